@@ -0,0 +1,147 @@
+package adapter_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// itemIDs IDs dos itens das seções, na ordem
+func itemIDs(sections []component.SectionData) []string {
+	var ids []string
+	for _, sec := range sections {
+		for _, item := range sec.Items {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+func listSpec() component.ComponentSpec {
+	return component.ComponentSpec{
+		Kind: "listpicker",
+		Text: &component.TextValue{Raw: "Escolha um horário"},
+		Meta: map[string]any{"sections": []component.SectionData{
+			{Title: "Manhã", Items: []component.ItemData{{ID: "m1", Title: "08:00"}, {ID: "m2", Title: "09:00"}, {ID: "m3", Title: "10:00"}, {ID: "m4", Title: "11:00"}}},
+			{Title: "Tarde", Items: []component.ItemData{{ID: "t1", Title: "14:00"}, {ID: "t2", Title: "15:00"}, {ID: "t3", Title: "16:00"}}},
+		}},
+	}
+}
+
+func TestListPaginationRule(t *testing.T) {
+	caps := adapter.NewCaps()
+	caps.SupportsListPicker = true
+	caps.MaxListItems = 4
+
+	rule := adapter.ListPaginationRule{MoreLabel: "Mais horários"}
+	spec := listSpec()
+	if !rule.Applies(spec, caps) {
+		t.Fatal("rule does not apply to 7 items with MaxListItems 4")
+	}
+
+	out := rule.Apply(spec, caps)
+	pages, _ := out.Meta["pages"].([]adapter.ListPage)
+	want := [][]string{
+		{"m1", "m2", "m3", adapter.PageNextPayload + ":2"},
+		{"m4", "t1", "t2", adapter.PageNextPayload + ":3"},
+		{"t3"},
+	}
+	if len(pages) != len(want) || out.Meta["page_count"] != 3 {
+		t.Fatalf("pages = %+v", pages)
+	}
+	for i, page := range pages {
+		if page.Number != i+1 || !reflect.DeepEqual(itemIDs(page.Sections), want[i]) {
+			t.Errorf("page %d = %+v", i+1, page)
+		}
+	}
+
+	// Seções são repetidas na página em que continuam; a primeira página vira o spec exibido
+	if titles := []string{pages[1].Sections[0].Title, pages[1].Sections[1].Title}; !reflect.DeepEqual(titles, []string{"Manhã", "Tarde"}) {
+		t.Errorf("page 2 sections = %v", titles)
+	}
+	for _, page := range pages {
+		for _, sec := range page.Sections {
+			if sec.Title == "" {
+				t.Errorf("page %d has an untitled section: %+v", page.Number, sec)
+			}
+		}
+	}
+	if nav := pages[0].Sections[len(pages[0].Sections)-1]; nav.Title != "Mais opções" || nav.Items[0].Title != "Mais horários" {
+		t.Errorf("nav section = %+v", nav)
+	}
+	if !reflect.DeepEqual(out.Meta["sections"], pages[0].Sections) {
+		t.Errorf("sections = %+v", out.Meta["sections"])
+	}
+	if _, ok := spec.Meta["pages"]; ok {
+		t.Error("Apply changed the original spec meta")
+	}
+
+	// Não se aplica quando cabe numa página ou o canal não tem listas
+	caps.MaxListItems = 7
+	if rule.Applies(spec, caps) {
+		t.Error("rule applies to a list that fits")
+	}
+	caps.MaxListItems, caps.SupportsListPicker = 4, false
+	if rule.Applies(spec, caps) {
+		t.Error("rule applies on a channel without list support")
+	}
+}
+
+func TestDegraderListToText(t *testing.T) {
+	spec, applied := adapter.DefaultDegrader().Degrade(listSpec(), adapter.NewCaps())
+	if !reflect.DeepEqual(applied, []io.Degradation{{Rule: "listpicker_to_text", From: "listpicker", To: "message"}}) {
+		t.Errorf("applied = %+v", applied)
+	}
+	if spec.Kind != "message" || spec.Meta["reply_mode"] != "numeric" || spec.Meta["degraded_from"] != "listpicker" {
+		t.Errorf("spec = %+v", spec)
+	}
+	if want := "Escolha um horário\n\n1. 08:00\n2. 09:00\n3. 10:00\n4. 11:00\n5. 14:00\n6. 15:00\n7. 16:00"; spec.Text.Raw != want {
+		t.Errorf("text = %q", spec.Text.Raw)
+	}
+}
+
+func TestResolveChoice(t *testing.T) {
+	typed := map[string]any{"choices": []adapter.Choice{
+		{Number: 1, Label: "Corte - R$ 50", Payload: "cut"},
+		{Number: 2, Label: "Barba\n   Com toalha quente", Payload: "beard"},
+	}}
+
+	// O plano compilado chega como JSON decodificado
+	raw, err := json.Marshal(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		reply   string
+		payload string
+		ok      bool
+	}{
+		{"1", "cut", true},
+		{" 2) ", "beard", true},
+		{"2.", "beard", true},
+		{"barba", "beard", true},
+		{"CORTE - r$ 50", "cut", true},
+		{"3", "", false},
+		{"com toalha quente", "", false},
+		{"", "", false},
+	}
+	for _, meta := range []map[string]any{typed, decoded} {
+		for _, c := range cases {
+			if payload, ok := adapter.ResolveChoice(meta, c.reply); payload != c.payload || ok != c.ok {
+				t.Errorf("ResolveChoice(%T, %q) = %q, %v", meta["choices"], c.reply, payload, ok)
+			}
+		}
+	}
+	if _, ok := adapter.ResolveChoice(nil, "1"); ok {
+		t.Error("ResolveChoice without choices matched")
+	}
+}
@@ -1,5 +1,7 @@
 package adapter
 
+import "time"
+
 // Capabilities descreve as capacidades e limitações de um canal específico
 // Serve como fonte de verdade para validação de componentes
 type Capabilities struct {
@@ -10,6 +12,7 @@ type Capabilities struct {
 	ButtonKinds        map[string]bool // Tipos de botão suportados {"reply":true,"url":true,"call":true}
 	SupportsCarousel   bool            // Suporte a carrosséis de cards
 	SupportsListPicker bool            // Suporte a listas de seleção
+	ServiceWindow      time.Duration   // Janela para mensagens livres após a última mensagem do usuário (0 = sem janela)
 
 	// Limitações específicas do WhatsApp
 	MaxListItems      int // Máximo de itens por lista/seção
@@ -40,3 +43,31 @@ func NewCaps() Capabilities {
 		MaxHeaderLen:      40,
 	}
 }
+
+// Supports verifica se o canal suporta nativamente um tipo de componente e os tipos de botão usados
+// Retorna o motivo quando não suporta (vazio quando suportado); componentes sem suporte
+// nativo ainda podem ser entregues degradados (ver Degrader.Degrades)
+func (c Capabilities) Supports(kind string, buttonKinds []string) (bool, string) {
+	switch kind {
+	case "carousel":
+		if !c.SupportsCarousel {
+			return false, "carousel not supported"
+		}
+	case "listpicker", "menu":
+		if !c.SupportsListPicker {
+			return false, "list picker not supported"
+		}
+	case "hsm_trigger":
+		if !c.SupportsHSM {
+			return false, "HSM not supported"
+		}
+	}
+
+	for _, k := range buttonKinds {
+		if !c.ButtonKinds[k] {
+			return false, "button kind '" + k + "' not supported"
+		}
+	}
+
+	return true, ""
+}
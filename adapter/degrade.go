@@ -0,0 +1,362 @@
+package adapter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// PageNextPayload payload do item "ver mais" inserido nas páginas de listas paginadas
+const PageNextPayload = "__next_page"
+
+// PagePrevPayload payload do item "voltar" das listas paginadas em ambos os sentidos
+const PagePrevPayload = "__prev_page"
+
+// Choice opção numerada de um componente degradado para texto ("1. Consulta")
+// O runtime mapeia a resposta numérica do usuário de volta para o Payload original
+type Choice struct {
+	Number  int    `json:"number"`  // Número exibido ao usuário (1..N)
+	Label   string `json:"label"`   // Texto exibido
+	Payload string `json:"payload"` // Payload/ID original (card.id, item.id)
+}
+
+// ListPage página de uma lista paginada
+type ListPage struct {
+	Number   int                     `json:"number"`   // Número da página (1..N)
+	Sections []component.SectionData `json:"sections"` // Seções/itens da página
+}
+
+// DegradationRule regra declarativa de degradação para componentes que o canal não suporta
+type DegradationRule interface {
+	Name() string                                                                  // Nome da regra (registrado no plano)
+	Applies(spec component.ComponentSpec, caps Capabilities) bool                  // Se a regra se aplica ao spec no canal
+	Apply(spec component.ComponentSpec, caps Capabilities) component.ComponentSpec // Gera o spec degradado
+}
+
+// Degrader aplica regras de degradação em ordem antes do Transform do adapter
+type Degrader struct{ rules []DegradationRule }
+
+// NewDegrader cria um degrader com as regras informadas (aplicadas em ordem)
+func NewDegrader(rules ...DegradationRule) *Degrader { return &Degrader{rules: rules} }
+
+// DefaultDegrader regras padrão: carousel e listpicker em texto numerado, listas paginadas e botões sem suporte
+func DefaultDegrader() *Degrader {
+	return NewDegrader(
+		CarouselToTextRule{},
+		ListPickerToTextRule{},
+		ListPaginationRule{},
+		UnsupportedButtonsRule{},
+	)
+}
+
+// Degrading interface opcional para adapters que declaram regras próprias
+type Degrading interface {
+	Degrader() *Degrader
+}
+
+// DegraderFor retorna as regras do adapter ou as regras padrão
+func DegraderFor(a Adapter) *Degrader {
+	if d, ok := a.(Degrading); ok && d.Degrader() != nil {
+		return d.Degrader()
+	}
+	return DefaultDegrader()
+}
+
+// Register adiciona uma regra ao final da lista
+func (d *Degrader) Register(rule DegradationRule) { d.rules = append(d.rules, rule) }
+
+// Degrades indica se alguma regra se aplica ao spec no canal (o componente é
+// entregue degradado em vez de rejeitado)
+func (d *Degrader) Degrades(spec component.ComponentSpec, caps Capabilities) bool {
+	for _, rule := range d.rules {
+		if rule.Applies(spec, caps) {
+			return true
+		}
+	}
+	return false
+}
+
+// Degrade aplica as regras que se aplicam ao spec e retorna as degradações realizadas
+func (d *Degrader) Degrade(spec component.ComponentSpec, caps Capabilities) (component.ComponentSpec, []io.Degradation) {
+	var applied []io.Degradation
+	for _, rule := range d.rules {
+		if !rule.Applies(spec, caps) {
+			continue
+		}
+		from := spec.Kind
+		spec = rule.Apply(spec, caps)
+		applied = append(applied, io.Degradation{Rule: rule.Name(), From: from, To: spec.Kind})
+	}
+	return spec, applied
+}
+
+// CarouselToTextRule converte carrossel em lista numerada de texto quando o canal não suporta carrossel
+type CarouselToTextRule struct{}
+
+func (CarouselToTextRule) Name() string { return "carousel_to_text" }
+
+func (CarouselToTextRule) Applies(spec component.ComponentSpec, caps Capabilities) bool {
+	return spec.Kind == "carousel" && !caps.SupportsCarousel
+}
+
+func (CarouselToTextRule) Apply(spec component.ComponentSpec, _ Capabilities) component.ComponentSpec {
+	cards, _ := spec.Meta["cards"].([]component.CardData)
+	choices := make([]Choice, 0, len(cards))
+	for i, card := range cards {
+		label := card.Title
+		if card.Price != "" {
+			label += " - " + card.Price
+		}
+		if card.Description != "" {
+			label += "\n   " + card.Description
+		}
+		choices = append(choices, Choice{Number: i + 1, Label: label, Payload: card.ID})
+	}
+	return numberedText(spec, choices)
+}
+
+// ListPickerToTextRule converte lista de seleção em lista numerada quando o canal não suporta listas
+type ListPickerToTextRule struct{}
+
+func (ListPickerToTextRule) Name() string { return "listpicker_to_text" }
+
+func (ListPickerToTextRule) Applies(spec component.ComponentSpec, caps Capabilities) bool {
+	return spec.Kind == "listpicker" && !caps.SupportsListPicker
+}
+
+func (ListPickerToTextRule) Apply(spec component.ComponentSpec, _ Capabilities) component.ComponentSpec {
+	sections, _ := spec.Meta["sections"].([]component.SectionData)
+	var choices []Choice
+	for _, sec := range sections {
+		for _, item := range sec.Items {
+			label := item.Title
+			if item.Description != "" {
+				label += " - " + item.Description
+			}
+			choices = append(choices, Choice{Number: len(choices) + 1, Label: label, Payload: item.ID})
+		}
+	}
+	return numberedText(spec, choices)
+}
+
+// ListPaginationRule divide listas com mais itens que MaxListItems em páginas
+// Cada página (exceto a última) reserva o último item para "ver mais" (PageNextPayload),
+// numa seção própria com título (o WhatsApp exige título em listas com mais de uma seção)
+type ListPaginationRule struct {
+	MoreLabel string // Texto do item "ver mais" (padrão: "Ver mais opções")
+	NavTitle  string // Título da seção do item "ver mais" (padrão: "Mais opções")
+}
+
+func (ListPaginationRule) Name() string { return "listpicker_paginate" }
+
+func (ListPaginationRule) Applies(spec component.ComponentSpec, caps Capabilities) bool {
+	if spec.Kind != "listpicker" || !caps.SupportsListPicker || caps.MaxListItems < 2 {
+		return false
+	}
+	sections, _ := spec.Meta["sections"].([]component.SectionData)
+	total := 0
+	for _, sec := range sections {
+		total += len(sec.Items)
+	}
+	return total > caps.MaxListItems
+}
+
+func (r ListPaginationRule) Apply(spec component.ComponentSpec, caps Capabilities) component.ComponentSpec {
+	moreLabel := r.MoreLabel
+	if moreLabel == "" {
+		moreLabel = "Ver mais opções"
+	}
+	navTitle := r.NavTitle
+	if navTitle == "" {
+		navTitle = "Mais opções"
+	}
+
+	sections, _ := spec.Meta["sections"].([]component.SectionData)
+	perPage := caps.MaxListItems - 1 // reserva espaço para o item "ver mais"
+
+	var pages []ListPage
+	current := ListPage{Number: 1}
+	count := 0
+	flush := func() {
+		pages = append(pages, current)
+		current = ListPage{Number: len(pages) + 1}
+		count = 0
+	}
+
+	for _, sec := range sections {
+		for _, item := range sec.Items {
+			if count == perPage {
+				flush()
+			}
+			if n := len(current.Sections); n == 0 || current.Sections[n-1].Title != sec.Title {
+				current.Sections = append(current.Sections, component.SectionData{Title: sec.Title})
+			}
+			last := &current.Sections[len(current.Sections)-1]
+			last.Items = append(last.Items, item)
+			count++
+		}
+	}
+	if count > 0 {
+		flush()
+	}
+
+	// Item "ver mais" em todas as páginas exceto a última
+	for i := 0; i < len(pages)-1; i++ {
+		pages[i].Sections = append(pages[i].Sections, component.SectionData{
+			Title: navTitle,
+			Items: []component.ItemData{{ID: fmt.Sprintf("%s:%d", PageNextPayload, i+2), Title: moreLabel}},
+		})
+	}
+
+	meta := cloneMeta(spec.Meta)
+	meta["sections"] = pages[0].Sections
+	meta["pages"] = pages
+	meta["page_count"] = len(pages)
+	spec.Meta = meta
+	return spec
+}
+
+// UnsupportedButtonsRule remove botões de tipos não suportados e os anexa ao texto
+// (ex.: botão url vira "Site: https://..."). Sem botões restantes, o spec vira message
+type UnsupportedButtonsRule struct{}
+
+func (UnsupportedButtonsRule) Name() string { return "buttons_unsupported_kind" }
+
+func (UnsupportedButtonsRule) Applies(spec component.ComponentSpec, caps Capabilities) bool {
+	for _, b := range spec.Buttons {
+		if !caps.ButtonKinds[b.Kind] {
+			return true
+		}
+	}
+	return false
+}
+
+func (UnsupportedButtonsRule) Apply(spec component.ComponentSpec, caps Capabilities) component.ComponentSpec {
+	data, _ := spec.Meta["buttons"].([]component.ButtonData)
+
+	var kept []component.Button
+	var keptData []component.ButtonData
+	var lines []string
+	for i, b := range spec.Buttons {
+		if caps.ButtonKinds[b.Kind] {
+			kept = append(kept, b)
+			if i < len(data) {
+				keptData = append(keptData, data[i])
+			}
+			continue
+		}
+		target := ""
+		if i < len(data) {
+			target = data[i].URL
+			if target == "" {
+				target = data[i].Payload
+			}
+		}
+		lines = append(lines, strings.TrimSpace(b.Label.Raw+": "+target))
+	}
+
+	spec.Text = appendText(spec.Text, strings.Join(lines, "\n"))
+	spec.Buttons = kept
+	meta := cloneMeta(spec.Meta)
+	if data != nil {
+		meta["buttons"] = keptData
+	}
+	spec.Meta = meta
+	if len(kept) == 0 {
+		spec.Kind = "message"
+	}
+	return spec
+}
+
+// ResolveChoice mapeia a resposta do usuário ("2", "2.", " 2) ", ou o próprio label) para o payload original
+// Aceita Meta com []Choice (compilação) ou []any decodificado do JSON do plano
+func ResolveChoice(meta map[string]any, reply string) (string, bool) {
+	choices := choicesFromMeta(meta)
+	reply = strings.TrimSpace(reply)
+
+	if n, err := strconv.Atoi(strings.TrimRight(reply, ".)- ")); err == nil {
+		for _, c := range choices {
+			if c.Number == n {
+				return c.Payload, true
+			}
+		}
+		return "", false
+	}
+
+	for _, c := range choices {
+		label, _, _ := strings.Cut(c.Label, "\n")
+		if strings.EqualFold(strings.TrimSpace(label), reply) {
+			return c.Payload, true
+		}
+	}
+	return "", false
+}
+
+// choicesFromMeta lê meta["choices"] nos formatos tipado e JSON
+func choicesFromMeta(meta map[string]any) []Choice {
+	switch v := meta["choices"].(type) {
+	case []Choice:
+		return v
+	case []any:
+		out := make([]Choice, 0, len(v))
+		for _, raw := range v {
+			m, _ := raw.(map[string]any)
+			n, _ := m["number"].(float64)
+			label, _ := m["label"].(string)
+			payload, _ := m["payload"].(string)
+			out = append(out, Choice{Number: int(n), Label: label, Payload: payload})
+		}
+		return out
+	}
+	return nil
+}
+
+// numberedText transforma o spec em message com opções numeradas e registra as choices para mapear a resposta
+func numberedText(spec component.ComponentSpec, choices []Choice) component.ComponentSpec {
+	lines := make([]string, 0, len(choices))
+	for _, c := range choices {
+		lines = append(lines, fmt.Sprintf("%d. %s", c.Number, c.Label))
+	}
+
+	meta := cloneMeta(spec.Meta)
+	meta["degraded_from"] = spec.Kind
+	meta["reply_mode"] = "numeric"
+	meta["choices"] = choices
+
+	spec.Kind = "message"
+	spec.Text = appendText(spec.Text, strings.Join(lines, "\n"))
+	spec.Buttons = nil
+	spec.Meta = meta
+	return spec
+}
+
+// appendText anexa um bloco ao texto do spec preservando metadados Liquid
+func appendText(t *component.TextValue, block string) *component.TextValue {
+	if block == "" {
+		return t
+	}
+	if t == nil {
+		return &component.TextValue{Raw: block}
+	}
+	cp := *t
+	cp.Raw = strings.TrimRight(cp.Raw, "\n") + "\n\n" + block
+	if len(t.Variants) > 0 {
+		cp.Variants = make(map[string]string, len(t.Variants))
+		for locale, v := range t.Variants {
+			cp.Variants[locale] = strings.TrimRight(v, "\n") + "\n\n" + block
+		}
+	}
+	return &cp
+}
+
+// cloneMeta copia o mapa de metadados para não alterar o spec original
+func cloneMeta(meta map[string]any) map[string]any {
+	out := make(map[string]any, len(meta)+3)
+	for k, v := range meta {
+		out[k] = v
+	}
+	return out
+}
@@ -21,6 +21,7 @@ func New() *WhatsApp {
 	c.MaxButtons = 3                                                          // Máximo 3 botões
 	c.ButtonKinds = map[string]bool{"reply": true, "url": true, "call": true} // Tipos suportados
 	c.SupportsListPicker = true                                               // Suporte a listas
+	c.ServiceWindow = ServiceWindow                                           // Fora da janela de 24h apenas templates (ver Window)
 
 	// Limitações específicas do WhatsApp para validação
 	c.MaxListItems = 10      // Máximo 10 itens por lista/seção
@@ -0,0 +1,57 @@
+package whatsapp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/adapter/whatsapp"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/hsm"
+)
+
+func TestWindowGuard(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	window := whatsapp.NewWindow(nil).WithClock(func() time.Time { return now })
+	text := component.ComponentSpec{Kind: "message", Text: &component.TextValue{Raw: "Olá"}}
+	template := component.ComponentSpec{Kind: "message", HSM: &hsm.HSMTemplate{Name: "lembrete"}}
+
+	if _, err := window.Guard(ctx, "s1", text); !errors.Is(err, whatsapp.ErrOutsideWindow) {
+		t.Fatalf("no inbound yet: err = %v", err)
+	}
+	if _, err := window.Guard(ctx, "s1", template); err != nil {
+		t.Fatalf("template refused: %v", err)
+	}
+
+	if err := window.Inbound(ctx, "s1"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(23 * time.Hour)
+	if got, err := window.Guard(ctx, "s1", text); err != nil || got.Text == nil {
+		t.Fatalf("inside window = %+v, %v", got, err)
+	}
+
+	now = now.Add(time.Hour)
+	open, closesAt, _ := window.Status(ctx, "s1")
+	if open || !closesAt.Equal(now) {
+		t.Errorf("status = %v, %s", open, closesAt)
+	}
+	if _, err := window.Guard(ctx, "s1", text); !errors.Is(err, whatsapp.ErrOutsideWindow) {
+		t.Errorf("closed window: err = %v", err)
+	}
+
+	converted, err := window.WithFallback(hsm.HSMTemplate{Name: "reabrir_conversa"}).Guard(ctx, "s1", text)
+	if err != nil || converted.HSM == nil || converted.HSM.Name != "reabrir_conversa" || converted.Meta["replaced_kind"] != "message" {
+		t.Errorf("fallback = %+v, %v", converted, err)
+	}
+
+	if _, err := window.Guard(ctx, "s1", component.ComponentSpec{Kind: "delay", Meta: map[string]any{"message": ""}}); err != nil {
+		t.Errorf("silent kind refused: %v", err)
+	}
+	typing := component.ComponentSpec{Kind: "delay", Meta: map[string]any{"duration": 3, "unit": "seconds", "message": "Só um instante..."}}
+	if _, err := window.Guard(ctx, "s1", typing); !errors.Is(err, whatsapp.ErrOutsideWindow) {
+		t.Errorf("delay with message: err = %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/hsm"
+)
+
+// ServiceWindow janela de atendimento: mensagens livres só até 24h após a
+// última mensagem do usuário; fora dela apenas templates (HSM)
+const ServiceWindow = 24 * time.Hour
+
+// ErrOutsideWindow envio livre recusado fora da janela de atendimento
+var ErrOutsideWindow = errors.New("whatsapp: free-form message outside the 24h customer service window")
+
+// Clock relógio injetável (time.Now por padrão)
+type Clock func() time.Time
+
+// silentKinds componentes que não enviam mensagem ao usuário
+// (delay só é silencioso sem message: ver DelaySendsMessage)
+var silentKinds = map[string]bool{
+	"delay":        true,
+	"global_start": true,
+	"subflow":      true,
+	"geo_resolve":  true,
+}
+
+// SilentKind indica se o componente não envia mensagem (não depende da janela)
+func SilentKind(kind string) bool {
+	return silentKinds[kind]
+}
+
+// DelaySendsMessage indica se o delay envia a mensagem opcional (prop message)
+// durante a espera; nesse caso o envio é livre e depende da janela
+func DelaySendsMessage(message any) bool {
+	text, _ := message.(string)
+	return strings.TrimSpace(text) != ""
+}
+
+// RequiresWindow indica se o spec é um envio livre (não template) e portanto
+// só pode sair dentro da janela de atendimento
+func RequiresWindow(spec component.ComponentSpec) bool {
+	if spec.Kind == "delay" && DelaySendsMessage(spec.Meta["message"]) {
+		return true
+	}
+	if spec.HSM != nil || spec.Kind == "hsm_trigger" || SilentKind(spec.Kind) {
+		return false
+	}
+	return spec.Meta["whatsapp_type"] != "template"
+}
+
+// WindowStore guarda o horário da última mensagem recebida de cada sessão
+type WindowStore interface {
+	LastInbound(ctx context.Context, sessionID string) (time.Time, bool, error)
+	SetLastInbound(ctx context.Context, sessionID string, at time.Time) error
+}
+
+// Window controla a janela de 24h por sessão
+// Inbound registra cada mensagem recebida; Guard deixa passar envios livres
+// dentro da janela e, fora dela, converte para o template de fallback (quando
+// configurado) ou recusa com ErrOutsideWindow
+type Window struct {
+	store    WindowStore
+	now      Clock
+	fallback *hsm.HSMTemplate
+}
+
+// NewWindow cria o controle de janela; store nil usa MemoryWindowStore
+func NewWindow(store WindowStore) *Window {
+	if store == nil {
+		store = NewMemoryWindowStore()
+	}
+	return &Window{store: store, now: time.Now}
+}
+
+// WithClock define o relógio
+func (w *Window) WithClock(now Clock) *Window {
+	cp := *w
+	cp.now = now
+	return &cp
+}
+
+// WithFallback define o template enviado no lugar de mensagens livres fora da janela
+func (w *Window) WithFallback(template hsm.HSMTemplate) *Window {
+	cp := *w
+	cp.fallback = &template
+	return &cp
+}
+
+// Inbound registra mensagem recebida do usuário (reabre a janela)
+func (w *Window) Inbound(ctx context.Context, sessionID string) error {
+	if err := w.store.SetLastInbound(ctx, sessionID, w.now()); err != nil {
+		return fmt.Errorf("whatsapp window %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// Status indica se a janela da sessão está aberta e quando fecha (zero se a
+// sessão nunca enviou mensagem)
+func (w *Window) Status(ctx context.Context, sessionID string) (bool, time.Time, error) {
+	last, ok, err := w.store.LastInbound(ctx, sessionID)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("whatsapp window %s: %w", sessionID, err)
+	}
+	if !ok {
+		return false, time.Time{}, nil
+	}
+	closesAt := last.Add(ServiceWindow)
+	return w.now().Before(closesAt), closesAt, nil
+}
+
+// Guard aplica a janela ao envio: templates e componentes silenciosos (delay
+// sem message) passam sempre; envios livres passam com a janela aberta e, com ela fechada, viram
+// o template de fallback (Meta window_fallback e replaced_kind) ou ErrOutsideWindow
+func (w *Window) Guard(ctx context.Context, sessionID string, spec component.ComponentSpec) (component.ComponentSpec, error) {
+	if !RequiresWindow(spec) {
+		return spec, nil
+	}
+	open, closedAt, err := w.Status(ctx, sessionID)
+	if err != nil {
+		return component.ComponentSpec{}, err
+	}
+	if open {
+		return spec, nil
+	}
+
+	if w.fallback == nil {
+		if closedAt.IsZero() {
+			return component.ComponentSpec{}, fmt.Errorf("%w: session %s has no inbound message", ErrOutsideWindow, sessionID)
+		}
+		return component.ComponentSpec{}, fmt.Errorf("%w: session %s window closed at %s",
+			ErrOutsideWindow, sessionID, closedAt.Format(time.RFC3339))
+	}
+	template := *w.fallback
+	return component.ComponentSpec{
+		Kind: "message",
+		HSM:  &template,
+		Meta: map[string]any{
+			"window_fallback": true,
+			"replaced_kind":   spec.Kind,
+		},
+	}, nil
+}
+
+// MemoryWindowStore WindowStore em memória (um processo; não persiste entre reinícios)
+type MemoryWindowStore struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+var _ WindowStore = (*MemoryWindowStore)(nil)
+
+// NewMemoryWindowStore cria o armazenamento em memória
+func NewMemoryWindowStore() *MemoryWindowStore {
+	return &MemoryWindowStore{last: map[string]time.Time{}}
+}
+
+func (m *MemoryWindowStore) LastInbound(_ context.Context, sessionID string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	at, ok := m.last[sessionID]
+	return at, ok, nil
+}
+
+// SetLastInbound mantém o horário mais recente (entregas fora de ordem não fecham a janela)
+func (m *MemoryWindowStore) SetLastInbound(_ context.Context, sessionID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if at.After(m.last[sessionID]) {
+		m.last[sessionID] = at
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package cart
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// Erros do carrinho
+var (
+	ErrInvalidItem      = errors.New("cart: invalid item")
+	ErrItemNotFound     = errors.New("cart: item not found")
+	ErrCurrencyMismatch = errors.New("cart: currency mismatch")
+)
+
+// Item linha do carrinho
+type Item struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	UnitPrice Money          `json:"unit_price"` // Em unidades menores da moeda
+	Quantity  int            `json:"quantity"`
+	Metadata  map[string]any `json:"metadata,omitempty"` // Demais campos do item (sku, imagem...)
+}
+
+// Amount valor da linha (preço × quantidade)
+func (i Item) Amount() Money { return i.UnitPrice * Money(i.Quantity) }
+
+// Discount desconto aplicado ao carrinho: valor fixo e/ou percentual
+type Discount struct {
+	Code      string `json:"code,omitempty"`
+	Amount    Money  `json:"amount,omitempty"`     // Valor fixo em unidades menores
+	PercentBP int    `json:"percent_bp,omitempty"` // Percentual em pontos-base (1050 = 10,50%)
+}
+
+// Cart carrinho de um usuário
+type Cart struct {
+	Currency string    `json:"currency"`
+	Items    []Item    `json:"items"`
+	Discount *Discount `json:"discount,omitempty"`
+}
+
+// New carrinho vazio na moeda (código ISO 4217)
+func New(currency string) (*Cart, error) {
+	c, err := LookupCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	return &Cart{Currency: c.Code, Items: []Item{}}, nil
+}
+
+// ParseItem converte o item das props do order_cart ({"id", "name", "price", "quantity"})
+// "sku" e "title" são aceitos como alternativas a id e name; quantidade padrão 1
+// Sem name e price, Name e UnitPrice ficam zerados (Add usa o id como nome da linha nova)
+func ParseItem(props map[string]any, currency Currency) (Item, error) {
+	item := Item{ID: propString(props, "id", "sku"), Name: propString(props, "name", "title"), Quantity: 1}
+	if item.ID == "" {
+		return Item{}, fmt.Errorf("%w: missing id", ErrInvalidItem)
+	}
+
+	if price, ok := props["price"]; ok {
+		amount, err := currency.ParseAmount(price)
+		if err != nil {
+			return Item{}, fmt.Errorf("%w: price: %w", ErrInvalidItem, err)
+		}
+		item.UnitPrice = amount
+	}
+
+	switch q := props["quantity"].(type) {
+	case nil:
+	case int:
+		item.Quantity = q
+	case float64:
+		if q != float64(int(q)) {
+			return Item{}, fmt.Errorf("%w: fractional quantity %v", ErrInvalidItem, q)
+		}
+		item.Quantity = int(q)
+	case string:
+		n, err := strconv.Atoi(q)
+		if err != nil {
+			return Item{}, fmt.Errorf("%w: quantity %q", ErrInvalidItem, q)
+		}
+		item.Quantity = n
+	default:
+		return Item{}, fmt.Errorf("%w: quantity %v", ErrInvalidItem, q)
+	}
+	if item.Quantity <= 0 {
+		return Item{}, fmt.Errorf("%w: quantity must be positive", ErrInvalidItem)
+	}
+
+	for k, v := range props {
+		switch k {
+		case "id", "sku", "name", "title", "price", "quantity":
+		default:
+			if item.Metadata == nil {
+				item.Metadata = map[string]any{}
+			}
+			item.Metadata[k] = v
+		}
+	}
+	return item, nil
+}
+
+// propString texto da primeira chave preenchida (ids numéricos do JSON viram texto)
+func propString(props map[string]any, keys ...string) string {
+	for _, k := range keys {
+		switch v := props[k].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			return strconv.Itoa(v)
+		}
+	}
+	return ""
+}
+
+// Add adiciona o item; se o ID já existe soma a quantidade e atualiza nome e
+// preço apenas quando informados (vazio e zero mantêm os da linha)
+func (c *Cart) Add(item Item) error {
+	if item.ID == "" || item.Quantity <= 0 || item.UnitPrice < 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidItem, item.ID)
+	}
+	if i := c.index(item.ID); i >= 0 {
+		existing := &c.Items[i]
+		existing.Quantity += item.Quantity
+		if item.Name != "" {
+			existing.Name = item.Name
+		}
+		if item.UnitPrice != 0 {
+			existing.UnitPrice = item.UnitPrice
+		}
+		if item.Metadata != nil {
+			existing.Metadata = item.Metadata
+		}
+		return nil
+	}
+	if item.Name == "" {
+		item.Name = item.ID
+	}
+	c.Items = append(c.Items, item)
+	return nil
+}
+
+// Remove retira quantity unidades do item (quantity <= 0 ou maior que a atual remove a linha)
+func (c *Cart) Remove(id string, quantity int) error {
+	i := c.index(id)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrItemNotFound, id)
+	}
+	if quantity > 0 && quantity < c.Items[i].Quantity {
+		c.Items[i].Quantity -= quantity
+		return nil
+	}
+	c.Items = slices.Delete(c.Items, i, i+1)
+	return nil
+}
+
+// Clear esvazia o carrinho (inclusive o desconto)
+func (c *Cart) Clear() {
+	c.Items, c.Discount = []Item{}, nil
+}
+
+// IsEmpty indica carrinho sem itens
+func (c *Cart) IsEmpty() bool { return len(c.Items) == 0 }
+
+// Count total de unidades no carrinho
+func (c *Cart) Count() int {
+	n := 0
+	for _, item := range c.Items {
+		n += item.Quantity
+	}
+	return n
+}
+
+// Subtotal soma das linhas
+func (c *Cart) Subtotal() Money {
+	var total Money
+	for _, item := range c.Items {
+		total += item.Amount()
+	}
+	return total
+}
+
+// DiscountAmount desconto efetivo: percentual (arredondado metade para cima) mais o
+// valor fixo, limitado ao subtotal
+func (c *Cart) DiscountAmount() Money {
+	if c.Discount == nil {
+		return 0
+	}
+	subtotal := c.Subtotal()
+	amount := (subtotal*Money(c.Discount.PercentBP) + 5000) / 10000
+	return min(amount+c.Discount.Amount, subtotal)
+}
+
+// Total subtotal menos desconto
+func (c *Cart) Total() Money { return c.Subtotal() - c.DiscountAmount() }
+
+// ApplyDiscount define o desconto do carrinho (substitui o anterior)
+func (c *Cart) ApplyDiscount(d Discount) error {
+	if d.Amount < 0 || d.PercentBP < 0 || d.PercentBP > 10000 {
+		return fmt.Errorf("cart: invalid discount %+v", d)
+	}
+	c.Discount = &d
+	return nil
+}
+
+func (c *Cart) index(id string) int {
+	return slices.IndexFunc(c.Items, func(item Item) bool { return item.ID == id })
+}
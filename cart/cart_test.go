@@ -0,0 +1,147 @@
+package cart_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/cart"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/persistence"
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+type memoryStore map[string]string
+
+func (m memoryStore) Get(_ context.Context, scope persistence.Scope, key string) (string, error) {
+	v, ok := m[string(scope)+":"+key]
+	if !ok {
+		return "", persistence.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func (m memoryStore) Set(_ context.Context, scope persistence.Scope, key, value string) error {
+	m[string(scope)+":"+key] = value
+	return nil
+}
+
+func TestParseAmount(t *testing.T) {
+	brl, _ := cart.LookupCurrency("brl")
+	jpy, _ := cart.LookupCurrency("JPY")
+	cases := []struct {
+		currency cart.Currency
+		in       any
+		want     cart.Money
+	}{
+		{brl, 19.9, 1990},
+		{brl, 0.1 + 0.2, 30}, // 0.30000000000000004
+		{brl, "1.234,56", 123456},
+		{brl, "10.005", 1001},
+		{brl, 7, 700},
+		{jpy, "1500.4", 1500},
+	}
+	for _, c := range cases {
+		if got, err := c.currency.ParseAmount(c.in); err != nil || got != c.want {
+			t.Errorf("%s ParseAmount(%v) = %d, %v; want %d", c.currency.Code, c.in, got, err, c.want)
+		}
+	}
+	if _, err := brl.ParseAmount("-5"); !errors.Is(err, cart.ErrInvalidAmount) {
+		t.Errorf("negative amount err = %v", err)
+	}
+	if got := brl.Format(123456); got != "R$ 1.234,56" {
+		t.Errorf("Format = %q", got)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+	store := memoryStore{}
+	svc := cart.NewService(store)
+
+	run := func(props map[string]any) cart.Result {
+		t.Helper()
+		c, err := component.NewOrderCartFactory(nil).New("cart", props)
+		if err != nil {
+			t.Fatal(err)
+		}
+		spec, _ := c.Spec(ctx, runtime.Context{})
+		result, err := svc.Execute(ctx, cart.ConfigFromSpec(spec))
+		if err != nil {
+			t.Fatalf("%v: %v", props, err)
+		}
+		return result
+	}
+
+	run(map[string]any{"item": map[string]any{"id": "corte", "name": "Corte", "price": 39.9}})
+	run(map[string]any{"item": map[string]any{"id": "corte", "name": "Corte", "price": 39.9, "quantity": float64(2)}})
+	added := run(map[string]any{"item": map[string]any{"id": "barba", "name": "Barba", "price": "25,00"}})
+	if added.Output != cart.OutputAdded || added.Cart.Count() != 4 || added.Cart.Subtotal() != 3*3990+2500 {
+		t.Fatalf("added = %+v", added.Cart)
+	}
+
+	removed := run(map[string]any{"action": "remove", "item": map[string]any{"id": "corte", "quantity": float64(1)}})
+	if removed.Output != cart.OutputRemoved || removed.Cart.Items[0].Quantity != 2 {
+		t.Fatalf("removed = %+v", removed.Cart)
+	}
+
+	c, _ := svc.Load(ctx, "BRL")
+	_ = c.ApplyDiscount(cart.Discount{Code: "PROMO10", PercentBP: 1000})
+	_ = svc.Save(ctx, c)
+
+	checkout := run(map[string]any{"mode": "checkout", "checkout_provider": "mercadopago"})
+	p := checkout.Checkout
+	if checkout.Output != cart.OutputCheckoutReady || p.Provider != "mercadopago" || p.Subtotal != 10480 || p.Discount != 1048 || p.Total != 9432 || p.TotalDecimal != "94.32" {
+		t.Fatalf("checkout = %+v", p)
+	}
+	want := "Seu carrinho:\n2x Corte — R$ 79,80\n1x Barba — R$ 25,00\nSubtotal: R$ 104,80\nDesconto (PROMO10): -R$ 10,48\nTotal: R$ 94,32"
+	if checkout.Summary != want {
+		t.Errorf("summary =\n%s", checkout.Summary)
+	}
+
+	if cleared := run(map[string]any{"action": "clear"}); cleared.Output != cart.OutputCleared {
+		t.Errorf("cleared output = %q", cleared.Output)
+	}
+	if empty := run(map[string]any{"mode": "checkout"}); empty.Output != cart.OutputEmpty || empty.Checkout != nil {
+		t.Errorf("empty checkout = %+v", empty)
+	}
+
+	missing := cart.DefaultConfig()
+	missing.Action, missing.Item = cart.ActionRemove, map[string]any{"id": "manicure"}
+	if result, err := svc.Execute(ctx, missing); result.Output != cart.OutputError || !errors.Is(err, cart.ErrItemNotFound) {
+		t.Errorf("remove missing = %q, %v", result.Output, err)
+	}
+}
+
+func TestAddMergeAndNumericIDs(t *testing.T) {
+	ctx := context.Background()
+	svc := cart.NewService(memoryStore{})
+	exec := func(action string, item map[string]any) cart.Result {
+		t.Helper()
+		cfg := cart.DefaultConfig()
+		cfg.Action, cfg.Item = action, item
+		result, err := svc.Execute(ctx, cfg)
+		if err != nil {
+			t.Fatalf("%s %v: %v", action, item, err)
+		}
+		return result
+	}
+
+	exec(cart.ActionAdd, map[string]any{"id": float64(123), "name": "Pizza", "price": "30,00"})
+	// "Mais 1" sem nome nem preço mantém os da linha
+	more := exec(cart.ActionAdd, map[string]any{"id": float64(123)})
+	if item := more.Cart.Items[0]; len(more.Cart.Items) != 1 || item.ID != "123" || item.Name != "Pizza" || item.UnitPrice != 3000 || item.Quantity != 2 {
+		t.Fatalf("merged item = %+v", more.Cart.Items)
+	}
+	repriced := exec(cart.ActionAdd, map[string]any{"id": "123", "name": "Pizza grande", "price": 45})
+	if item := repriced.Cart.Items[0]; item.Name != "Pizza grande" || item.UnitPrice != 4500 || item.Quantity != 3 {
+		t.Errorf("repriced item = %+v", item)
+	}
+	if bare := exec(cart.ActionAdd, map[string]any{"sku": "agua"}); bare.Cart.Items[1].Name != "agua" {
+		t.Errorf("new item without name = %+v", bare.Cart.Items[1])
+	}
+
+	if removed := exec(cart.ActionRemove, map[string]any{"id": float64(123)}); removed.Output != cart.OutputRemoved || len(removed.Cart.Items) != 1 {
+		t.Errorf("remove numeric id = %+v", removed.Cart)
+	}
+}
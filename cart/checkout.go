@@ -0,0 +1,95 @@
+package cart
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// CheckoutItem linha do payload de checkout
+type CheckoutItem struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Money  `json:"unit_price"` // Unidades menores da moeda
+	Amount    Money  `json:"amount"`
+}
+
+// CheckoutPayload dados enviados ao checkout_provider
+// Valores em unidades menores (padrão de Stripe/Mercado Pago) e o total também
+// em decimal; Reference identifica o conteúdo do carrinho (mesmo carrinho, mesma referência)
+type CheckoutPayload struct {
+	Provider     string         `json:"provider"`
+	Currency     string         `json:"currency"`
+	Items        []CheckoutItem `json:"items"`
+	Subtotal     Money          `json:"subtotal"`
+	Discount     Money          `json:"discount"`
+	DiscountCode string         `json:"discount_code,omitempty"`
+	Total        Money          `json:"total"`
+	TotalDecimal string         `json:"total_decimal"` // "103.50"
+	Reference    string         `json:"reference"`
+}
+
+// Checkout monta o payload de checkout do carrinho para o provider
+func Checkout(c *Cart, provider string) (CheckoutPayload, error) {
+	currency, err := LookupCurrency(c.Currency)
+	if err != nil {
+		return CheckoutPayload{}, err
+	}
+
+	p := CheckoutPayload{
+		Provider:     provider,
+		Currency:     currency.Code,
+		Items:        make([]CheckoutItem, 0, len(c.Items)),
+		Subtotal:     c.Subtotal(),
+		Discount:     c.DiscountAmount(),
+		Total:        c.Total(),
+		TotalDecimal: currency.Decimal(c.Total()),
+	}
+	if c.Discount != nil {
+		p.DiscountCode = c.Discount.Code
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%d|%d", provider, currency.Code, p.Discount, p.Total)
+	for _, item := range c.Items {
+		p.Items = append(p.Items, CheckoutItem{ID: item.ID, Name: item.Name, Quantity: item.Quantity, UnitPrice: item.UnitPrice, Amount: item.Amount()})
+		fmt.Fprintf(h, "|%s:%d:%d", item.ID, item.Quantity, item.UnitPrice)
+	}
+	p.Reference = hex.EncodeToString(h.Sum(nil))[:16]
+	return p, nil
+}
+
+// Summary mensagem de resumo para o canal (show_summary_in_channel):
+//
+//	Seu carrinho:
+//	2x Corte — R$ 80,00
+//	Subtotal: R$ 80,00
+//	Desconto (PROMO10): -R$ 8,00
+//	Total: R$ 72,00
+func Summary(c *Cart) (string, error) {
+	currency, err := LookupCurrency(c.Currency)
+	if err != nil {
+		return "", err
+	}
+	if c.IsEmpty() {
+		return "Seu carrinho está vazio.", nil
+	}
+
+	var b strings.Builder
+	b.WriteString("Seu carrinho:\n")
+	for _, item := range c.Items {
+		fmt.Fprintf(&b, "%dx %s — %s\n", item.Quantity, item.Name, currency.Format(item.Amount()))
+	}
+	fmt.Fprintf(&b, "Subtotal: %s\n", currency.Format(c.Subtotal()))
+	if discount := c.DiscountAmount(); discount > 0 {
+		label := "Desconto"
+		if c.Discount.Code != "" {
+			label += " (" + c.Discount.Code + ")"
+		}
+		fmt.Fprintf(&b, "%s: -%s\n", label, currency.Format(discount))
+	}
+	fmt.Fprintf(&b, "Total: %s", currency.Format(c.Total()))
+	return b.String(), nil
+}
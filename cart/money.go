@@ -0,0 +1,143 @@
+// Package cart implementa o carrinho do componente order_cart: itens com
+// quantidades somadas, totais em unidades menores da moeda (sem float),
+// persistência no escopo state, resumo para o canal e payload de checkout
+package cart
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Erros de valores monetários
+var (
+	ErrUnknownCurrency = errors.New("cart: unknown currency")
+	ErrInvalidAmount   = errors.New("cart: invalid amount")
+)
+
+// Money valor em unidades menores da moeda (centavos no BRL)
+type Money int64
+
+// Currency moeda suportada: casas decimais e símbolo exibido
+type Currency struct {
+	Code     string `json:"code"`
+	Exponent int    `json:"exponent"` // Casas decimais (2 no BRL, 0 no JPY)
+	Symbol   string `json:"symbol"`
+}
+
+// currencies moedas aceitas na prop currency
+var currencies = map[string]Currency{
+	"BRL": {"BRL", 2, "R$"},
+	"USD": {"USD", 2, "US$"},
+	"EUR": {"EUR", 2, "€"},
+	"GBP": {"GBP", 2, "£"},
+	"ARS": {"ARS", 2, "AR$"},
+	"MXN": {"MXN", 2, "MX$"},
+	"CLP": {"CLP", 0, "CLP$"},
+	"JPY": {"JPY", 0, "¥"},
+}
+
+// LookupCurrency busca a moeda pelo código ISO 4217 (maiúsculas ou minúsculas)
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// ParseAmount converte um valor decimal para unidades menores da moeda
+// Aceita número (int/float64 do JSON) ou texto "19.90", "19,90" e "1.234,56";
+// casas além do expoente são arredondadas (metade para cima)
+func (c Currency) ParseAmount(value any) (Money, error) {
+	var s string
+	switch v := value.(type) {
+	case int:
+		s = strconv.Itoa(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, v)
+		}
+		s = strconv.FormatFloat(v, 'f', -1, 64) // Representação decimal mais curta: 19.9 → "19.9"
+	case string:
+		s = strings.TrimSpace(v)
+		if strings.Contains(s, ",") { // Formato pt-BR: ponto de milhar, vírgula decimal
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		}
+	default:
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, value)
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || strings.ContainsAny(whole, "+-") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	for _, r := range frac {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+
+	frac += strings.Repeat("0", c.Exponent)
+	minor, _ := strconv.ParseInt("0"+frac[:c.Exponent], 10, 64)
+	if len(frac) > c.Exponent && frac[c.Exponent] >= '5' {
+		minor++
+	}
+	return Money(units*pow10(c.Exponent) + minor), nil
+}
+
+// Decimal valor como texto decimal com ponto ("1234.56"), para provedores de pagamento
+func (c Currency) Decimal(m Money) string {
+	sign, abs := "", int64(m)
+	if abs < 0 {
+		sign, abs = "-", -abs
+	}
+	if c.Exponent == 0 {
+		return sign + strconv.FormatInt(abs, 10)
+	}
+	p := pow10(c.Exponent)
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/p, c.Exponent, abs%p)
+}
+
+// Format valor para exibição em pt-BR: "R$ 1.234,56"
+func (c Currency) Format(m Money) string {
+	sign, abs := "", int64(m)
+	if abs < 0 {
+		sign, abs = "-", -abs
+	}
+	p := pow10(c.Exponent)
+	out := sign + c.Symbol + " " + groupThousands(strconv.FormatInt(abs/p, 10))
+	if c.Exponent > 0 {
+		out += fmt.Sprintf(",%0*d", c.Exponent, abs%p)
+	}
+	return out
+}
+
+// groupThousands insere ponto como separador de milhar
+func groupThousands(digits string) string {
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for range n {
+		p *= 10
+	}
+	return p
+}
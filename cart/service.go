@@ -0,0 +1,232 @@
+package cart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/persistence"
+)
+
+// Modos e ações do componente order_cart
+const (
+	ModeAction   = "action"
+	ModeView     = "view"
+	ModeCheckout = "checkout"
+
+	ActionAdd    = "add"
+	ActionRemove = "remove"
+	ActionClear  = "clear"
+)
+
+// Saídas do componente order_cart
+const (
+	OutputAdded         = "added"
+	OutputRemoved       = "removed"
+	OutputCleared       = "cleared"
+	OutputError         = "error"
+	OutputViewed        = "viewed"
+	OutputCheckoutReady = "checkout_ready"
+	OutputEmpty         = "empty"
+)
+
+// DefaultKey chave do carrinho no escopo state
+const DefaultKey = "cart"
+
+// Config configuração de uma execução (mesmas props e defaults do component.OrderCart)
+type Config struct {
+	Mode             string
+	Action           string
+	Item             map[string]any // Item a adicionar/remover (remove usa id e quantity)
+	ShowSummary      bool
+	Currency         string
+	AutoPersist      bool
+	CheckoutProvider string
+}
+
+// DefaultConfig defaults do component.OrderCart
+func DefaultConfig() Config {
+	return Config{
+		Mode:             ModeAction,
+		Action:           ActionAdd,
+		ShowSummary:      true,
+		Currency:         "BRL",
+		AutoPersist:      true,
+		CheckoutProvider: "default",
+	}
+}
+
+// ConfigFromSpec lê a configuração do spec gerado pelo component.OrderCart
+// Campos ausentes mantêm os defaults
+func ConfigFromSpec(spec component.ComponentSpec) Config {
+	cfg := DefaultConfig()
+	str := func(key string, dst *string) {
+		if v, ok := spec.Meta[key].(string); ok && v != "" {
+			*dst = v
+		}
+	}
+	str("mode", &cfg.Mode)
+	str("action", &cfg.Action)
+	str("currency", &cfg.Currency)
+	str("checkout_provider", &cfg.CheckoutProvider)
+	if v, ok := spec.Meta["item"].(map[string]any); ok {
+		cfg.Item = v
+	}
+	if v, ok := spec.Meta["show_summary"].(bool); ok {
+		cfg.ShowSummary = v
+	}
+	if v, ok := spec.Meta["auto_persist"].(bool); ok {
+		cfg.AutoPersist = v
+	}
+	return cfg
+}
+
+// Result resultado de uma execução do order_cart
+type Result struct {
+	Output   string           `json:"output"`
+	Cart     *Cart            `json:"cart,omitempty"`
+	Summary  string           `json:"summary,omitempty"`  // Mensagem para o canal (show_summary_in_channel)
+	Checkout *CheckoutPayload `json:"checkout,omitempty"` // Payload para o provider (checkout_ready)
+	Error    string           `json:"error,omitempty"`
+}
+
+// Service executa o order_cart sobre o carrinho persistido no escopo state
+type Service struct {
+	store persistence.KeyStore
+	key   string
+}
+
+// NewService cria o serviço; store pode ser nil (carrinho apenas em memória, sempre vazio ao carregar)
+func NewService(store persistence.KeyStore) *Service {
+	return &Service{store: store, key: DefaultKey}
+}
+
+// WithKey define a chave do carrinho no escopo state
+func (s *Service) WithKey(key string) *Service {
+	cp := *s
+	cp.key = key
+	return &cp
+}
+
+// Load lê o carrinho do usuário; ausente, cria um vazio na moeda informada
+func (s *Service) Load(ctx context.Context, currency string) (*Cart, error) {
+	if s.store == nil {
+		return New(currency)
+	}
+	raw, err := s.store.Get(ctx, persistence.ScopeState, s.key)
+	if errors.Is(err, persistence.ErrKeyNotFound) || (err == nil && raw == "") {
+		return New(currency)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load cart %s: %w", s.key, err)
+	}
+
+	var c Cart
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		return nil, fmt.Errorf("decode cart %s: %w", s.key, err)
+	}
+	if c.Items == nil {
+		c.Items = []Item{}
+	}
+	return &c, nil
+}
+
+// Save grava o carrinho no escopo state
+func (s *Service) Save(ctx context.Context, c *Cart) error {
+	if s.store == nil {
+		return nil
+	}
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Set(ctx, persistence.ScopeState, s.key, string(raw)); err != nil {
+		return fmt.Errorf("save cart %s: %w", s.key, err)
+	}
+	return nil
+}
+
+// Execute aplica o modo configurado: action (add/remove/clear, grava com
+// auto_persist), view (resumo) ou checkout (payload para o provider)
+// Falhas seguem pela saída error e também são retornadas
+func (s *Service) Execute(ctx context.Context, cfg Config) (Result, error) {
+	currency, err := LookupCurrency(cfg.Currency)
+	if err != nil {
+		return fail(nil, err)
+	}
+	c, err := s.Load(ctx, currency.Code)
+	if err != nil {
+		return fail(nil, err)
+	}
+	if c.Currency != currency.Code {
+		if !c.IsEmpty() {
+			return fail(c, fmt.Errorf("%w: cart in %s, component in %s", ErrCurrencyMismatch, c.Currency, currency.Code))
+		}
+		c.Currency = currency.Code
+	}
+
+	result := Result{Cart: c}
+	switch cfg.Mode {
+	case ModeView:
+		result.Output = OutputViewed
+	case ModeCheckout:
+		if c.IsEmpty() {
+			result.Output = OutputEmpty
+			break
+		}
+		payload, err := Checkout(c, cfg.CheckoutProvider)
+		if err != nil {
+			return fail(c, err)
+		}
+		result.Output, result.Checkout = OutputCheckoutReady, &payload
+	default:
+		if result.Output, err = apply(c, cfg, currency); err != nil {
+			return fail(c, err)
+		}
+		if cfg.AutoPersist {
+			if err := s.Save(ctx, c); err != nil {
+				return fail(c, err)
+			}
+		}
+	}
+
+	if cfg.ShowSummary {
+		if result.Summary, err = Summary(c); err != nil {
+			return fail(c, err)
+		}
+	}
+	return result, nil
+}
+
+// apply executa a ação do modo action
+func apply(c *Cart, cfg Config, currency Currency) (string, error) {
+	switch cfg.Action {
+	case ActionClear:
+		c.Clear()
+		return OutputCleared, nil
+	case ActionAdd:
+		item, err := ParseItem(cfg.Item, currency)
+		if err != nil {
+			return "", err
+		}
+		return OutputAdded, c.Add(item)
+	case ActionRemove:
+		id := propString(cfg.Item, "id", "sku")
+		quantity := 0 // Sem quantity remove a linha inteira
+		if _, ok := cfg.Item["quantity"]; ok {
+			item, err := ParseItem(cfg.Item, currency)
+			if err != nil {
+				return "", err
+			}
+			quantity = item.Quantity
+		}
+		return OutputRemoved, c.Remove(id, quantity)
+	}
+	return "", fmt.Errorf("cart: unknown action %q", cfg.Action)
+}
+
+func fail(c *Cart, err error) (Result, error) {
+	return Result{Output: OutputError, Cart: c, Error: err.Error()}, err
+}
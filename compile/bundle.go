@@ -0,0 +1,207 @@
+package compile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+	"github.com/AgendoCerto/lib-bot/validate"
+)
+
+// ChannelPlan resultado da compilação de um design para um canal
+type ChannelPlan struct {
+	Channel   string           `json:"channel"`             // Nome do canal (adapter)
+	Plan      *io.RuntimePlan  `json:"plan,omitempty"`      // Plano compilado (nil quando não há adapter ou falhou)
+	Checksum  string           `json:"checksum,omitempty"`  // Checksum do design do canal
+	Issues    []validate.Issue `json:"issues"`              // Issues específicas do canal
+	Fallbacks []string         `json:"fallbacks,omitempty"` // Nós substituídos por ChannelFallbacks
+	Error     string           `json:"error,omitempty"`     // Erro de compilação (não é issue de validação)
+}
+
+// Valid indica se o canal compilou sem issues de severidade error
+func (p ChannelPlan) Valid() bool {
+	if p.Plan == nil || p.Error != "" {
+		return false
+	}
+	for _, i := range p.Issues {
+		if i.Severity == validate.Err {
+			return false
+		}
+	}
+	return true
+}
+
+// Bundle conjunto de planos de um design, um por canal de Bot.Channels
+type Bundle struct {
+	BotID   string        `json:"bot_id"`
+	Version string        `json:"version"`
+	Plans   []ChannelPlan `json:"plans"`
+	Report  BundleReport  `json:"report"`
+}
+
+// BundleReport relatório combinado dos canais
+type BundleReport struct {
+	Channels    []string            `json:"channels"`               // Canais compilados (ordem de Bot.Channels)
+	Valid       []string            `json:"valid"`                  // Canais sem erros
+	Invalid     []string            `json:"invalid"`                // Canais com erros ou sem adapter
+	Errors      int                 `json:"errors"`                 // Total de issues error (todos os canais)
+	Warnings    int                 `json:"warnings"`               // Total de issues warn
+	Infos       int                 `json:"infos"`                  // Total de issues info
+	ChannelOnly map[string][]string `json:"channel_only,omitempty"` // node_id -> canais que suportam o nó (só nós parcialmente suportados)
+}
+
+// Plan retorna o plano de um canal
+func (b Bundle) Plan(channel string) (ChannelPlan, bool) {
+	for _, p := range b.Plans {
+		if p.Channel == channel {
+			return p, true
+		}
+	}
+	return ChannelPlan{}, false
+}
+
+// CompileAll compila o design para cada canal de Bot.Channels usando os adapters do provider
+// Nós sem suporte em um canal geram erro, a menos que o design declare ChannelFallbacks para o canal
+func (c DefaultCompiler) CompileAll(ctx context.Context, design io.DesignDoc, reg *component.Registry, p adapter.Provider) (Bundle, error) {
+	if p == nil {
+		return Bundle{}, fmt.Errorf("adapter provider is nil")
+	}
+
+	bundle := Bundle{BotID: design.Bot.ID, Version: design.Version.ID}
+	supported := make(map[flow.ID][]string) // nó -> canais que o suportam
+
+	channels := design.Bot.ChannelNames()
+	for _, channel := range channels {
+		cp := ChannelPlan{Channel: channel}
+
+		a, ok := p.Get(channel)
+		if !ok {
+			cp.Issues = []validate.Issue{{
+				Code: "bundle.channel.no_adapter", Severity: validate.Err,
+				Path: "bot.channels",
+				Msg:  fmt.Sprintf("no adapter registered for channel '%s'", channel),
+			}}
+			bundle.Plans = append(bundle.Plans, cp)
+			continue
+		}
+
+		channelDesign, applied := design.ForChannel(channel)
+		for _, id := range applied {
+			cp.Fallbacks = append(cp.Fallbacks, string(id))
+		}
+		cp.Issues = append(cp.Issues, channelSupportIssues(channelDesign, a, channel, supported)...)
+
+		plan, checksum, issues, err := c.Compile(ctx, channelDesign, reg, a)
+		if err != nil {
+			cp.Error = err.Error()
+		} else {
+			cp.Plan = &plan
+			cp.Checksum = checksum
+			cp.Issues = append(cp.Issues, issues...)
+		}
+
+		bundle.Plans = append(bundle.Plans, cp)
+	}
+
+	bundle.Report = buildBundleReport(bundle.Plans, channels, supported)
+	return bundle, nil
+}
+
+// channelSupportIssues verifica suporte de cada nó pelo canal e registra os canais que suportam cada nó
+// Nós que o Degrader do adapter converte (ex.: listpicker em texto numerado) geram aviso, não erro
+func channelSupportIssues(d io.DesignDoc, a adapter.Adapter, channel string, supported map[flow.ID][]string) []validate.Issue {
+	caps, degrader := a.Capabilities(), adapter.DegraderFor(a)
+	var issues []validate.Issue
+	for i, n := range d.Graph.Nodes {
+		kinds := buttonKinds(d.ResolveProps(n))
+		ok, reason := caps.Supports(n.Kind, kinds)
+		if ok {
+			supported[n.ID] = append(supported[n.ID], channel)
+			continue
+		}
+
+		path := fmt.Sprintf("graph.nodes[%d]", i)
+		if degrader.Degrades(supportSpec(n.Kind, kinds), caps) {
+			supported[n.ID] = append(supported[n.ID], channel)
+			issues = append(issues, validate.Issue{
+				Code: "bundle.node.degraded", Severity: validate.Warn, Path: path,
+				Msg: fmt.Sprintf("node '%s' (%s) is degraded on channel '%s': %s", n.ID, n.Kind, channel, reason),
+			})
+			continue
+		}
+		issues = append(issues, validate.Issue{
+			Code: "bundle.node.unsupported", Severity: validate.Err, Path: path,
+			Msg: fmt.Sprintf("node '%s' (%s) is not supported on channel '%s': %s - add a channel_fallbacks.%s.%s entry", n.ID, n.Kind, channel, reason, channel, n.ID),
+		})
+	}
+	return issues
+}
+
+// supportSpec spec mínimo (tipo e botões) para consultar as regras de degradação
+func supportSpec(kind string, buttonKinds []string) component.ComponentSpec {
+	spec := component.ComponentSpec{Kind: kind}
+	for _, k := range buttonKinds {
+		spec.Buttons = append(spec.Buttons, component.Button{Kind: k})
+	}
+	return spec
+}
+
+// buttonKinds extrai os tipos de botão usados nas props (padrão "reply")
+func buttonKinds(props map[string]any) []string {
+	raw, _ := props["buttons"].([]any)
+	var kinds []string
+	for _, b := range raw {
+		m, _ := b.(map[string]any)
+		kind, _ := m["kind"].(string)
+		if kind == "" {
+			kind = "reply"
+		}
+		if !containsString(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// buildBundleReport consolida contagens e nós suportados só em parte dos canais
+func buildBundleReport(plans []ChannelPlan, channels []string, supported map[flow.ID][]string) BundleReport {
+	report := BundleReport{Channels: channels, Valid: []string{}, Invalid: []string{}}
+
+	compiled := 0
+	for _, p := range plans {
+		if p.Valid() {
+			report.Valid = append(report.Valid, p.Channel)
+		} else {
+			report.Invalid = append(report.Invalid, p.Channel)
+		}
+		if p.Plan != nil || p.Error != "" {
+			compiled++
+		}
+		for _, i := range p.Issues {
+			switch i.Severity {
+			case validate.Err:
+				report.Errors++
+			case validate.Warn:
+				report.Warnings++
+			case validate.Info:
+				report.Infos++
+			}
+		}
+	}
+
+	for id, chs := range supported {
+		if len(chs) < compiled {
+			if report.ChannelOnly == nil {
+				report.ChannelOnly = make(map[string][]string)
+			}
+			sort.Strings(chs)
+			report.ChannelOnly[string(id)] = chs
+		}
+	}
+
+	return report
+}
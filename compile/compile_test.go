@@ -0,0 +1,375 @@
+package compile_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/adapter/whatsapp"
+	"github.com/AgendoCerto/lib-bot/compile"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+	"github.com/AgendoCerto/lib-bot/validate"
+)
+
+// subflowDesign bot com um subflow local "confirm" aninhado em "checkout"
+const subflowDesign = `{
+  "schema": "flowkit/1.0",
+  "bot": {"id": "shop", "channels": ["whatsapp"]},
+  "version": {"id": "v1", "status": "development"},
+  "entries": [{"kind": "global_start", "target": "pay"}],
+  "variables": {"context": ["order_id"], "state": [], "global": {}},
+  "subflows": {
+    "confirm": {
+      "id": "confirm", "entry": "ask", "inputs": ["question"], "outputs": ["yes", "no"],
+      "graph": {
+        "nodes": [{"id": "ask", "kind": "confirm", "props": {"text": "{{context.question}}"}, "outputs": ["confirmed", "cancelled"]}],
+        "edges": [
+          {"from": "ask", "to": "@yes", "label": "confirmed"},
+          {"from": "ask", "to": "@no", "label": "cancelled"}
+        ]
+      }
+    },
+    "checkout": {
+      "id": "checkout", "entry": "summary", "outputs": ["done", "aborted"],
+      "graph": {
+        "nodes": [
+          {"id": "summary", "kind": "message", "props": {"text": "Resumo"}, "outputs": ["complete"]},
+          {"id": "ok", "kind": "subflow", "props": {"ref": "confirm", "inputs": {"question": "Confirma?"}}, "outputs": ["yes", "no"]}
+        ],
+        "edges": [
+          {"from": "summary", "to": "ok", "label": "complete"},
+          {"from": "ok", "to": "@done", "label": "yes"},
+          {"from": "ok", "to": "@aborted", "label": "no"}
+        ]
+      }
+    }
+  },
+  "graph": {
+    "nodes": [
+      {"id": "pay", "kind": "subflow", "props": {"ref": "checkout"}, "outputs": ["done", "aborted"]},
+      {"id": "thanks", "kind": "message", "props": {"text": "Obrigado"}, "final": true, "outputs": ["complete"]},
+      {"id": "bye", "kind": "message", "props": {"text": "Até logo"}, "final": true, "outputs": ["complete"]},
+      {"id": "crm", "kind": "subflow", "props": {"bot_id": "crm_bot", "version_id": "v3"}, "outputs": ["done"]}
+    ],
+    "edges": [
+      {"from": "pay", "to": "thanks", "label": "done"},
+      {"from": "pay", "to": "bye", "label": "aborted"},
+      {"from": "thanks", "to": "crm", "label": "complete"}
+    ]
+  }
+}`
+
+func decodeDesign(t *testing.T, raw string) io.DesignDoc {
+	t.Helper()
+	design, err := io.JSONCodec{}.DecodeDesign([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return design
+}
+
+func hasEdge(g io.Graph, from, to, label string) bool {
+	for _, e := range g.Edges {
+		if e.From == flow.ID(from) && e.To == flow.ID(to) && e.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExpandSubflows(t *testing.T) {
+	design := decodeDesign(t, subflowDesign)
+	out, bindings, err := compile.ExpandSubflows(context.Background(), design, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Subflows aninhados recebem o prefixo de cada nível e a entrada aponta para o nó interno
+	ids := map[flow.ID]string{}
+	for _, n := range out.Graph.Nodes {
+		ids[n.ID] = n.Kind
+	}
+	if ids["pay/summary"] != "message" || ids["pay/ok/ask"] != "confirm" || ids["crm"] != "subflow" {
+		t.Fatalf("nodes = %v", ids)
+	}
+	if _, ok := ids["pay"]; ok {
+		t.Error("inlined subflow node kept in the graph")
+	}
+	if out.Entries[0].Target != "pay/summary" {
+		t.Errorf("entry target = %s", out.Entries[0].Target)
+	}
+
+	// Saídas "@output" religadas às arestas do pai, através dos dois níveis
+	for _, e := range [][3]string{
+		{"pay/summary", "pay/ok/ask", "complete"},
+		{"pay/ok/ask", "thanks", "confirmed"},
+		{"pay/ok/ask", "bye", "cancelled"},
+		{"thanks", "crm", "complete"},
+	} {
+		if !hasEdge(out.Graph, e[0], e[1], e[2]) {
+			t.Errorf("missing edge %s -[%s]-> %s in %+v", e[0], e[2], e[1], out.Graph.Edges)
+		}
+	}
+	for _, e := range out.Graph.Edges {
+		if strings.HasPrefix(string(e.To), io.SubflowExitPrefix) {
+			t.Errorf("dangling exit edge %+v", e)
+		}
+	}
+
+	// Inputs do subflow viram variáveis de contexto
+	if strings.Join(out.Variables.Context, ",") != "order_id,question" {
+		t.Errorf("context variables = %v", out.Variables.Context)
+	}
+
+	modes := map[string]string{}
+	for _, b := range bindings {
+		modes[b.Node] = b.Mode
+	}
+	if len(bindings) != 3 || modes["ok"] != "inline" || modes["pay"] != "inline" || modes["crm"] != "link" {
+		t.Errorf("bindings = %+v", bindings)
+	}
+	for _, b := range bindings {
+		if b.Node == "pay" && (b.Entry != "pay/summary" || b.Outputs["done"] != "thanks" || b.Outputs["aborted"] != "bye") {
+			t.Errorf("pay binding = %+v", b)
+		}
+	}
+}
+
+// fakeResolver resolve subflows externos de um mapa
+type fakeResolver map[string]io.Subflow
+
+func (r fakeResolver) ResolveSubflow(_ context.Context, botID, versionID string) (io.Subflow, error) {
+	return r[botID+"@"+versionID], nil
+}
+
+func TestExpandSubflowsResolver(t *testing.T) {
+	design := decodeDesign(t, subflowDesign)
+	resolver := fakeResolver{"crm_bot@v3": {
+		ID: "crm", Entry: "tag", Outputs: []string{"done"},
+		Graph: io.Graph{
+			Nodes: []flow.Node{{ID: "tag", Kind: "message", Props: map[string]any{"text": "ok"}}},
+			Edges: []flow.Edge{{From: "tag", To: "@done", Label: "complete"}},
+		},
+	}}
+
+	out, bindings, err := compile.ExpandSubflows(context.Background(), design, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasEdge(out.Graph, "thanks", "crm/tag", "complete") {
+		t.Errorf("external subflow not inlined: %+v", out.Graph.Edges)
+	}
+	for _, b := range bindings {
+		if b.Mode != "inline" {
+			t.Errorf("binding %s mode = %s", b.Node, b.Mode)
+		}
+	}
+}
+
+func TestExpandSubflowsRecursion(t *testing.T) {
+	raw := strings.Replace(subflowDesign, `"props": {"ref": "confirm", "inputs": {"question": "Confirma?"}}`, `"props": {"ref": "checkout"}`, 1)
+	_, _, err := compile.ExpandSubflows(context.Background(), decodeDesign(t, raw), nil)
+	if err == nil || !strings.Contains(err.Error(), "subflow recursion detected: ref:checkout -> ref:checkout") {
+		t.Errorf("err = %v", err)
+	}
+
+	raw = strings.Replace(subflowDesign, `"props": {"ref": "checkout"}`, `"props": {"ref": "missing"}`, 1)
+	if _, _, err := compile.ExpandSubflows(context.Background(), decodeDesign(t, raw), nil); err == nil || !strings.Contains(err.Error(), `subflow "missing" not found`) {
+		t.Errorf("missing ref err = %v", err)
+	}
+}
+
+// smsAdapter canal sem listas nem botões de URL (capabilities conservadoras)
+// degrader nil usa as regras padrão
+type smsAdapter struct{ degrader *adapter.Degrader }
+
+func (smsAdapter) Name() string                       { return "sms" }
+func (smsAdapter) Capabilities() adapter.Capabilities { return adapter.NewCaps() }
+func (smsAdapter) Transform(_ context.Context, spec component.ComponentSpec) (component.ComponentSpec, error) {
+	return spec, nil
+}
+func (a smsAdapter) Degrader() *adapter.Degrader { return a.degrader }
+
+const bundleDesign = `{
+  "schema": "flowkit/1.0",
+  "bot": {"id": "menu_bot", "channels": ["whatsapp:5511999990000", "sms", "whatsapp:5511888880000"]},
+  "version": {"id": "v2", "status": "development"},
+  "entries": [{"kind": "global_start", "target": "welcome"}],
+  "variables": {"context": [], "state": [], "global": {}},
+  "graph": {
+    "nodes": [
+      {"id": "welcome", "kind": "message", "props": {"text": "Olá!"}, "outputs": ["complete"]},
+      {"id": "menu", "kind": "listpicker", "props": {
+        "text": "Escolha um serviço", "button_text": "Ver opções",
+        "sections": [{"title": "Serviços", "items": [{"id": "cut", "title": "Corte"}, {"id": "beard", "title": "Barba"}]}]
+      }, "outputs": ["selected"]},
+      {"id": "end", "kind": "message", "props": {"text": "Até logo"}, "final": true, "outputs": ["complete"]}
+    ],
+    "edges": [
+      {"from": "welcome", "to": "menu", "label": "complete"},
+      {"from": "menu", "to": "end", "label": "selected"}
+    ]
+  }
+}`
+
+func TestCompileAll(t *testing.T) {
+	provider := adapter.NewProvider()
+	provider.Register(whatsapp.New())
+	provider.Register(smsAdapter{})
+	reg := component.DefaultRegistry()
+
+	design := decodeDesign(t, bundleDesign)
+	bundle, err := compile.DefaultCompiler{}.CompileAll(context.Background(), design, reg, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.BotID != "menu_bot" || bundle.Version != "v2" || len(bundle.Plans) != 2 {
+		t.Fatalf("bundle = %+v", bundle)
+	}
+
+	// Canais repetidos ("whatsapp:<número>") compilam uma vez; no SMS o listpicker vira texto numerado
+	r := bundle.Report
+	if !reflect.DeepEqual(r.Channels, []string{"whatsapp", "sms"}) || !reflect.DeepEqual(r.Valid, []string{"whatsapp", "sms"}) ||
+		len(r.Invalid) != 0 || r.Errors != 0 || r.ChannelOnly != nil {
+		t.Errorf("report = %+v", r)
+	}
+	sms, _ := bundle.Plan("sms")
+	if sms.Plan == nil || sms.Issues[0].Code != "bundle.node.degraded" || sms.Issues[0].Severity != validate.Warn ||
+		len(sms.Plan.Degradations) != 1 || sms.Plan.Degradations[0].Rule != "listpicker_to_text" {
+		t.Errorf("sms plan = %+v", sms)
+	}
+
+	// Sem regra de degradação o listpicker só existe no WhatsApp
+	provider.Register(smsAdapter{degrader: adapter.NewDegrader()})
+	bundle, err = compile.DefaultCompiler{}.CompileAll(context.Background(), design, reg, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = bundle.Report
+	if !reflect.DeepEqual(r.Valid, []string{"whatsapp"}) || !reflect.DeepEqual(r.Invalid, []string{"sms"}) || r.Errors != 1 {
+		t.Errorf("report without degradation = %+v", r)
+	}
+	if !reflect.DeepEqual(r.ChannelOnly, map[string][]string{"menu": {"whatsapp"}}) {
+		t.Errorf("channel_only = %v", r.ChannelOnly)
+	}
+	sms, _ = bundle.Plan("sms")
+	if sms.Plan == nil || sms.Issues[0].Code != "bundle.node.unsupported" || sms.Issues[0].Path != "graph.nodes[1]" {
+		t.Errorf("sms plan without degradation = %+v", sms)
+	}
+
+	// Fallback declarado para o SMS substitui o nó só nesse canal
+	design.ChannelFallbacks = map[string]map[string]io.ChannelFallback{
+		"sms": {"menu": {Kind: "buttons", Props: map[string]any{
+			"text":    "Escolha um serviço",
+			"buttons": []any{map[string]any{"label": "Corte", "payload": "cut"}, map[string]any{"label": "Barba", "payload": "beard"}},
+		}}},
+	}
+	design.Bot.Channels = append(design.Bot.Channels, "telegram")
+	bundle, err = compile.DefaultCompiler{}.CompileAll(context.Background(), design, reg, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = bundle.Report
+	if !reflect.DeepEqual(r.Valid, []string{"whatsapp", "sms"}) || !reflect.DeepEqual(r.Invalid, []string{"telegram"}) || r.ChannelOnly != nil {
+		t.Errorf("report with fallback = %+v", r)
+	}
+	sms, _ = bundle.Plan("sms")
+	wa, _ := bundle.Plan("whatsapp")
+	if !reflect.DeepEqual(sms.Fallbacks, []string{"menu"}) || wa.Fallbacks != nil || sms.Checksum == wa.Checksum {
+		t.Errorf("sms fallbacks = %v, whatsapp = %v", sms.Fallbacks, wa.Fallbacks)
+	}
+	if tg, _ := bundle.Plan("telegram"); tg.Plan != nil || tg.Issues[0].Code != "bundle.channel.no_adapter" {
+		t.Errorf("telegram plan = %+v", tg)
+	}
+
+	if _, err := (compile.DefaultCompiler{}).CompileAll(context.Background(), design, reg, nil); err == nil {
+		t.Error("nil provider accepted")
+	}
+}
+
+const localizedDesign = `{
+  "schema": "flowkit/1.0",
+  "bot": {"id": "loja", "channels": ["whatsapp"]},
+  "version": {"id": "v1", "status": "development"},
+  "entries": [{"kind": "global_start", "target": "start"}],
+  "variables": {"context": [], "state": [], "global": {}},
+  "i18n": {
+    "default_locale": "pt-BR", "locales": ["pt-BR", "en"],
+    "translations": {
+      "menu.text": {"en": "Pick a service"},
+      "menu.sections[0].title": {"en": "Services"},
+      "menu.sections[0].items[0].title": {"en": "Haircut"},
+      "photo.caption": {"en": "Our store"},
+      "greet/hello.text": {"en": "Hi!"}
+    }
+  },
+  "subflows": {
+    "greet": {
+      "id": "greet", "entry": "hello", "outputs": ["done"],
+      "graph": {
+        "nodes": [{"id": "hello", "kind": "message", "props": {"text": "Oi!"}, "outputs": ["complete"]}],
+        "edges": [{"from": "hello", "to": "@done", "label": "complete"}]
+      }
+    }
+  },
+  "graph": {
+    "nodes": [
+      {"id": "start", "kind": "subflow", "props": {"ref": "greet"}, "outputs": ["done"]},
+      {"id": "photo", "kind": "media", "props": {"media_type": "image", "caption": "Nossa loja"}, "outputs": ["complete"]},
+      {"id": "menu", "kind": "listpicker", "props": {
+        "text": "Escolha um serviço", "button_text": "Ver opções",
+        "sections": [{"title": "Serviços", "items": [{"id": "cut", "title": "Corte"}, {"id": "beard", "title": "Barba"}]}]
+      }, "outputs": ["selected"], "final": true}
+    ],
+    "edges": [
+      {"from": "start", "to": "photo", "label": "done"},
+      {"from": "photo", "to": "menu", "label": "complete"}
+    ]
+  }
+}`
+
+func TestCompileLocalizesAllTexts(t *testing.T) {
+	design := decodeDesign(t, localizedDesign)
+
+	// Nós de subflows locais entram na exportação com a chave "<subflow>/<nó>"
+	keys := map[string]bool{}
+	for _, text := range design.LocalizableTexts() {
+		keys[text.Key] = true
+	}
+	for _, key := range []string{"greet/hello.text", "photo.caption", "menu.sections[0].items[1].title"} {
+		if !keys[key] {
+			t.Errorf("LocalizableTexts missing %s", key)
+		}
+	}
+
+	plan, _, _, err := compile.DefaultCompiler{}.Compile(context.Background(), design, component.DefaultRegistry(), whatsapp.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	views := map[string]component.ComponentSpec{}
+	for _, r := range plan.Routes {
+		views[r.Node], _ = r.View.(component.ComponentSpec)
+	}
+	chain := plan.I18n.Chain("en-US")
+
+	if hello := views["start/hello"]; hello.Text == nil || hello.Text.Localized(chain) != "Hi!" {
+		t.Errorf("inlined subflow node = %+v", hello.Text)
+	}
+	if photo := views["photo"]; photo.Text == nil || photo.Text.Localized(chain) != "Our store" {
+		t.Errorf("media caption = %+v", photo.Text)
+	}
+	menu := views["menu"]
+	if menu.Text.Localized(chain) != "Pick a service" ||
+		menu.LocalizedProp("sections[0].title", "Serviços", chain) != "Services" ||
+		menu.LocalizedProp("sections[0].items[0].title", "Corte", chain) != "Haircut" ||
+		menu.LocalizedProp("sections[0].items[1].title", "Barba", chain) != "Barba" {
+		t.Errorf("menu = %+v", menu.Meta[component.MetaI18n])
+	}
+	if design.I18n.Translations["start/hello.text"] != nil {
+		t.Error("Compile changed the design translations")
+	}
+}
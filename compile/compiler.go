@@ -33,24 +33,37 @@ type Compiler interface {
 }
 
 // DefaultCompiler implementação padrão do compilador
-type DefaultCompiler struct{}
+type DefaultCompiler struct {
+	Resolver SubflowResolver // Opcional: resolve subflows de outros bots (sem resolver ficam linkados)
+}
 
 // Compile transforma um design em plano de execução usando um adapter específico
-func (DefaultCompiler) Compile(ctx context.Context, design io.DesignDoc, reg *component.Registry, a adapter.Adapter) (io.RuntimePlan, string, []validate.Issue, error) {
+func (c DefaultCompiler) Compile(ctx context.Context, design io.DesignDoc, reg *component.Registry, a adapter.Adapter) (io.RuntimePlan, string, []validate.Issue, error) {
 	if reg == nil {
 		return io.RuntimePlan{}, "", nil, errors.New("component registry is nil")
 	}
 
+	// Inline de subflows (locais e externos resolvidos); o design original segue para as validações
+	expanded, bindings, err := ExpandSubflows(ctx, design, c.Resolver)
+	if err != nil {
+		return io.RuntimePlan{}, "", nil, err
+	}
+
 	// Criar contexto de runtime baseado nas variáveis do design
-	runtimeCtx := buildRuntimeContextFromVariables(design.Variables)
+	runtimeCtx := buildRuntimeContextFromVariables(expanded.Variables)
+	if expanded.I18n != nil {
+		runtimeCtx.Locale = expanded.I18n.DefaultLocale
+	}
 
 	// Percorre o grafo e monta ComponentSpecs
-	specs := make([]component.ComponentSpec, 0, len(design.Graph.Nodes))
-	routes := make([]io.Route, 0, len(design.Graph.Nodes))
+	specs := make([]component.ComponentSpec, 0, len(expanded.Graph.Nodes))
+	routes := make([]io.Route, 0, len(expanded.Graph.Nodes))
 	det := liquid.NoRenderDetector{} // Detector para factories que precisem
+	degrader := adapter.DegraderFor(a)
+	var degradations []io.Degradation
 
-	for _, n := range design.Graph.Nodes {
-		props := design.ResolveProps(n)
+	for _, n := range expanded.Graph.Nodes {
+		props := expanded.ResolveProps(n)
 		comp, err := reg.New(n.Kind, props)
 		if err != nil {
 			return io.RuntimePlan{}, "", nil, err
@@ -62,6 +75,14 @@ func (DefaultCompiler) Compile(ctx context.Context, design io.DesignDoc, reg *co
 		if err != nil {
 			return io.RuntimePlan{}, "", nil, err
 		}
+		localizeSpec(&spec, n.ID, expanded.I18n)
+
+		// Degradação declarativa para capacidades ausentes no canal (registrada no plano)
+		spec, applied := degrader.Degrade(spec, a.Capabilities())
+		for _, d := range applied {
+			d.Node = string(n.ID)
+			degradations = append(degradations, d)
+		}
 
 		adapted, err := a.Transform(ctx, spec)
 		if err != nil {
@@ -83,6 +104,11 @@ func (DefaultCompiler) Compile(ctx context.Context, design io.DesignDoc, reg *co
 			"max_text_len": a.Capabilities().MaxTextLen,
 			"max_buttons":  a.Capabilities().MaxButtons,
 		},
+		Subflows:      bindings,
+		I18n:          expanded.I18n.Policy(),
+		Degradations:  degradations,
+		Intents:       expanded.Intents,
+		BusinessHours: expanded.BusinessHours,
 	}
 
 	// Validações sobre topologia do design primeiro
@@ -95,7 +121,7 @@ func (DefaultCompiler) Compile(ctx context.Context, design io.DesignDoc, reg *co
 
 	// Validações sobre specs (sem render)
 	p := validate.NewPipeline()
-	specIssues := p.RunWithDesign(specs, a.Capabilities(), "$", &design)
+	specIssues := p.RunWithDesign(specs, a.Capabilities(), "$", &expanded)
 
 	// Combina todas as issues
 	allIssues := append(topologyIssues, designIssues...)
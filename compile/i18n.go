@@ -0,0 +1,61 @@
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// localizeSpec anexa as variantes traduzidas do design aos textos do spec
+// Texto (ou legenda de mídia) e rótulos de botões recebem TextValue.Variants; as demais
+// props traduzíveis (seções, itens, cards, título, descrição) vão para Meta[component.MetaI18n]
+// O runtime escolhe a variante com TextValue.Localized ou ComponentSpec.LocalizedProp,
+// usando plan.I18n.Chain(locale da sessão)
+func localizeSpec(spec *component.ComponentSpec, node flow.ID, t *io.I18n) {
+	if t == nil || len(t.Translations) == 0 {
+		return
+	}
+
+	props := make(map[string]map[string]string)
+	prefix := string(node) + "."
+	for key := range t.Translations {
+		if prop, ok := strings.CutPrefix(key, prefix); ok {
+			if variants := t.Variants(key); variants != nil {
+				props[prop] = variants
+			}
+		}
+	}
+	if len(props) == 0 {
+		return
+	}
+
+	if spec.Text != nil {
+		for _, prop := range []string{"text", "caption"} {
+			if variants, ok := props[prop]; ok {
+				spec.Text.Variants = variants
+				delete(props, prop)
+				break
+			}
+		}
+	}
+	for i := range spec.Buttons {
+		prop := fmt.Sprintf("buttons[%d].label", i)
+		if variants, ok := props[prop]; ok {
+			spec.Buttons[i].Label.Variants = variants
+			delete(props, prop)
+		}
+	}
+	if len(props) == 0 {
+		return
+	}
+
+	meta := make(map[string]any, len(spec.Meta)+1)
+	for k, v := range spec.Meta {
+		meta[k] = v
+	}
+	meta[component.MetaI18n] = props
+	spec.Meta = meta
+}
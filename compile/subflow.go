@@ -0,0 +1,265 @@
+package compile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// maxSubflowDepth limita o aninhamento de subflows (proteção adicional contra recursão)
+const maxSubflowDepth = 8
+
+// SubflowResolver resolve subflows que referenciam outro bot/versão
+type SubflowResolver interface {
+	ResolveSubflow(ctx context.Context, botID, versionID string) (io.Subflow, error)
+}
+
+// ExpandSubflows faz o inline dos nós "subflow" do design
+// - Subflows locais (DesignDoc.Subflows) são sempre inlined
+// - Subflows externos (bot_id/version_id) são inlined se houver resolver; senão ficam linkados
+// Nós internos recebem o prefixo "<id do nó subflow>/" e as saídas "@output" são religadas
+// às arestas do nó pai com o mesmo label.
+// Traduções dos nós de subflows locais ("<subflow>/<nó>.<prop>") são copiadas para os
+// nós inlined, que o compilador localiza pelo ID expandido.
+func ExpandSubflows(ctx context.Context, design io.DesignDoc, resolver SubflowResolver) (io.DesignDoc, []io.SubflowBinding, error) {
+	exp := &subflowExpander{ctx: ctx, subflows: design.Subflows, resolver: resolver}
+
+	graph, entries, origins, err := exp.expand(design.Graph, nil, "", nil)
+	if err != nil {
+		return io.DesignDoc{}, nil, err
+	}
+	if len(exp.bindings) == 0 {
+		return design, nil, nil
+	}
+
+	out := design
+	out.Graph = graph
+	out.I18n = inlineTranslations(design.I18n, origins)
+
+	// Entradas que apontam para um nó subflow passam a apontar para a entrada interna
+	out.Entries = make([]flow.Entry, len(design.Entries))
+	for i, entry := range design.Entries {
+		if target, ok := entries[entry.Target]; ok {
+			entry.Target = target
+		}
+		out.Entries[i] = entry
+	}
+
+	// Parâmetros de entrada dos subflows ficam disponíveis como variáveis de contexto
+	out.Variables.Context = append([]string{}, design.Variables.Context...)
+	for _, name := range exp.inputs {
+		if !containsString(out.Variables.Context, name) {
+			out.Variables.Context = append(out.Variables.Context, name)
+		}
+	}
+
+	return out, exp.bindings, nil
+}
+
+// subflowExpander mantém o estado da expansão recursiva
+type subflowExpander struct {
+	ctx      context.Context
+	subflows map[string]io.Subflow
+	resolver SubflowResolver
+	bindings []io.SubflowBinding
+	inputs   []string
+}
+
+// inlined guarda o resultado da expansão de um nó subflow
+type inlined struct {
+	entry   flow.ID
+	exits   []flow.Edge // Arestas internas que saem por "@output" (From já prefixado)
+	binding int         // Índice em subflowExpander.bindings
+}
+
+// expand expande os nós subflow de um grafo
+// sf != nil indica um grafo interno (props dos nós são resolvidas inline); local é o
+// ID do subflow quando ele é local (chave das traduções dos seus nós)
+// Retorna o grafo expandido, o mapa nó subflow -> nó de entrada interno e o mapa
+// nó expandido -> nó nas chaves de tradução ("<subflow>/<nó>")
+func (e *subflowExpander) expand(g io.Graph, sf *io.Subflow, local string, stack []string) (io.Graph, map[flow.ID]flow.ID, map[flow.ID]flow.ID, error) {
+	out := io.Graph{
+		Nodes: make([]flow.Node, 0, len(g.Nodes)),
+		Edges: make([]flow.Edge, 0, len(g.Edges)),
+	}
+	expanded := make(map[flow.ID]inlined)
+	entries := make(map[flow.ID]flow.ID)
+	origins := make(map[flow.ID]flow.ID)
+
+	for _, n := range g.Nodes {
+		props := n.Props
+		if sf != nil {
+			props = sf.ResolveProps(n)
+			n.Props = props
+			n.PropsRef = ""
+		}
+
+		if n.Kind != "subflow" {
+			if local != "" {
+				origins[n.ID] = flow.ID(local + "/" + string(n.ID))
+			}
+			out.Nodes = append(out.Nodes, n)
+			continue
+		}
+
+		ref, _ := props["ref"].(string)
+		botID, _ := props["bot_id"].(string)
+		versionID, _ := props["version_id"].(string)
+		inputs := stringMap(props["inputs"])
+
+		binding := io.SubflowBinding{
+			Node:      string(n.ID),
+			Ref:       ref,
+			BotID:     botID,
+			VersionID: versionID,
+			Inputs:    inputs,
+		}
+
+		var target io.Subflow
+		var key string
+		switch {
+		case ref != "":
+			s, ok := e.subflows[ref]
+			if !ok {
+				return io.Graph{}, nil, nil, fmt.Errorf("subflow node %s: subflow %q not found", n.ID, ref)
+			}
+			target, key = s, "ref:"+ref
+		case botID != "" && e.resolver != nil:
+			s, err := e.resolver.ResolveSubflow(e.ctx, botID, versionID)
+			if err != nil {
+				return io.Graph{}, nil, nil, fmt.Errorf("subflow node %s: %w", n.ID, err)
+			}
+			target, key = s, "bot:"+botID+"@"+versionID
+		case botID != "":
+			// Sem resolver: mantém o nó e registra o link para o runtime
+			binding.Mode = "link"
+			e.bindings = append(e.bindings, binding)
+			out.Nodes = append(out.Nodes, n)
+			continue
+		default:
+			return io.Graph{}, nil, nil, fmt.Errorf("subflow node %s: ref or bot_id is required", n.ID)
+		}
+
+		if containsString(stack, key) {
+			return io.Graph{}, nil, nil, fmt.Errorf("subflow recursion detected: %s -> %s", strings.Join(stack, " -> "), key)
+		}
+		if len(stack) >= maxSubflowDepth {
+			return io.Graph{}, nil, nil, fmt.Errorf("subflow node %s: max nesting depth (%d) exceeded", n.ID, maxSubflowDepth)
+		}
+
+		inner, innerEntries, innerOrigins, err := e.expand(target.Graph, &target, ref, append(append([]string{}, stack...), key))
+		if err != nil {
+			return io.Graph{}, nil, nil, err
+		}
+
+		prefix := string(n.ID) + "/"
+		entry := target.Entry
+		if mapped, ok := innerEntries[entry]; ok {
+			entry = mapped
+		}
+
+		for _, in := range inner.Nodes {
+			if origin, ok := innerOrigins[in.ID]; ok {
+				origins[flow.ID(prefix+string(in.ID))] = origin
+			}
+			in.ID = flow.ID(prefix + string(in.ID))
+			out.Nodes = append(out.Nodes, in)
+		}
+
+		var exits []flow.Edge
+		for _, ie := range inner.Edges {
+			ie.From = flow.ID(prefix + string(ie.From))
+			if strings.HasPrefix(string(ie.To), io.SubflowExitPrefix) {
+				exits = append(exits, ie)
+				continue
+			}
+			ie.To = flow.ID(prefix + string(ie.To))
+			out.Edges = append(out.Edges, ie)
+			binding.Edges = append(binding.Edges, ie)
+		}
+
+		binding.Mode = "inline"
+		binding.Entry = prefix + string(entry)
+		binding.Outputs = make(map[string]string)
+		e.bindings = append(e.bindings, binding)
+		e.inputs = append(e.inputs, target.Inputs...)
+
+		expanded[n.ID] = inlined{entry: flow.ID(binding.Entry), exits: exits, binding: len(e.bindings) - 1}
+		entries[n.ID] = flow.ID(binding.Entry)
+	}
+
+	for _, edge := range g.Edges {
+		if in, ok := expanded[edge.To]; ok {
+			edge.To = in.entry
+		}
+
+		in, ok := expanded[edge.From]
+		if !ok {
+			out.Edges = append(out.Edges, edge)
+			continue
+		}
+
+		// Religa cada saída "@label" do subflow à aresta do pai com o mesmo label
+		for _, exit := range in.exits {
+			if strings.TrimPrefix(string(exit.To), io.SubflowExitPrefix) != edge.Label {
+				continue
+			}
+			rewired := exit
+			rewired.To = edge.To
+			rewired.Metadata = map[string]any{"subflow": string(edge.From), "subflow_output": edge.Label}
+			out.Edges = append(out.Edges, rewired)
+			e.bindings[in.binding].Edges = append(e.bindings[in.binding].Edges, rewired)
+		}
+		e.bindings[in.binding].Outputs[edge.Label] = string(edge.To)
+	}
+
+	return out, entries, origins, nil
+}
+
+// inlineTranslations copia as traduções dos nós de subflows locais para os IDs
+// expandidos (o I18n original não é alterado)
+func inlineTranslations(t *io.I18n, origins map[flow.ID]flow.ID) *io.I18n {
+	if t == nil || len(t.Translations) == 0 || len(origins) == 0 {
+		return t
+	}
+	cp := *t
+	cp.Translations = make(map[string]map[string]string, len(t.Translations))
+	for key, variants := range t.Translations {
+		cp.Translations[key] = variants
+	}
+	for node, origin := range origins {
+		for key, variants := range t.Translations {
+			if prop, ok := strings.CutPrefix(key, string(origin)+"."); ok {
+				cp.Translations[io.TranslationKey(node, prop)] = variants
+			}
+		}
+	}
+	return &cp
+}
+
+// stringMap converte map[string]any em map[string]string (ignora valores não-string)
+func stringMap(raw any) map[string]string {
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			out[k] = s
+		}
+	}
+	return out
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
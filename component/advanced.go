@@ -231,7 +231,7 @@ func (pl *PaymentLink) Spec(ctx context.Context, _ runtime.Context) (ComponentSp
 		"lock_slot_ttl_s": pl.lockSlotTTL,
 		"metadata":        pl.metadata,
 		"component_type":  "payment_link",
-		// Outputs: paid | expired | failed | abandoned
+		// Outputs: paid | expired | failed | cancelled | abandoned
 	}
 
 	return ComponentSpec{
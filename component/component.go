@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/AgendoCerto/lib-bot/hsm"
+	"github.com/AgendoCerto/lib-bot/io"
 	"github.com/AgendoCerto/lib-bot/liquid"
 	"github.com/AgendoCerto/lib-bot/persistence"
 	"github.com/AgendoCerto/lib-bot/runtime"
@@ -18,6 +20,34 @@ type TextValue struct {
 	Raw      string      `json:"raw"`      // Texto original com possíveis templates
 	Template bool        `json:"template"` // Indica se contém templates Liquid
 	Liquid   liquid.Meta `json:"liquid"`   // Metadados de parsing do Liquid
+	// Variantes traduzidas por locale (ex: "es", "en-US"); Raw é o texto do locale padrão
+	Variants map[string]string `json:"variants,omitempty"`
+}
+
+// Localized retorna o texto do primeiro locale da cadeia com variante, ou Raw
+func (t TextValue) Localized(chain []string) string {
+	for _, locale := range chain {
+		if v, ok := t.Variants[locale]; ok && v != "" {
+			return v
+		}
+	}
+	return t.Raw
+}
+
+// MetaI18n chave do Meta com as variantes traduzidas das props sem TextValue
+// (seções, itens, cards...): caminho da prop (ex: "sections[0].items[1].title") -> locale -> texto
+const MetaI18n = "i18n"
+
+// LocalizedProp retorna o texto da prop no primeiro locale da cadeia com variante em
+// Meta[MetaI18n], ou raw (texto do locale padrão)
+func (s ComponentSpec) LocalizedProp(prop, raw string, chain []string) string {
+	variants, _ := s.Meta[MetaI18n].(map[string]map[string]string)
+	for _, locale := range chain {
+		if v, ok := variants[prop][locale]; ok && v != "" {
+			return v
+		}
+	}
+	return raw
 }
 
 // HSMView representa uma HSM (Highly Structured Message) com parâmetros templated
@@ -30,6 +60,25 @@ type HSMView struct {
 	Policy    string      `json:"policy,omitempty"`    // Política de fallback: error_on_missing|fallback_to_text|fallback_to_menu
 }
 
+// SessionLocale marca HSMs/templates cujo idioma é resolvido pelo locale da sessão
+const SessionLocale = "auto"
+
+// ResolveLocale resolve o idioma da HSM: vazio ou "auto" usa o locale da sessão (formato WhatsApp: pt_BR)
+func (h HSMView) ResolveLocale(sessionLocale string) string {
+	return resolveTemplateLanguage(h.Locale, sessionLocale)
+}
+
+// resolveTemplateLanguage aplica o locale da sessão quando o idioma não é fixo (padrão pt_BR)
+func resolveTemplateLanguage(language, sessionLocale string) string {
+	if language != "" && language != SessionLocale {
+		return language
+	}
+	if sessionLocale == "" {
+		return "pt_BR"
+	}
+	return strings.ReplaceAll(io.NormalizeLocale(sessionLocale), "-", "_")
+}
+
 // Button representa um botão interativo
 type Button struct {
 	Label   TextValue `json:"label"`   // Texto do botão (pode ter templates)
@@ -455,36 +504,50 @@ func ParsePersistence(props map[string]any) (*persistence.Config, error) {
 		config.DefaultValue = defaultValue
 	}
 
-	// Sanitization
-	if sanitizationRaw, hasSanitization := persistenceMap["sanitization"]; hasSanitization {
-		if sanitizationMap, ok := sanitizationRaw.(map[string]any); ok {
-			sanitization := &persistence.SanitizationConfig{}
-
-			if sanitizationType, ok := sanitizationMap["type"].(string); ok {
-				sanitization.Type = persistence.SanitizationType(sanitizationType)
+	// Sanitization: objeto único ou lista ordenada de etapas (pipeline)
+	switch sanitizationRaw := persistenceMap["sanitization"].(type) {
+	case map[string]any:
+		config.Sanitization = parseSanitization(sanitizationRaw)
+	case []any:
+		for _, stepRaw := range sanitizationRaw {
+			if stepMap, ok := stepRaw.(map[string]any); ok {
+				config.Pipeline = append(config.Pipeline, *parseSanitization(stepMap))
 			}
+		}
+	}
 
-			if customRegex, ok := sanitizationMap["custom_regex"].(string); ok {
-				sanitization.CustomRegex = customRegex
-			}
+	return config, nil
+}
 
-			if replacement, ok := sanitizationMap["replacement"].(string); ok {
-				sanitization.Replacement = replacement
-			}
+// parseSanitization converte uma etapa de sanitização
+func parseSanitization(sanitizationMap map[string]any) *persistence.SanitizationConfig {
+	sanitization := &persistence.SanitizationConfig{}
 
-			if description, ok := sanitizationMap["description"].(string); ok {
-				sanitization.Description = description
-			}
+	if sanitizationType, ok := sanitizationMap["type"].(string); ok {
+		sanitization.Type = persistence.SanitizationType(sanitizationType)
+	}
 
-			if strictMode, ok := sanitizationMap["strict_mode"].(bool); ok {
-				sanitization.StrictMode = strictMode
-			}
+	if customRegex, ok := sanitizationMap["custom_regex"].(string); ok {
+		sanitization.CustomRegex = customRegex
+	}
 
-			config.Sanitization = sanitization
-		}
+	if replacement, ok := sanitizationMap["replacement"].(string); ok {
+		sanitization.Replacement = replacement
 	}
 
-	return config, nil
+	if region, ok := sanitizationMap["region"].(string); ok {
+		sanitization.Region = region
+	}
+
+	if description, ok := sanitizationMap["description"].(string); ok {
+		sanitization.Description = description
+	}
+
+	if strictMode, ok := sanitizationMap["strict_mode"].(bool); ok {
+		sanitization.StrictMode = strictMode
+	}
+
+	return sanitization
 }
 
 // parseValidator converte map para validator.Config
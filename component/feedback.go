@@ -13,7 +13,7 @@ import (
 type Feedback struct {
 	text        string          // Texto da pergunta de feedback
 	placeholder string          // Placeholder para entrada do usuário
-	scale       string          // Tipo de escala (ex: "1-5", "1-10", "emoji", "text")
+	scale       string          // Tipo de escala (ex: "1-5", "1-10", "0-10", "emoji", "text")
 	det         liquid.Detector // Detector para parsing de templates Liquid
 }
 
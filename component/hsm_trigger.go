@@ -11,7 +11,7 @@ import (
 // Permite controlar quando enviar (imediato, agendado ou por condição)
 type HSMTrigger struct {
 	templateID      string            // ID do template
-	language        string            // Código do idioma (ex: "pt_BR")
+	language        string            // Código do idioma (ex: "pt_BR"); vazio ou "auto" usa o locale da sessão
 	variables       map[string]string // Variáveis do template
 	triggerMode     string            // immediate|schedule|condition
 	scheduleMinutes int               // Para mode=schedule
@@ -84,15 +84,20 @@ func (h *HSMTrigger) WithCooldown(key string, seconds int) *HSMTrigger {
 }
 
 // Spec gera o ComponentSpec
-func (h *HSMTrigger) Spec(ctx context.Context, _ runtime.Context) (ComponentSpec, error) {
+func (h *HSMTrigger) Spec(ctx context.Context, rctx runtime.Context) (ComponentSpec, error) {
 	metaData := map[string]any{
 		"template_id":    h.templateID,
-		"language":       h.language,
+		"language":       resolveTemplateLanguage(h.language, rctx.Locale),
 		"variables":      h.variables,
 		"trigger_mode":   h.triggerMode,
 		"component_type": "hsm_trigger",
 	}
 
+	// Idioma não fixado: o runtime deve re-resolver com o locale de cada sessão
+	if h.language == "" || h.language == SessionLocale {
+		metaData["language_source"] = "session"
+	}
+
 	if h.triggerMode == "schedule" {
 		metaData["schedule"] = map[string]int{
 			"in_minutes": h.scheduleMinutes,
@@ -133,10 +138,7 @@ func (f *HSMTriggerFactory) New(_ string, props map[string]any) (Component, erro
 
 	// Template
 	if templateID, ok := props["template_id"].(string); ok {
-		language, _ := props["language"].(string)
-		if language == "" {
-			language = "pt_BR"
-		}
+		language, _ := props["language"].(string) // vazio = locale da sessão
 		h = h.WithTemplate(templateID, language)
 	}
 
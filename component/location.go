@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/AgendoCerto/lib-bot/liquid"
+	"github.com/AgendoCerto/lib-bot/persistence"
 	"github.com/AgendoCerto/lib-bot/runtime"
 )
 
@@ -87,8 +88,15 @@ func (f *LocationCaptureFactory) New(_ string, props map[string]any) (Component,
 		return nil, err
 	}
 
+	// Parse persistence (onde o endereço capturado/resolvido é gravado)
+	persistence, err := ParsePersistence(props)
+	if err != nil {
+		return nil, err
+	}
+
 	return &LocationCaptureWithBehavior{
 		locationCapture: lc,
+		persistence:     persistence,
 		behavior:        behavior,
 	}, nil
 }
@@ -96,6 +104,7 @@ func (f *LocationCaptureFactory) New(_ string, props map[string]any) (Component,
 // LocationCaptureWithBehavior wrapper
 type LocationCaptureWithBehavior struct {
 	locationCapture *LocationCapture
+	persistence     *persistence.Config
 	behavior        *ComponentBehavior
 }
 
@@ -110,6 +119,7 @@ func (lcwb *LocationCaptureWithBehavior) Spec(ctx context.Context, rctx runtime.
 	}
 
 	spec.Behavior = lcwb.behavior
+	spec.Persistence = lcwb.persistence
 	return spec, nil
 }
 
@@ -184,16 +194,24 @@ func (f *GeoResolveFactory) New(_ string, props map[string]any) (Component, erro
 		return nil, err
 	}
 
+	// Parse persistence (onde o endereço capturado/resolvido é gravado)
+	persistence, err := ParsePersistence(props)
+	if err != nil {
+		return nil, err
+	}
+
 	return &GeoResolveWithBehavior{
-		geoResolve: gr,
-		behavior:   behavior,
+		geoResolve:  gr,
+		persistence: persistence,
+		behavior:    behavior,
 	}, nil
 }
 
 // GeoResolveWithBehavior wrapper
 type GeoResolveWithBehavior struct {
-	geoResolve *GeoResolve
-	behavior   *ComponentBehavior
+	geoResolve  *GeoResolve
+	persistence *persistence.Config
+	behavior    *ComponentBehavior
 }
 
 func (grwb *GeoResolveWithBehavior) Kind() string {
@@ -207,5 +225,6 @@ func (grwb *GeoResolveWithBehavior) Spec(ctx context.Context, rctx runtime.Conte
 	}
 
 	spec.Behavior = grwb.behavior
+	spec.Persistence = grwb.persistence
 	return spec, nil
 }
@@ -44,5 +44,8 @@ func DefaultRegistry() *Registry {
 	reg.Register("order_cart", NewOrderCartFactory(det))
 	reg.Register("human_handoff", NewHumanHandoffFactory(det))
 
+	// Módulos reutilizáveis
+	reg.Register("subflow", NewSubflowFactory(det))
+
 	return reg
 }
@@ -0,0 +1,106 @@
+package component
+
+import (
+	"context"
+
+	"github.com/AgendoCerto/lib-bot/liquid"
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+// Subflow componente que invoca um módulo de fluxo reutilizável
+// Referencia um subflow local (DesignDoc.Subflows) ou outro bot/versão
+type Subflow struct {
+	ref       string            // ID do subflow local
+	botID     string            // Bot externo (quando não é local)
+	versionID string            // Versão do bot externo
+	inputs    map[string]string // Parâmetro -> expressão (pode conter Liquid)
+	det       liquid.Detector   // Detector para parsing de templates Liquid
+}
+
+// NewSubflow cria nova instância de componente subflow
+func NewSubflow(det liquid.Detector) *Subflow {
+	return &Subflow{det: det, inputs: map[string]string{}}
+}
+
+func (s *Subflow) Kind() string { return "subflow" }
+
+// WithRef define o subflow local referenciado
+func (s *Subflow) WithRef(ref string) *Subflow {
+	cp := *s
+	cp.ref = ref
+	return &cp
+}
+
+// WithBot define o bot/versão externo referenciado
+func (s *Subflow) WithBot(botID, versionID string) *Subflow {
+	cp := *s
+	cp.botID = botID
+	cp.versionID = versionID
+	return &cp
+}
+
+// WithInputs define o mapeamento de parâmetros de entrada
+func (s *Subflow) WithInputs(inputs map[string]string) *Subflow {
+	cp := *s
+	cp.inputs = inputs
+	return &cp
+}
+
+// Spec gera o ComponentSpec para subflow (modo link, quando não inlined pelo compilador)
+func (s *Subflow) Spec(ctx context.Context, _ runtime.Context) (ComponentSpec, error) {
+	inputs := make(map[string]any, len(s.inputs))
+	for name, expr := range s.inputs {
+		meta, err := s.det.Parse(ctx, expr)
+		if err != nil {
+			return ComponentSpec{}, err
+		}
+		inputs[name] = TextValue{Raw: expr, Template: meta.IsTemplate, Liquid: meta}
+	}
+
+	metaData := map[string]any{
+		"ref":            s.ref,
+		"bot_id":         s.botID,
+		"version_id":     s.versionID,
+		"inputs":         inputs,
+		"component_type": "subflow",
+		// Outputs: os declarados pelo subflow referenciado
+	}
+
+	return ComponentSpec{
+		Kind: "subflow",
+		Meta: metaData,
+	}, nil
+}
+
+// SubflowFactory factory para criar componentes subflow
+type SubflowFactory struct{ det liquid.Detector }
+
+func NewSubflowFactory(det liquid.Detector) *SubflowFactory {
+	return &SubflowFactory{det: det}
+}
+
+func (f *SubflowFactory) New(_ string, props map[string]any) (Component, error) {
+	s := NewSubflow(f.det)
+
+	if ref, ok := props["ref"].(string); ok && ref != "" {
+		s = s.WithRef(ref)
+	}
+
+	botID, _ := props["bot_id"].(string)
+	versionID, _ := props["version_id"].(string)
+	if botID != "" {
+		s = s.WithBot(botID, versionID)
+	}
+
+	if inputsRaw, ok := props["inputs"].(map[string]any); ok {
+		inputs := make(map[string]string, len(inputsRaw))
+		for k, v := range inputsRaw {
+			if str, ok := v.(string); ok {
+				inputs[k] = str
+			}
+		}
+		s = s.WithInputs(inputs)
+	}
+
+	return s, nil
+}
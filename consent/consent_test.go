@@ -0,0 +1,119 @@
+package consent_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/consent"
+	"github.com/AgendoCerto/lib-bot/liquid"
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+func TestGateSkipsAcceptedVersion(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "consent.jsonl")
+	ledger, err := consent.OpenFileLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := component.NewTermsGateFactory(liquid.NoRenderDetector{}).New("terms", map[string]any{"version_id": "v2", "text": "Termos de uso"})
+	spec, _ := c.Spec(ctx, runtime.Context{})
+	terms := consent.TermsFromSpec("bot-a", spec)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	gate := consent.NewGate(ledger).WithClock(func() time.Time { return now })
+
+	if r, _ := gate.Check(ctx, "u1", terms); r.Output != "" {
+		t.Fatalf("first check = %q, want terms shown", r.Output)
+	}
+	r, err := gate.Record(ctx, terms, consent.Answer{UserID: "u1", Channel: "whatsapp", Payload: consent.PayloadAccept})
+	if err != nil || r.Output != consent.OutputAccepted || r.Record.TextHash != consent.HashText("Termos de uso") {
+		t.Fatalf("record = %+v, %v", r, err)
+	}
+	if _, err := gate.Record(ctx, terms, consent.Answer{UserID: "u1", Payload: "talvez"}); !errors.Is(err, consent.ErrInvalidDecision) {
+		t.Errorf("invalid payload err = %v", err)
+	}
+	ledger.Close()
+
+	// Reabrir preserva o histórico
+	ledger, err = consent.OpenFileLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gate = consent.NewGate(ledger)
+	if r, _ := gate.Check(ctx, "u1", terms); r.Output != consent.OutputSkipped {
+		t.Errorf("check after accept = %q", r.Output)
+	}
+	if r, _ := gate.Check(ctx, "u1", consent.Terms{BotID: "bot-a", Version: "v3"}); r.Output != "" {
+		t.Errorf("new version skipped")
+	}
+	if r, _ := gate.Check(ctx, "u1", consent.Terms{BotID: "bot-b", Version: "v2"}); r.Output != "" {
+		t.Errorf("same version of another bot skipped")
+	}
+
+	// Recusa posterior revoga o aceite
+	_, _ = gate.Record(ctx, terms, consent.Answer{UserID: "u1", Channel: "whatsapp", Payload: consent.PayloadReject})
+	if ok, _ := consent.HasAccepted(ctx, ledger, "u1", "bot-a", "v2"); ok {
+		t.Error("rejection did not revoke acceptance")
+	}
+	if history, _ := ledger.History(ctx, "u1"); len(history) != 2 || history[1].PrevHash != history[0].Hash {
+		t.Errorf("history = %+v", history)
+	}
+	if _, err := gate.Record(ctx, consent.Terms{Version: "v2"}, consent.Answer{UserID: "u1", Payload: consent.PayloadAccept}); !errors.Is(err, consent.ErrInvalidRecord) {
+		t.Errorf("record without bot err = %v", err)
+	}
+	ledger.Close()
+}
+
+func TestFileLedgerDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "consent.jsonl")
+	ledger, _ := consent.OpenFileLedger(path)
+	_, _ = ledger.Append(ctx, consent.Record{UserID: "u1", BotID: "bot-a", TermsVersion: "v1", TextHash: consent.HashText("t"), Decision: consent.Rejected, Timestamp: time.Now()})
+	ledger.Close()
+
+	raw, _ := os.ReadFile(path)
+	_ = os.WriteFile(path, []byte(strings.Replace(string(raw), `"rejected"`, `"accepted"`, 1)), 0o600)
+	if _, err := consent.OpenFileLedger(path); !errors.Is(err, consent.ErrCorrupt) {
+		t.Errorf("tampered ledger err = %v", err)
+	}
+}
+
+func TestFileLedgerDropsTornWrite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "consent.jsonl")
+	record := consent.Record{UserID: "u1", BotID: "bot-a", TermsVersion: "v1", TextHash: consent.HashText("t"), Decision: consent.Accepted, Timestamp: time.Now()}
+	ledger, _ := consent.OpenFileLedger(path)
+	_, _ = ledger.Append(ctx, record)
+	ledger.Close()
+
+	// Gravação interrompida no meio da linha
+	raw, _ := os.ReadFile(path)
+	_ = os.WriteFile(path, append(raw, raw[:len(raw)/2]...), 0o600)
+
+	ledger, err := consent.OpenFileLedger(path)
+	if err != nil {
+		t.Fatalf("open after torn write: %v", err)
+	}
+	second, err := ledger.Append(ctx, record)
+	if err != nil || second.Seq != 2 {
+		t.Fatalf("append after torn write = %+v, %v", second, err)
+	}
+	ledger.Close()
+
+	ledger, err = consent.OpenFileLedger(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer ledger.Close()
+	if history, _ := ledger.History(ctx, "u1"); len(history) != 2 || history[1].PrevHash != history[0].Hash {
+		t.Errorf("history = %+v", history)
+	}
+}
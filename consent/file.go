@@ -0,0 +1,156 @@
+package consent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileLedger Ledger em arquivo JSON Lines (um registro por linha)
+// O arquivo é aberto em modo append e cada inclusão é sincronizada em disco;
+// ao abrir, o encadeamento de hashes é verificado e os índices são montados em memória
+// Uma gravação interrompida (última linha sem quebra) é descartada: o arquivo volta
+// ao fim do último registro completo
+type FileLedger struct {
+	mu     sync.Mutex
+	file   *os.File
+	size   int64               // Fim do último registro completo
+	last   string              // Hash do último registro
+	seq    int64               // Seq do último registro
+	byUser map[string][]Record // Registros por usuário, em ordem
+}
+
+var _ Ledger = (*FileLedger)(nil)
+
+// OpenFileLedger abre (ou cria) o livro no caminho; falha com ErrCorrupt se o
+// arquivo foi alterado fora do livro
+func OpenFileLedger(path string) (*FileLedger, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &FileLedger{file: f, byUser: map[string][]Record{}}
+	if err := l.load(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("consent: open %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// load lê e verifica os registros existentes; uma linha final incompleta
+// (gravação interrompida) é truncada
+func (l *FileLedger) load() error {
+	reader := bufio.NewReaderSize(l.file, 64*1024)
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(raw) > 0 {
+				return l.truncate()
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		offset := l.size + int64(len(raw))
+		if raw = bytes.TrimSpace(raw); len(raw) == 0 {
+			l.size = offset
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return fmt.Errorf("%w: line %d: %w", ErrCorrupt, line, err)
+		}
+		if r.Seq != l.seq+1 || r.PrevHash != l.last || r.Hash != r.computeHash() {
+			return fmt.Errorf("%w: line %d", ErrCorrupt, line)
+		}
+		l.index(r)
+		l.size = offset
+	}
+}
+
+// truncate descarta o que foi gravado após o último registro completo
+func (l *FileLedger) truncate() error {
+	if err := l.file.Truncate(l.size); err != nil {
+		return fmt.Errorf("consent: truncate: %w", err)
+	}
+	return nil
+}
+
+// Append grava o registro ao final do livro
+func (l *FileLedger) Append(_ context.Context, r Record) (Record, error) {
+	if err := r.Validate(); err != nil {
+		return Record{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return Record{}, errors.New("consent: ledger closed")
+	}
+	r.Timestamp = r.Timestamp.UTC()
+	r.Seq, r.PrevHash = l.seq+1, l.last
+	r.Hash = r.computeHash()
+
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return Record{}, err
+	}
+	raw = append(raw, '\n')
+	if _, err := l.file.Write(raw); err != nil {
+		return Record{}, errors.Join(fmt.Errorf("consent: append: %w", err), l.truncate())
+	}
+	if err := l.file.Sync(); err != nil {
+		return Record{}, errors.Join(fmt.Errorf("consent: sync: %w", err), l.truncate())
+	}
+	l.index(r)
+	l.size += int64(len(raw))
+	return r, nil
+}
+
+// Latest última decisão do usuário para a versão dos termos do bot
+func (l *FileLedger) Latest(_ context.Context, userID, botID, termsVersion string) (Record, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records := l.byUser[userID]
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].BotID == botID && records[i].TermsVersion == termsVersion {
+			return records[i], true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// History todas as decisões do usuário, em ordem de registro
+func (l *FileLedger) History(_ context.Context, userID string) ([]Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Record(nil), l.byUser[userID]...), nil
+}
+
+// Close fecha o arquivo
+func (l *FileLedger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// index atualiza o encadeamento e os índices (chamar com o mutex ou durante load)
+func (l *FileLedger) index(r Record) {
+	l.seq, l.last = r.Seq, r.Hash
+	l.byUser[r.UserID] = append(l.byUser[r.UserID], r)
+}
@@ -0,0 +1,124 @@
+package consent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+)
+
+// ErrInvalidDecision resposta que não corresponde a aceitar/recusar
+var ErrInvalidDecision = errors.New("consent: invalid decision payload")
+
+// Saídas do componente terms_gate
+const (
+	OutputAccepted = "accepted"
+	OutputRejected = "rejected"
+	OutputSkipped  = "skipped" // Usuário já aceitou a versão: termo não é exibido
+)
+
+// Payloads dos botões do componente terms
+const (
+	PayloadAccept = "accept"
+	PayloadReject = "reject"
+)
+
+// Clock relógio injetável (time.Now por padrão)
+type Clock func() time.Time
+
+// Terms termo exibido: bot dono, versão e texto (props version_id e text)
+type Terms struct {
+	BotID   string
+	Version string
+	Text    string
+}
+
+// TermsFromSpec lê versão e texto do spec do component.TermsGate do bot
+func TermsFromSpec(botID string, spec component.ComponentSpec) Terms {
+	t := Terms{BotID: botID}
+	t.Version, _ = spec.Meta["version_id"].(string)
+	if spec.Text != nil {
+		t.Text = spec.Text.Raw
+	}
+	return t
+}
+
+// Answer resposta do usuário ao termo
+type Answer struct {
+	UserID    string
+	Channel   string
+	Payload   string // accept | reject
+	ShownText string // Texto efetivamente exibido (renderizado); vazio usa Terms.Text
+}
+
+// Result resultado do terms_gate
+type Result struct {
+	Output string  `json:"output,omitempty"` // Vazio: o termo deve ser exibido
+	Record *Record `json:"record,omitempty"` // Registro gravado (accepted/rejected)
+}
+
+// Gate decide a exibição do terms_gate e registra as respostas no livro
+type Gate struct {
+	ledger Ledger
+	now    Clock
+}
+
+// NewGate cria o gate sobre o livro de consentimentos
+func NewGate(ledger Ledger) *Gate {
+	return &Gate{ledger: ledger, now: time.Now}
+}
+
+// WithClock define o relógio (timestamp dos registros)
+func (g *Gate) WithClock(now Clock) *Gate {
+	cp := *g
+	cp.now = now
+	return &cp
+}
+
+// Check retorna skipped quando o usuário já aceitou a versão dos termos do bot;
+// caso contrário Output vazio (exibir o termo)
+func (g *Gate) Check(ctx context.Context, userID string, terms Terms) (Result, error) {
+	accepted, err := HasAccepted(ctx, g.ledger, userID, terms.BotID, terms.Version)
+	if err != nil {
+		return Result{}, fmt.Errorf("consent check %s: %w", userID, err)
+	}
+	if accepted {
+		return Result{Output: OutputSkipped}, nil
+	}
+	return Result{}, nil
+}
+
+// Record registra a resposta (usuário, bot, versão, hash do texto exibido,
+// horário, canal e decisão) e retorna a saída accepted/rejected
+func (g *Gate) Record(ctx context.Context, terms Terms, answer Answer) (Result, error) {
+	var decision Decision
+	var output string
+	switch answer.Payload {
+	case PayloadAccept:
+		decision, output = Accepted, OutputAccepted
+	case PayloadReject:
+		decision, output = Rejected, OutputRejected
+	default:
+		return Result{}, fmt.Errorf("%w: %q", ErrInvalidDecision, answer.Payload)
+	}
+
+	shown := answer.ShownText
+	if shown == "" {
+		shown = terms.Text
+	}
+	r, err := g.ledger.Append(ctx, Record{
+		UserID:       answer.UserID,
+		BotID:        terms.BotID,
+		TermsVersion: terms.Version,
+		TextHash:     HashText(shown),
+		Channel:      answer.Channel,
+		Decision:     decision,
+		Timestamp:    g.now(),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("consent record %s: %w", answer.UserID, err)
+	}
+	return Result{Output: output, Record: &r}, nil
+}
@@ -0,0 +1,95 @@
+// Package consent registra aceites de termos (LGPD) dos componentes terms e
+// terms_gate: livro-razão somente de inclusão com encadeamento de hashes,
+// implementação em arquivo e consulta "o usuário aceitou a versão X do bot Y"
+package consent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Erros do livro de consentimentos
+var (
+	ErrInvalidRecord = errors.New("consent: invalid record")
+	ErrCorrupt       = errors.New("consent: ledger integrity check failed")
+)
+
+// Decision decisão do usuário
+type Decision string
+
+const (
+	Accepted Decision = "accepted"
+	Rejected Decision = "rejected"
+)
+
+// Record registro imutável de uma decisão
+// Hash cobre todos os campos e o Hash do registro anterior (PrevHash), de modo
+// que qualquer alteração ou remoção no meio do livro é detectada
+type Record struct {
+	Seq          int64     `json:"seq"`
+	UserID       string    `json:"user_id"`
+	BotID        string    `json:"bot_id"` // Bot dono dos termos (versões como "v2" se repetem entre bots)
+	TermsVersion string    `json:"terms_version"`
+	TextHash     string    `json:"text_hash"` // SHA-256 do texto exibido (HashText)
+	Channel      string    `json:"channel"`
+	Decision     Decision  `json:"decision"`
+	Timestamp    time.Time `json:"timestamp"`
+	PrevHash     string    `json:"prev_hash,omitempty"`
+	Hash         string    `json:"hash"`
+}
+
+// Validate verifica os campos obrigatórios
+func (r Record) Validate() error {
+	switch {
+	case r.UserID == "":
+		return fmt.Errorf("%w: missing user_id", ErrInvalidRecord)
+	case r.BotID == "":
+		return fmt.Errorf("%w: missing bot_id", ErrInvalidRecord)
+	case r.TermsVersion == "":
+		return fmt.Errorf("%w: missing terms_version", ErrInvalidRecord)
+	case r.TextHash == "":
+		return fmt.Errorf("%w: missing text_hash", ErrInvalidRecord)
+	case r.Decision != Accepted && r.Decision != Rejected:
+		return fmt.Errorf("%w: decision must be accepted or rejected", ErrInvalidRecord)
+	case r.Timestamp.IsZero():
+		return fmt.Errorf("%w: missing timestamp", ErrInvalidRecord)
+	}
+	return nil
+}
+
+// computeHash hash do registro encadeado ao anterior
+func (r Record) computeHash() string {
+	r.Hash = ""
+	raw, _ := json.Marshal(r)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// HashText hash do texto exibido ao usuário (prova de qual texto foi aceito)
+func HashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Ledger livro de consentimentos somente de inclusão
+// Append preenche Seq, PrevHash e Hash; registros nunca são alterados ou removidos
+type Ledger interface {
+	Append(ctx context.Context, r Record) (Record, error)
+	Latest(ctx context.Context, userID, botID, termsVersion string) (Record, bool, error) // Última decisão do usuário para a versão dos termos do bot
+	History(ctx context.Context, userID string) ([]Record, error)                         // Todas as decisões do usuário (todos os bots), em ordem
+}
+
+// HasAccepted indica se a última decisão do usuário para a versão dos termos do
+// bot foi aceite (uma recusa posterior revoga o aceite)
+func HasAccepted(ctx context.Context, ledger Ledger, userID, botID, termsVersion string) (bool, error) {
+	r, ok, err := ledger.Latest(ctx, userID, botID, termsVersion)
+	if err != nil || !ok {
+		return false, err
+	}
+	return r.Decision == Accepted, nil
+}
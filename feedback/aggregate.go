@@ -0,0 +1,222 @@
+package feedback
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Response resposta armazenada (uma por envio do feedback)
+type Response struct {
+	BotVersion string    `json:"bot_version"`
+	NodeID     string    `json:"node_id"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Scale      Scale     `json:"scale"`
+	Score      int       `json:"score,omitempty"` // 0 na escala text
+	Comment    string    `json:"comment,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// NewResponse monta a resposta a partir da Answer interpretada
+func NewResponse(botVersion, nodeID, sessionID string, a Answer, at time.Time) Response {
+	return Response{
+		BotVersion: botVersion,
+		NodeID:     nodeID,
+		SessionID:  sessionID,
+		Scale:      a.Scale,
+		Score:      a.Score,
+		Comment:    a.Comment,
+		At:         at,
+	}
+}
+
+// NPS Net Promoter Score das respostas nas escalas 0-10 e 1-10
+// Promotores 9-10, neutros 7-8, detratores 0-6; Score = %promotores - %detratores
+type NPS struct {
+	Responses  int     `json:"responses"`
+	Promoters  int     `json:"promoters"`
+	Passives   int     `json:"passives"`
+	Detractors int     `json:"detractors"`
+	Average    float64 `json:"average"`
+	Score      float64 `json:"score"` // -100..100
+}
+
+// CSAT satisfação das respostas nas escalas de 5 pontos (1-5 e emoji)
+// Satisfeitos são notas 4-5; Score = %satisfeitos
+type CSAT struct {
+	Responses int     `json:"responses"`
+	Satisfied int     `json:"satisfied"`
+	Average   float64 `json:"average"`
+	Score     float64 `json:"score"` // 0..100
+}
+
+// Stats métricas de um grupo (versão do bot e nó)
+// NodeID vazio indica o consolidado da versão
+type Stats struct {
+	BotVersion string `json:"bot_version"`
+	NodeID     string `json:"node_id,omitempty"`
+	Responses  int    `json:"responses"`      // Todas as respostas, inclusive texto
+	Comments   int    `json:"comments"`       // Respostas na escala text
+	NPS        *NPS   `json:"nps,omitempty"`  // nil sem respostas 0-10 ou 1-10
+	CSAT       *CSAT  `json:"csat,omitempty"` // nil sem respostas de 5 pontos
+	sum10      int    // Soma das notas 0-10 e 1-10
+	sum5       int    // Soma das notas de 5 pontos
+}
+
+// add contabiliza a resposta no grupo
+func (s *Stats) add(r Response) {
+	s.Responses++
+	switch r.Scale {
+	case ScaleText:
+		s.Comments++
+	case Scale10, ScaleNPS:
+		if s.NPS == nil {
+			s.NPS = &NPS{}
+		}
+		s.NPS.Responses++
+		s.sum10 += r.Score
+		switch {
+		case r.Score >= 9:
+			s.NPS.Promoters++
+		case r.Score >= 7:
+			s.NPS.Passives++
+		default:
+			s.NPS.Detractors++
+		}
+		n := float64(s.NPS.Responses)
+		s.NPS.Average = float64(s.sum10) / n
+		s.NPS.Score = 100 * float64(s.NPS.Promoters-s.NPS.Detractors) / n
+	case Scale5, ScaleEmoji:
+		if s.CSAT == nil {
+			s.CSAT = &CSAT{}
+		}
+		s.CSAT.Responses++
+		s.sum5 += r.Score
+		if r.Score >= 4 {
+			s.CSAT.Satisfied++
+		}
+		n := float64(s.CSAT.Responses)
+		s.CSAT.Average = float64(s.sum5) / n
+		s.CSAT.Score = 100 * float64(s.CSAT.Satisfied) / n
+	}
+}
+
+// Aggregator agrega respostas por versão do bot e por nó
+// Respostas inválidas (nota fora da escala ou escala desconhecida) são ignoradas
+type Aggregator struct {
+	groups map[[2]string]*Stats
+}
+
+// NewAggregator cria agregador vazio
+func NewAggregator() *Aggregator {
+	return &Aggregator{groups: map[[2]string]*Stats{}}
+}
+
+// Aggregate agrega as respostas armazenadas
+func Aggregate(responses []Response) []Stats {
+	a := NewAggregator()
+	for _, r := range responses {
+		a.Add(r)
+	}
+	return a.Stats()
+}
+
+// Add contabiliza a resposta no nó e no consolidado da versão (false se ignorada)
+func (a *Aggregator) Add(r Response) bool {
+	if !r.Scale.Valid() {
+		return false
+	}
+	if lo, hi := r.Scale.Bounds(); r.Scale.Scored() && (r.Score < lo || r.Score > hi) {
+		return false
+	}
+	a.group(r.BotVersion, "").add(r)
+	a.group(r.BotVersion, r.NodeID).add(r)
+	return true
+}
+
+// group grupo (criado sob demanda)
+func (a *Aggregator) group(version, node string) *Stats {
+	key := [2]string{version, node}
+	s, ok := a.groups[key]
+	if !ok {
+		s = &Stats{BotVersion: version, NodeID: node}
+		a.groups[key] = s
+	}
+	return s
+}
+
+// Stats métricas ordenadas por versão e nó (consolidado da versão primeiro)
+func (a *Aggregator) Stats() []Stats {
+	out := make([]Stats, 0, len(a.groups))
+	for _, s := range a.groups {
+		cp := *s
+		if s.NPS != nil {
+			nps := *s.NPS
+			cp.NPS = &nps
+		}
+		if s.CSAT != nil {
+			csat := *s.CSAT
+			cp.CSAT = &csat
+		}
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].BotVersion != out[j].BotVersion {
+			return out[i].BotVersion < out[j].BotVersion
+		}
+		return out[i].NodeID < out[j].NodeID
+	})
+	return out
+}
+
+// WriteJSON exporta as métricas como array JSON
+func WriteJSON(w io.Writer, stats []Stats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stats)
+}
+
+// csvHeader colunas do CSV exportado
+var csvHeader = []string{
+	"bot_version", "node_id", "responses", "comments",
+	"nps_responses", "promoters", "passives", "detractors", "nps_average", "nps",
+	"csat_responses", "satisfied", "csat_average", "csat",
+}
+
+// WriteCSV exporta as métricas em CSV (colunas de NPS/CSAT vazias quando não há
+// respostas na escala correspondente)
+func WriteCSV(w io.Writer, stats []Stats) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, s := range stats {
+		row := []string{s.BotVersion, s.NodeID, strconv.Itoa(s.Responses), strconv.Itoa(s.Comments)}
+		if s.NPS != nil {
+			row = append(row, strconv.Itoa(s.NPS.Responses), strconv.Itoa(s.NPS.Promoters),
+				strconv.Itoa(s.NPS.Passives), strconv.Itoa(s.NPS.Detractors),
+				formatFloat(s.NPS.Average), formatFloat(s.NPS.Score))
+		} else {
+			row = append(row, "", "", "", "", "", "")
+		}
+		if s.CSAT != nil {
+			row = append(row, strconv.Itoa(s.CSAT.Responses), strconv.Itoa(s.CSAT.Satisfied),
+				formatFloat(s.CSAT.Average), formatFloat(s.CSAT.Score))
+		} else {
+			row = append(row, "", "", "", "")
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatFloat duas casas decimais
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
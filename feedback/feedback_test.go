@@ -0,0 +1,141 @@
+package feedback_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/feedback"
+	"github.com/AgendoCerto/lib-bot/liquid"
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		reply  string
+		scale  feedback.Scale
+		output string
+		score  int
+	}{
+		{"5", feedback.Scale5, feedback.OutputSubmitted, 5},
+		{"nota 8/10", feedback.Scale10, feedback.OutputSubmitted, 8},
+		{"Três", feedback.Scale5, feedback.OutputSubmitted, 3},
+		{"dez!", feedback.Scale10, feedback.OutputSubmitted, 10},
+		{"😍😍", feedback.ScaleEmoji, feedback.OutputSubmitted, 5},
+		{"😡", feedback.Scale5, feedback.OutputSubmitted, 1},
+		{"7️⃣", feedback.Scale10, feedback.OutputSubmitted, 7},
+		{"🔟", feedback.Scale10, feedback.OutputSubmitted, 10},
+		{"gostei muito", feedback.ScaleText, feedback.OutputSubmitted, 0},
+		{"8", feedback.Scale5, feedback.OutputInvalid, 0},
+		{"0", feedback.Scale10, feedback.OutputInvalid, 0},
+		{"0", feedback.ScaleNPS, feedback.OutputSubmitted, 0},
+		{"zero", feedback.ScaleNPS, feedback.OutputSubmitted, 0},
+		{"nota 10", feedback.ScaleNPS, feedback.OutputSubmitted, 10},
+		{"11", feedback.ScaleNPS, feedback.OutputInvalid, 0},
+		{"4,5", feedback.Scale5, feedback.OutputInvalid, 0},
+		{"😍", feedback.Scale10, feedback.OutputInvalid, 0},
+		{"nenhum comentário", feedback.Scale5, feedback.OutputInvalid, 0},
+		{"foi uma experiência ótima", feedback.Scale10, feedback.OutputInvalid, 0},
+		{"gostei, tenho dois filhos", feedback.Scale5, feedback.OutputInvalid, 0},
+		{"uma", feedback.Scale5, feedback.OutputSubmitted, 1},
+		{"nota um", feedback.Scale5, feedback.OutputSubmitted, 1},
+		{"dou um dez", feedback.Scale10, feedback.OutputSubmitted, 10},
+		{"Dou uma nota oito pra vocês", feedback.Scale10, feedback.OutputSubmitted, 8},
+		{"  ", feedback.ScaleText, feedback.OutputInvalid, 0},
+		{"5", "stars", feedback.OutputInvalid, 0},
+	}
+	for _, c := range cases {
+		a, err := feedback.Parse(c.reply, c.scale)
+		if a.Output != c.output || a.Score != c.score {
+			t.Errorf("Parse(%q, %s) = %+v, %v", c.reply, c.scale, a, err)
+		}
+		if (err == nil) != (c.output == feedback.OutputSubmitted) {
+			t.Errorf("Parse(%q, %s) err = %v", c.reply, c.scale, err)
+		}
+	}
+
+	if _, err := feedback.Parse("11", feedback.Scale10); !errors.Is(err, feedback.ErrOutOfRange) {
+		t.Errorf("out of range err = %v", err)
+	}
+}
+
+func TestScaleFromSpec(t *testing.T) {
+	spec, err := component.NewFeedback(liquid.NoRenderDetector{}).WithScale("1-10").Spec(context.Background(), runtime.Context{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := feedback.ScaleFromSpec(spec); got != feedback.Scale10 {
+		t.Errorf("ScaleFromSpec = %q", got)
+	}
+	if got := feedback.ScaleFromSpec(component.ComponentSpec{}); got != feedback.ScaleText {
+		t.Errorf("default scale = %q", got)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	responses := []feedback.Response{
+		{BotVersion: "v1", NodeID: "nps", Scale: feedback.Scale10, Score: 10},
+		{BotVersion: "v1", NodeID: "nps", Scale: feedback.Scale10, Score: 9},
+		{BotVersion: "v1", NodeID: "nps", Scale: feedback.Scale10, Score: 7},
+		{BotVersion: "v1", NodeID: "nps", Scale: feedback.Scale10, Score: 3},
+		{BotVersion: "v1", NodeID: "csat", Scale: feedback.ScaleEmoji, Score: 5},
+		{BotVersion: "v1", NodeID: "csat", Scale: feedback.Scale5, Score: 2},
+		{BotVersion: "v1", NodeID: "comment", Scale: feedback.ScaleText, Comment: "ok"},
+		{BotVersion: "v1", NodeID: "nps", Scale: feedback.Scale10, Score: 42}, // inválida
+		{BotVersion: "v2", NodeID: "nps", Scale: feedback.Scale10, Score: 6},
+	}
+	stats := feedback.Aggregate(responses)
+	if len(stats) != 6 || stats[0].NodeID != "" || stats[0].BotVersion != "v1" {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	total := stats[0]
+	if total.Responses != 7 || total.Comments != 1 || total.NPS.Score != 25 || total.CSAT.Score != 50 {
+		t.Errorf("v1 total = %+v nps=%+v csat=%+v", total, total.NPS, total.CSAT)
+	}
+	if byNode := stats[2]; byNode.NodeID != "csat" || byNode.NPS != nil || byNode.CSAT.Average != 3.5 {
+		t.Errorf("csat node = %+v", byNode)
+	}
+	if v2 := stats[4]; v2.BotVersion != "v2" || v2.NPS.Score != -100 {
+		t.Errorf("v2 total = %+v", v2)
+	}
+
+	var csv bytes.Buffer
+	if err := feedback.WriteCSV(&csv, stats); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 7 || lines[1] != "v1,,7,1,4,2,1,1,7.25,25.00,2,1,3.50,50.00" {
+		t.Errorf("csv = %q", lines)
+	}
+
+	var js bytes.Buffer
+	if err := feedback.WriteJSON(&js, stats[:1]); err != nil || !strings.Contains(js.String(), `"score": 25`) {
+		t.Errorf("json = %s, %v", js.String(), err)
+	}
+}
+
+func TestAggregateNPSZero(t *testing.T) {
+	a, err := feedback.Parse("0", feedback.ScaleNPS)
+	if err != nil || a.Output != feedback.OutputSubmitted || a.Score != 0 {
+		t.Fatalf("Parse(0, 0-10) = %+v, %v", a, err)
+	}
+
+	agg := feedback.NewAggregator()
+	for _, score := range []int{0, 10} {
+		if !agg.Add(feedback.Response{BotVersion: "v1", NodeID: "nps", Scale: feedback.ScaleNPS, Score: score}) {
+			t.Fatalf("Add(%d) rejected", score)
+		}
+	}
+	if agg.Add(feedback.Response{BotVersion: "v1", NodeID: "nps", Scale: feedback.ScaleNPS, Score: 11}) {
+		t.Error("Add(11) accepted on 0-10")
+	}
+
+	nps := agg.Stats()[0].NPS
+	if nps == nil || nps.Responses != 2 || nps.Detractors != 1 || nps.Promoters != 1 || nps.Average != 5 || nps.Score != 0 {
+		t.Errorf("nps = %+v", nps)
+	}
+}
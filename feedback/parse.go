@@ -0,0 +1,218 @@
+// Package feedback interpreta respostas do componente feedback na escala
+// configurada (1-5, 1-10, 0-10, emoji ou texto) e agrega as notas em NPS/CSAT por
+// versão do bot e por nó, com exportação em JSON e CSV
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Erros de interpretação (todos levam à saída invalid)
+var (
+	ErrEmptyReply   = errors.New("feedback: empty reply")
+	ErrNoScore      = errors.New("feedback: reply has no score")
+	ErrOutOfRange   = errors.New("feedback: score out of range")
+	ErrUnknownScale = errors.New("feedback: unknown scale")
+)
+
+// Saídas do componente feedback
+const (
+	OutputSubmitted = "submitted"
+	OutputInvalid   = "invalid"
+)
+
+// Scale escala de avaliação (meta "scale" do component.Feedback)
+type Scale string
+
+const (
+	Scale5     Scale = "1-5"
+	Scale10    Scale = "1-10"
+	ScaleNPS   Scale = "0-10"  // Escala NPS padrão: 0 é nota válida (detrator)
+	ScaleEmoji Scale = "emoji" // 😡 😞 😐 🙂 😍 → 1..5
+	ScaleText  Scale = "text"  // Texto livre, sem nota
+)
+
+// Bounds menor e maior nota aceitas pela escala (0, 0 para texto)
+func (s Scale) Bounds() (lo, hi int) {
+	switch s {
+	case Scale5, ScaleEmoji:
+		return 1, 5
+	case Scale10:
+		return 1, 10
+	case ScaleNPS:
+		return 0, 10
+	}
+	return 0, 0
+}
+
+// Scored indica se a escala produz nota numérica
+func (s Scale) Scored() bool {
+	_, hi := s.Bounds()
+	return hi > 0
+}
+
+// Valid indica se a escala é conhecida
+func (s Scale) Valid() bool {
+	return s == ScaleText || s.Scored()
+}
+
+// ScaleFromSpec lê a escala do spec do component.Feedback (padrão: text)
+func ScaleFromSpec(spec component.ComponentSpec) Scale {
+	if s, ok := spec.Meta["scale"].(string); ok && s != "" {
+		return Scale(s)
+	}
+	return ScaleText
+}
+
+// Answer resposta interpretada
+type Answer struct {
+	Output  string `json:"output"`  // submitted | invalid
+	Scale   Scale  `json:"scale"`   // Escala usada na interpretação
+	Score   int    `json:"score"`   // Nota (0 na escala text ou quando inválida; na 0-10, 0 também é nota)
+	Comment string `json:"comment"` // Resposta original, sem espaços nas bordas
+}
+
+// emojiScores escada de emojis (e variações comuns) para notas 1..5
+var emojiScores = map[rune]int{
+	'😡': 1, '😠': 1, '🤬': 1,
+	'😞': 2, '🙁': 2, '☹': 2, '😕': 2,
+	'😐': 3, '😑': 3, '😶': 3,
+	'🙂': 4, '😊': 4, '😀': 4, '😃': 4, '👍': 4,
+	'😍': 5, '🤩': 5, '🥰': 5, '😁': 5,
+}
+
+// numberWords números por extenso (pt-BR e inglês)
+var numberWords = map[string]int{
+	"zero": 0, "um": 1, "uma": 1, "dois": 2, "duas": 2, "tres": 3, "quatro": 4,
+	"cinco": 5, "seis": 6, "sete": 7, "oito": 8, "nove": 9, "dez": 10,
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// scoreCues palavras que antecedem a nota por extenso ("nota oito", "dou dez")
+var scoreCues = map[string]bool{
+	"nota": true, "dou": true, "daria": true, "dei": true,
+	"score": true, "rating": true, "rate": true, "give": true,
+}
+
+// Parse interpreta a resposta na escala
+// Aceita dígitos ("8", "nota 8", "8/10", "8️⃣"), números por extenso quando são a
+// resposta inteira ou seguem uma palavra de nota ("oito", "nota oito") e,
+// nas escalas de 5 pontos, a escada de emojis; nota fora da escala, resposta
+// sem nota ou escala desconhecida retornam Output invalid com o erro
+func Parse(reply string, scale Scale) (Answer, error) {
+	a := Answer{Output: OutputInvalid, Scale: scale, Comment: strings.TrimSpace(reply)}
+	if !scale.Valid() {
+		return a, fmt.Errorf("%w: %q", ErrUnknownScale, scale)
+	}
+	if a.Comment == "" {
+		return a, ErrEmptyReply
+	}
+	if scale == ScaleText {
+		a.Output = OutputSubmitted
+		return a, nil
+	}
+
+	score, err := extractScore(a.Comment, scale)
+	if err != nil {
+		return a, err
+	}
+	if lo, hi := scale.Bounds(); score < lo || score > hi {
+		return a, fmt.Errorf("%w: %d not in %s", ErrOutOfRange, score, scale)
+	}
+	a.Output, a.Score = OutputSubmitted, score
+	return a, nil
+}
+
+// extractScore primeira nota encontrada: dígitos, emoji ou número por extenso
+func extractScore(reply string, scale Scale) (int, error) {
+	// Keycaps (8️⃣, 🔟) viram dígitos antes da busca
+	reply = strings.NewReplacer("\u20e3", "", "\ufe0f", "", "🔟", "10").Replace(reply)
+
+	if n, ok, err := firstNumber(reply); ok || err != nil {
+		return n, err
+	}
+	if _, hi := scale.Bounds(); hi == 5 {
+		for _, r := range reply {
+			if n, ok := emojiScores[r]; ok {
+				return n, nil
+			}
+		}
+	}
+	if n, ok := wordScore(strings.FieldsFunc(fold(reply), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})); ok {
+		return n, nil
+	}
+	return 0, ErrNoScore
+}
+
+// wordScore nota por extenso: a resposta inteira ("oito") ou a palavra após uma
+// palavra de nota ("nota oito"); "um"/"uma" seguidos de número ou de outra palavra
+// de nota são artigos ("dou uma nota oito"), e no meio da frase não contam como
+// nota ("foi uma experiência ótima")
+func wordScore(words []string) (int, bool) {
+	if len(words) == 1 {
+		n, ok := numberWords[words[0]]
+		return n, ok
+	}
+
+	kept := make([]string, 0, len(words))
+	for i, w := range words {
+		if (w == "um" || w == "uma") && i+1 < len(words) {
+			if _, number := numberWords[words[i+1]]; number || scoreCues[words[i+1]] {
+				continue
+			}
+		}
+		kept = append(kept, w)
+	}
+	for i := 1; i < len(kept); i++ {
+		if n, ok := numberWords[kept[i]]; ok && scoreCues[kept[i-1]] {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// firstNumber primeiro inteiro da resposta; sinais e decimais ("-3", "4,5") são
+// fora de escala
+func firstNumber(s string) (int, bool, error) {
+	start := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' })
+	if start < 0 {
+		return 0, false, nil
+	}
+	end := start
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if start > 0 && s[start-1] == '-' {
+		return 0, true, fmt.Errorf("%w: negative score", ErrOutOfRange)
+	}
+	if end+1 < len(s) && (s[end] == ',' || s[end] == '.') && s[end+1] >= '0' && s[end+1] <= '9' {
+		return 0, true, fmt.Errorf("%w: fractional score", ErrOutOfRange)
+	}
+	n, err := strconv.Atoi(s[start:end])
+	if err != nil {
+		return 0, true, fmt.Errorf("%w: %s", ErrOutOfRange, s[start:end])
+	}
+	return n, true, nil
+}
+
+// fold normaliza texto para comparação: minúsculas e sem acentos
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(s))
+	if err != nil {
+		return strings.ToLower(s)
+	}
+	return folded
+}
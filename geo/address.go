@@ -0,0 +1,229 @@
+// Package geo interpreta endereços brasileiros digitados e resolve CEP/coordenadas
+// para os componentes location_capture (modo type_address) e geo_resolve
+package geo
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AgendoCerto/lib-bot/sanitize"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrEmptyAddress o texto não contém um endereço reconhecível (CEP ou ao menos dois campos)
+var ErrEmptyAddress = errors.New("geo: empty address")
+
+// Address endereço brasileiro estruturado
+type Address struct {
+	Street       string `json:"street,omitempty"`       // Logradouro com tipo expandido: "Avenida Paulista"
+	Number       string `json:"number,omitempty"`       // Número ("S/N" quando sem número)
+	Complement   string `json:"complement,omitempty"`   // "Apto 12", "Bloco B"
+	Neighborhood string `json:"neighborhood,omitempty"` // Bairro
+	City         string `json:"city,omitempty"`
+	UF           string `json:"uf,omitempty"`  // Sigla do estado em maiúsculas
+	CEP          string `json:"cep,omitempty"` // Formatado: 01310-100
+}
+
+// IsZero indica se nenhum campo foi preenchido
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// String formata no padrão dos Correios: "Rua X, 123, Apto 4 - Centro, São Paulo - SP, 01310-100"
+func (a Address) String() string {
+	var b strings.Builder
+	b.WriteString(a.Street)
+	for _, part := range []string{a.Number, a.Complement} {
+		if part != "" {
+			if b.Len() > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(part)
+		}
+	}
+
+	var tail []string
+	if a.Neighborhood != "" {
+		if b.Len() > 0 {
+			b.WriteString(" - ")
+		}
+		b.WriteString(a.Neighborhood)
+	}
+	city := a.City
+	if a.UF != "" {
+		city = strings.TrimSpace(city + " - " + a.UF)
+		city = strings.TrimPrefix(city, "- ")
+	}
+	if city != "" {
+		tail = append(tail, city)
+	}
+	if a.CEP != "" {
+		tail = append(tail, a.CEP)
+	}
+	for _, part := range tail {
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// UFs siglas dos estados brasileiros
+var UFs = []string{
+	"AC", "AL", "AM", "AP", "BA", "CE", "DF", "ES", "GO", "MA", "MG", "MS", "MT", "PA",
+	"PB", "PE", "PI", "PR", "RJ", "RN", "RO", "RR", "RS", "SC", "SE", "SP", "TO",
+}
+
+var (
+	cepPattern        = regexp.MustCompile(`(?i)(?:\bcep[:\s]*)?\b(\d{2}\.?\d{3})-?(\d{3})\b`)
+	ufPattern         = regexp.MustCompile(`(?:^|[\s,/-])(` + strings.Join(UFs, "|") + `)[\s.]*$`)
+	ufLoosePattern    = regexp.MustCompile(`(?i)[/-]\s*(` + strings.Join(UFs, "|") + `)[\s.]*$`)
+	separatorPattern  = regexp.MustCompile(`\s*,\s*|\s+-\s+|\s*;\s*`)
+	numberPattern     = regexp.MustCompile(`(?i)^(?:n[º°o]?\.?\s*|numero\s*|número\s*)?(\d+[a-z]?|s/?n)$`)
+	streetNumPattern  = regexp.MustCompile(`(?i)^(.*?\D)\s+(?:n[º°o]?\.?\s*)?(\d+[a-z]?|s/?n)$`)
+	complementPattern = regexp.MustCompile(`(?i)^(apto|apartamento|ap|bloco|bl|casa|sala|cj|conjunto|lote|lt|quadra|qd|andar|fundos|loja|torre)\b\.?`)
+	abbrevPattern     = regexp.MustCompile(`(?i)^(r|av|al|tv|trav|estr|rod|pc|pca|praça|lgo|est)\.?\s+`)
+)
+
+// streetTypes expansão das abreviações de logradouro
+var streetTypes = map[string]string{
+	"r": "Rua", "av": "Avenida", "al": "Alameda", "tv": "Travessa", "trav": "Travessa",
+	"estr": "Estrada", "est": "Estrada", "rod": "Rodovia", "pc": "Praça", "pca": "Praça",
+	"praça": "Praça", "lgo": "Largo",
+}
+
+// ParseAddress interpreta um endereço digitado em texto livre
+// Reconhece CEP em qualquer posição, UF no final ("São Paulo - SP", "Curitiba/PR"),
+// número junto ou separado do logradouro e complementos comuns (apto, bloco, casa...)
+// Sem UF, um único campo depois do logradouro é tratado como bairro
+func ParseAddress(text string) (Address, error) {
+	var addr Address
+	rest := strings.TrimSpace(strings.Join(strings.Fields(text), " "))
+
+	if m := cepPattern.FindStringSubmatchIndex(rest); m != nil {
+		digits := strings.ReplaceAll(rest[m[2]:m[3]], ".", "") + rest[m[4]:m[5]]
+		addr.CEP, _ = sanitize.Default().Sanitize(digits, sanitize.Config{Type: sanitize.CEP})
+		rest = strings.TrimSpace(rest[:m[0]] + " " + rest[m[1]:])
+	}
+	rest = strings.Trim(rest, " ,;-")
+
+	// UF em maiúsculas no final, ou em minúsculas quando separada por "-" ou "/" ("curitiba/pr")
+	m := ufPattern.FindStringSubmatchIndex(rest)
+	if m == nil {
+		m = ufLoosePattern.FindStringSubmatchIndex(rest)
+	}
+	if m != nil {
+		addr.UF = strings.ToUpper(rest[m[2]:m[3]])
+		rest = strings.Trim(rest[:m[2]], " ,;-/")
+	}
+
+	var parts []string
+	for _, p := range separatorPattern.Split(rest, -1) {
+		if p = strings.Trim(p, " ."); strings.ContainsFunc(p, isAlnum) {
+			parts = append(parts, p)
+		}
+	}
+
+	// Logradouro (com número opcional na mesma parte)
+	if len(parts) > 0 && !numberPattern.MatchString(parts[0]) {
+		street := parts[0]
+		if m := streetNumPattern.FindStringSubmatch(street); m != nil {
+			street, addr.Number = m[1], normalizeNumber(m[2])
+		}
+		parts = parts[1:]
+		if len(parts) > 0 || addr.Number != "" || addr.UF == "" {
+			addr.Street = expandStreetType(street)
+		} else {
+			// Apenas "Cidade - UF"
+			addr.City = street
+		}
+	}
+	if addr.Number == "" && len(parts) > 0 && numberPattern.MatchString(parts[0]) {
+		addr.Number = normalizeNumber(numberPattern.FindStringSubmatch(parts[0])[1])
+		parts = parts[1:]
+	}
+
+	var complements []string
+	for len(parts) > 0 && complementPattern.MatchString(parts[0]) {
+		complements = append(complements, capitalize(parts[0]))
+		parts = parts[1:]
+	}
+	addr.Complement = strings.Join(complements, ", ")
+
+	switch {
+	case len(parts) >= 2:
+		addr.Neighborhood = strings.Join(parts[:len(parts)-1], ", ")
+		addr.City = parts[len(parts)-1]
+	case len(parts) == 1 && addr.UF != "":
+		addr.City = parts[0]
+	case len(parts) == 1:
+		addr.Neighborhood = parts[0]
+	}
+
+	// Um único campo solto ("sim", "Centro") não é endereço
+	if addr.CEP == "" && addr.fieldCount() < 2 {
+		return Address{}, ErrEmptyAddress
+	}
+	return addr, nil
+}
+
+// fieldCount quantidade de campos preenchidos
+func (a Address) fieldCount() int {
+	count := 0
+	for _, field := range []string{a.Street, a.Number, a.Complement, a.Neighborhood, a.City, a.UF, a.CEP} {
+		if field != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// expandStreetType troca a abreviação do tipo de logradouro pelo nome completo ("Av." → "Avenida")
+func expandStreetType(street string) string {
+	m := abbrevPattern.FindStringSubmatch(street)
+	if m == nil {
+		return capitalize(street)
+	}
+	name, ok := streetTypes[strings.ToLower(m[1])]
+	if !ok {
+		return capitalize(street)
+	}
+	return name + " " + capitalize(street[len(m[0]):])
+}
+
+// normalizeNumber padroniza "s/n" e números com letra ("12a" → "12A")
+func normalizeNumber(number string) string {
+	if strings.EqualFold(strings.ReplaceAll(number, "/", ""), "sn") {
+		return "S/N"
+	}
+	return strings.ToUpper(number)
+}
+
+// capitalize garante a primeira letra maiúscula sem alterar o restante
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// fold normaliza texto para comparação: minúsculas, sem acentos e espaços extras
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(s))
+	if err != nil {
+		folded = strings.ToLower(s)
+	}
+	return strings.Join(strings.Fields(folded), " ")
+}
@@ -0,0 +1,112 @@
+package geo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/geo"
+	"github.com/AgendoCerto/lib-bot/persistence"
+)
+
+func TestParseAddress(t *testing.T) {
+	cases := []struct {
+		input string
+		want  geo.Address
+	}{
+		{"Rua das Flores, 123 - Centro, São Paulo - SP, 01310-100", geo.Address{Street: "Rua das Flores", Number: "123", Neighborhood: "Centro", City: "São Paulo", UF: "SP", CEP: "01310-100"}},
+		{"Av. Paulista 1000, apto 12, Bela Vista, São Paulo/SP", geo.Address{Street: "Avenida Paulista", Number: "1000", Complement: "Apto 12", Neighborhood: "Bela Vista", City: "São Paulo", UF: "SP"}},
+		{"Avenida Brasil, nº 45, bloco B, Centro, Rio de Janeiro RJ 20040-020", geo.Address{Street: "Avenida Brasil", Number: "45", Complement: "Bloco B", Neighborhood: "Centro", City: "Rio de Janeiro", UF: "RJ", CEP: "20040-020"}},
+		{"Rua A, s/n, Jardim Europa", geo.Address{Street: "Rua A", Number: "S/N", Neighborhood: "Jardim Europa"}},
+		{"cep 01305000", geo.Address{CEP: "01305-000"}},
+		{"Curitiba/pr", geo.Address{City: "Curitiba", UF: "PR"}},
+	}
+	for _, c := range cases {
+		got, err := geo.ParseAddress(c.input)
+		if err != nil || got != c.want {
+			t.Errorf("ParseAddress(%q) = %+v, %v; want %+v", c.input, got, err, c.want)
+		}
+	}
+
+	if _, err := geo.ParseAddress("  , - "); !errors.Is(err, geo.ErrEmptyAddress) {
+		t.Errorf("empty address error = %v", err)
+	}
+}
+
+func TestOfflineGeocoder(t *testing.T) {
+	g, err := geo.LoadOfflineGeocoder("testdata/ceps.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	loc, err := g.LookupCEP(ctx, "01310100")
+	if err != nil || loc.Precision != geo.PrecisionCEP || loc.Address.Street != "Avenida Paulista" {
+		t.Fatalf("exact CEP got %+v, %v", loc, err)
+	}
+
+	loc, err = g.LookupCEP(ctx, "01310-150")
+	if err != nil || loc.Precision != geo.PrecisionRegion || loc.Address.City != "São Paulo" {
+		t.Fatalf("CEP range got %+v, %v", loc, err)
+	}
+
+	if _, err := g.LookupCEP(ctx, "99999-999"); !errors.Is(err, geo.ErrNotFound) {
+		t.Fatalf("unknown CEP error = %v", err)
+	}
+	if _, err := g.LookupCEP(ctx, "123"); !errors.Is(err, geo.ErrInvalidCEP) {
+		t.Fatalf("invalid CEP error = %v", err)
+	}
+
+	loc, err = g.Geocode(ctx, geo.Address{Street: "Paulista", Number: "900", City: "sao paulo", UF: "SP"})
+	if err != nil || loc.Precision != geo.PrecisionStreet || loc.Address.Number != "900" || loc.Address.CEP == "" {
+		t.Fatalf("street geocode got %+v, %v", loc, err)
+	}
+
+	sp := geo.Coordinates{Lat: -23.5613, Lng: -46.6565}
+	rj := geo.Coordinates{Lat: -22.9035, Lng: -43.1770}
+	if d := sp.DistanceKm(rj); d < 355 || d > 365 {
+		t.Fatalf("São Paulo → Rio distance = %.1f km", d)
+	}
+}
+
+// memoryStore grava as chaves em memória
+type memoryStore map[string]string
+
+func (m memoryStore) Set(_ context.Context, scope persistence.Scope, key, value string) error {
+	m[string(scope)+"."+key] = value
+	return nil
+}
+
+func TestResolver(t *testing.T) {
+	g, err := geo.LoadOfflineGeocoder("testdata/ceps.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := memoryStore{}
+	resolver := geo.NewResolver(g).WithKeyWriter(store)
+	opts := geo.Options{Persistence: &persistence.Config{Enabled: true, Scope: persistence.ScopeState, Key: "endereco"}}
+	ctx := context.Background()
+
+	res, err := resolver.Resolve(ctx, "Av. Paulista 1000, Bela Vista, São Paulo/SP", opts)
+	if err != nil || res.Output != geo.OutputResolved {
+		t.Fatalf("resolve got %+v, %v", res, err)
+	}
+	if store["state.endereco.cep"] != "01310-100" || store["state.endereco.number"] != "1000" || store["state.endereco.lat"] != "-23.5613" {
+		t.Fatalf("unexpected writes %v", store)
+	}
+
+	// Cidade conhecida, rua desconhecida: abaixo da qualidade mínima padrão
+	if res, err := resolver.Resolve(ctx, "Rua Nova, 5, Centro, Curitiba - PR", opts); err != nil || res.Output != geo.OutputNoMatch || res.Location == nil {
+		t.Fatalf("low quality got %+v, %v", res, err)
+	}
+	if res, err := resolver.Resolve(ctx, "Rua Nova, 5, Centro, Curitiba - PR", geo.Options{QualityMin: 0.5}); err != nil || res.Output != geo.OutputResolved {
+		t.Fatalf("quality_min 0.5 got %+v, %v", res, err)
+	}
+
+	if res, _ := resolver.CaptureAddress(ctx, "???", opts); res.Output != geo.OutputInvalid {
+		t.Fatalf("capture of invalid text got %+v", res)
+	}
+	if res, _ := geo.NewResolver(nil).CaptureAddress(ctx, "Rua A, 10, Centro", geo.Options{}); res.Output != geo.OutputCaptured || res.Address.Street != "Rua A" {
+		t.Fatalf("capture without geocoder got %+v", res)
+	}
+}
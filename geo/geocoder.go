@@ -0,0 +1,60 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// Erros dos geocoders
+var (
+	ErrInvalidCEP = errors.New("geo: invalid CEP")
+	ErrNotFound   = errors.New("geo: address not found")
+	ErrNoGeocoder = errors.New("geo: no geocoder configured")
+)
+
+// Precision nível de precisão da localização resolvida
+type Precision string
+
+const (
+	PrecisionCEP    Precision = "cep"    // CEP exato
+	PrecisionStreet Precision = "street" // Logradouro na cidade (sem CEP)
+	PrecisionRegion Precision = "region" // Faixa de CEP (5 primeiros dígitos)
+	PrecisionCity   Precision = "city"   // Centro aproximado da cidade
+)
+
+// Coordinates coordenadas em graus decimais (WGS84)
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// earthRadiusKm raio médio da Terra usado no cálculo de haversine
+const earthRadiusKm = 6371.0
+
+// DistanceKm distância em linha reta (haversine) até outro ponto
+func (c Coordinates) DistanceKm(other Coordinates) float64 {
+	lat1, lat2 := c.Lat*math.Pi/180, other.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (other.Lng - c.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Location resultado da geocodificação
+type Location struct {
+	Address     Address     `json:"address"`     // Endereço completado com os dados da base
+	Coordinates Coordinates `json:"coordinates"` // Coordenadas do endereço
+	Quality     float64     `json:"quality"`     // Confiança entre 0 e 1 (comparada com quality_min do geo_resolve)
+	Precision   Precision   `json:"precision"`
+	Source      string      `json:"source,omitempty"` // Identificação do provedor (ex.: "offline")
+}
+
+// Geocoder resolve CEP e endereços em localização
+// Implementações devem retornar ErrInvalidCEP para CEP malformado e ErrNotFound
+// quando não há resultado; erros de rede/provedor são repassados como estão
+type Geocoder interface {
+	LookupCEP(ctx context.Context, cep string) (Location, error)
+	Geocode(ctx context.Context, address Address) (Location, error)
+}
@@ -0,0 +1,327 @@
+package geo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/sanitize"
+)
+
+// Qualidade atribuída por nível de precisão no geocoder offline
+const (
+	qualityCEP          = 1.0
+	qualityStreet       = 0.9
+	qualityStreetPart   = 0.75 // Logradouro contido no nome da base ("Paulista" → "Avenida Paulista")
+	qualityRegion       = 0.7
+	qualityNeighborhood = 0.5
+	qualityCity         = 0.4
+	streetMismatch      = 0.8 // Fator quando o CEP existe mas o logradouro digitado é outro
+)
+
+// Entry linha da base offline de CEPs
+type Entry struct {
+	CEP          string  `json:"cep"` // Com ou sem hífen
+	Street       string  `json:"street"`
+	Neighborhood string  `json:"neighborhood"`
+	City         string  `json:"city"`
+	UF           string  `json:"uf"`
+	Lat          float64 `json:"lat"`
+	Lng          float64 `json:"lng"`
+}
+
+// OfflineGeocoder geocoder em memória carregado de uma base local de CEPs/coordenadas
+// Não acessa a rede: indicado para testes e ambientes sem provedor externo
+type OfflineGeocoder struct {
+	byCEP  map[string]Entry   // CEP (8 dígitos) → entrada
+	byCity map[string][]Entry // cidade|UF normalizados → entradas
+}
+
+var _ Geocoder = (*OfflineGeocoder)(nil)
+
+// NewOfflineGeocoder cria o geocoder a partir das entradas informadas
+// Entradas com CEP inválido são ignoradas
+func NewOfflineGeocoder(entries []Entry) *OfflineGeocoder {
+	g := &OfflineGeocoder{byCEP: make(map[string]Entry), byCity: make(map[string][]Entry)}
+	for _, e := range entries {
+		digits := cepDigits(e.CEP)
+		if len(digits) != 8 {
+			continue
+		}
+		e.CEP = formatCEP(digits)
+		e.UF = strings.ToUpper(e.UF)
+		g.byCEP[digits] = e
+		g.byCity[cityKey(e.City, e.UF)] = append(g.byCity[cityKey(e.City, e.UF)], e)
+	}
+	return g
+}
+
+// LoadOfflineGeocoder carrega a base de um arquivo .json (array de Entry) ou .csv
+// O CSV deve ter cabeçalho com as colunas cep, street, neighborhood, city, uf, lat e lng (em qualquer ordem)
+func LoadOfflineGeocoder(path string) (*OfflineGeocoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.NewDecoder(f).Decode(&entries)
+	case ".csv":
+		entries, err = ReadCSV(f)
+	default:
+		return nil, fmt.Errorf("geo: unsupported dataset format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("geo: load %s: %w", path, err)
+	}
+	return NewOfflineGeocoder(entries), nil
+}
+
+// ReadCSV lê entradas de um CSV com cabeçalho
+func ReadCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"cep", "lat", "lng"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		lat, err := strconv.ParseFloat(field("lat"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: lat: %w", len(entries)+2, err)
+		}
+		lng, err := strconv.ParseFloat(field("lng"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: lng: %w", len(entries)+2, err)
+		}
+		entries = append(entries, Entry{
+			CEP: field("cep"), Street: field("street"), Neighborhood: field("neighborhood"),
+			City: field("city"), UF: field("uf"), Lat: lat, Lng: lng,
+		})
+	}
+}
+
+// Len quantidade de CEPs carregados
+func (g *OfflineGeocoder) Len() int { return len(g.byCEP) }
+
+// LookupCEP resolve o CEP exato; sem correspondência, usa o centro da faixa (5 primeiros dígitos)
+func (g *OfflineGeocoder) LookupCEP(_ context.Context, cep string) (Location, error) {
+	digits := cepDigits(cep)
+	if len(digits) != 8 {
+		return Location{}, fmt.Errorf("%w: %q", ErrInvalidCEP, cep)
+	}
+
+	if e, ok := g.byCEP[digits]; ok {
+		return e.location(qualityCEP, PrecisionCEP), nil
+	}
+
+	var region []Entry
+	for key, e := range g.byCEP {
+		if key[:5] == digits[:5] {
+			region = append(region, e)
+		}
+	}
+	if len(region) == 0 {
+		return Location{}, fmt.Errorf("%w: CEP %s", ErrNotFound, formatCEP(digits))
+	}
+	loc := centroid(region, qualityRegion, PrecisionRegion)
+	loc.Address.CEP = formatCEP(digits)
+	return loc, nil
+}
+
+// Geocode resolve o endereço pelo CEP quando informado; senão pelo logradouro,
+// bairro ou cidade (nessa ordem de precisão). Número e complemento digitados são preservados
+func (g *OfflineGeocoder) Geocode(ctx context.Context, address Address) (Location, error) {
+	if address.CEP != "" {
+		loc, err := g.LookupCEP(ctx, address.CEP)
+		if err != nil {
+			return Location{}, err
+		}
+		if address.Street != "" && loc.Address.Street != "" && !streetMatches(address.Street, loc.Address.Street) {
+			loc.Quality *= streetMismatch
+		}
+		loc.Address = merge(address, loc.Address)
+		return loc, nil
+	}
+
+	candidates := g.cityEntries(address.City, address.UF)
+	if len(candidates) == 0 {
+		return Location{}, fmt.Errorf("%w: %s", ErrNotFound, address)
+	}
+
+	var loc Location
+	found := address.Street != "" && g.findStreet(candidates, address.Street, &loc)
+	if !found {
+		found = address.Neighborhood != "" && g.findNeighborhood(candidates, address.Neighborhood, &loc)
+	}
+	if !found {
+		loc = centroid(candidates, qualityCity, PrecisionCity)
+	}
+	loc.Address = merge(address, loc.Address)
+	return loc, nil
+}
+
+// findStreet procura o logradouro entre as entradas da cidade (exato antes de parcial)
+func (g *OfflineGeocoder) findStreet(candidates []Entry, street string, loc *Location) bool {
+	var partial *Entry
+	for i, e := range candidates {
+		if fold(e.Street) == fold(street) {
+			*loc = e.location(qualityStreet, PrecisionStreet)
+			return true
+		}
+		if partial == nil && streetMatches(street, e.Street) {
+			partial = &candidates[i]
+		}
+	}
+	if partial != nil {
+		*loc = partial.location(qualityStreetPart, PrecisionStreet)
+		return true
+	}
+	return false
+}
+
+// findNeighborhood usa o centro das entradas do bairro
+func (g *OfflineGeocoder) findNeighborhood(candidates []Entry, neighborhood string, loc *Location) bool {
+	var matches []Entry
+	for _, e := range candidates {
+		if fold(e.Neighborhood) == fold(neighborhood) {
+			matches = append(matches, e)
+		}
+	}
+	if len(matches) == 0 {
+		return false
+	}
+	*loc = centroid(matches, qualityNeighborhood, PrecisionRegion)
+	return true
+}
+
+// cityEntries entradas da cidade; sem UF, aceita a cidade em qualquer estado
+func (g *OfflineGeocoder) cityEntries(city, uf string) []Entry {
+	if city == "" {
+		return nil
+	}
+	if uf != "" {
+		return g.byCity[cityKey(city, uf)]
+	}
+	var entries []Entry
+	prefix := fold(city) + "|"
+	for _, key := range slices.Sorted(maps.Keys(g.byCity)) {
+		if strings.HasPrefix(key, prefix) {
+			entries = append(entries, g.byCity[key]...)
+		}
+	}
+	return entries
+}
+
+// location converte a entrada em resultado
+func (e Entry) location(quality float64, precision Precision) Location {
+	return Location{
+		Address: Address{
+			Street: e.Street, Neighborhood: e.Neighborhood, City: e.City, UF: e.UF, CEP: e.CEP,
+		},
+		Coordinates: Coordinates{Lat: e.Lat, Lng: e.Lng},
+		Quality:     quality,
+		Precision:   precision,
+		Source:      "offline",
+	}
+}
+
+// centroid média das coordenadas; mantém no endereço só os campos comuns a todas as entradas
+func centroid(entries []Entry, quality float64, precision Precision) Location {
+	loc := entries[0].location(quality, precision)
+	loc.Address.Street, loc.Address.CEP = "", ""
+
+	var lat, lng float64
+	for _, e := range entries {
+		lat += e.Lat
+		lng += e.Lng
+		if fold(e.Neighborhood) != fold(loc.Address.Neighborhood) {
+			loc.Address.Neighborhood = ""
+		}
+		if fold(e.City) != fold(loc.Address.City) || e.UF != loc.Address.UF {
+			loc.Address.City, loc.Address.UF = "", ""
+		}
+	}
+	loc.Coordinates = Coordinates{Lat: lat / float64(len(entries)), Lng: lng / float64(len(entries))}
+	return loc
+}
+
+// merge completa o endereço digitado com os dados da base (a base prevalece,
+// exceto número e complemento, que só o usuário conhece)
+func merge(typed, found Address) Address {
+	out := found
+	out.Number, out.Complement = typed.Number, typed.Complement
+	if out.Street == "" {
+		out.Street = typed.Street
+	}
+	if out.Neighborhood == "" {
+		out.Neighborhood = typed.Neighborhood
+	}
+	if out.City == "" {
+		out.City, out.UF = typed.City, typed.UF
+	}
+	if out.CEP == "" {
+		out.CEP = typed.CEP
+	}
+	return out
+}
+
+// streetMatches compara logradouros ignorando acentos e o tipo ("Paulista" ~ "Avenida Paulista")
+func streetMatches(typed, known string) bool {
+	a, b := fold(typed), fold(known)
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.Contains(b, a) || strings.Contains(a, b)
+}
+
+func cityKey(city, uf string) string {
+	return fold(city) + "|" + strings.ToUpper(uf)
+}
+
+func cepDigits(cep string) string {
+	digits, _ := sanitize.Default().Sanitize(cep, sanitize.Config{Type: sanitize.NumbersOnly})
+	return digits
+}
+
+func formatCEP(digits string) string {
+	return digits[:5] + "-" + digits[5:]
+}
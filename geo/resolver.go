@@ -0,0 +1,173 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/AgendoCerto/lib-bot/persistence"
+)
+
+// Saídas dos componentes atendidos pelo Resolver
+const (
+	OutputResolved = "resolved" // geo_resolve: endereço resolvido com qualidade suficiente
+	OutputNoMatch  = "no_match" // geo_resolve: endereço não encontrado ou abaixo de quality_min
+	OutputError    = "error"    // geo_resolve: falha do provedor ou da persistência
+	OutputCaptured = "captured" // location_capture: endereço digitado reconhecido
+	OutputInvalid  = "invalid"  // location_capture: texto sem endereço reconhecível
+)
+
+// DefaultQualityMin qualidade mínima padrão (mesmo default do componente geo_resolve)
+const DefaultQualityMin = 0.6
+
+// addressFields sufixos gravados junto com a chave configurada na persistência
+var addressFields = []string{"street", "number", "complement", "neighborhood", "city", "uf", "cep", "lat", "lng"}
+
+// PersistenceKeys chaves gravadas para uma chave de persistência: a própria chave
+// (endereço formatado) e uma subchave por campo ("endereco.cep", "endereco.lat"...)
+func PersistenceKeys(key string) []string {
+	keys := []string{key}
+	for _, field := range addressFields {
+		keys = append(keys, key+"."+field)
+	}
+	return keys
+}
+
+// Write gravação de um campo do endereço na persistência
+type Write struct {
+	Scope persistence.Scope `json:"scope"`
+	Key   string            `json:"key"`
+	Value string            `json:"value"`
+}
+
+// Options configuração do componente (props quality_min e persistence)
+type Options struct {
+	QualityMin  float64             // 0 usa DefaultQualityMin
+	Persistence *persistence.Config // Escopo e chave onde o resultado é gravado
+}
+
+// Result resultado da captura/resolução
+type Result struct {
+	Output   string    `json:"output"`             // Saída do componente a seguir
+	Address  Address   `json:"address"`            // Endereço interpretado (completado pela base quando resolvido)
+	Location *Location `json:"location,omitempty"` // Localização encontrada (mesmo abaixo de quality_min)
+	Writes   []Write   `json:"writes,omitempty"`   // Gravações feitas (ou a fazer, sem KeyWriter)
+	Error    string    `json:"error,omitempty"`
+}
+
+// Resolver executa os modos type_address do location_capture e o geo_resolve
+type Resolver struct {
+	geocoder Geocoder
+	store    persistence.KeyWriter
+}
+
+// NewResolver cria o resolver; geocoder pode ser nil quando só a captura é usada
+func NewResolver(geocoder Geocoder) *Resolver {
+	return &Resolver{geocoder: geocoder}
+}
+
+// WithGeocoder define o provedor de geocodificação
+func (r *Resolver) WithGeocoder(geocoder Geocoder) *Resolver {
+	cp := *r
+	cp.geocoder = geocoder
+	return &cp
+}
+
+// WithKeyWriter grava os resultados nos escopos configurados no componente
+func (r *Resolver) WithKeyWriter(store persistence.KeyWriter) *Resolver {
+	cp := *r
+	cp.store = store
+	return &cp
+}
+
+// CaptureAddress interpreta o endereço digitado (location_capture, modo type_address)
+// Com geocoder, completa o endereço e as coordenadas quando possível; falhas do
+// provedor não invalidam a captura, apenas deixam Location vazio
+func (r *Resolver) CaptureAddress(ctx context.Context, text string, opts Options) (Result, error) {
+	address, err := ParseAddress(text)
+	if err != nil {
+		return Result{Output: OutputInvalid, Error: err.Error()}, nil
+	}
+
+	result := Result{Output: OutputCaptured, Address: address}
+	if r.geocoder != nil {
+		if loc, err := r.geocoder.Geocode(ctx, address); err == nil && loc.Quality >= qualityMin(opts) {
+			result.Address, result.Location = loc.Address, &loc
+		}
+	}
+	return r.persist(ctx, result, opts)
+}
+
+// Resolve normaliza e geocodifica o endereço (geo_resolve)
+// Endereço ausente, não encontrado ou abaixo de quality_min segue por no_match;
+// falhas do provedor ou da persistência seguem por error e são retornadas
+func (r *Resolver) Resolve(ctx context.Context, text string, opts Options) (Result, error) {
+	if r.geocoder == nil {
+		return Result{Output: OutputError, Error: ErrNoGeocoder.Error()}, ErrNoGeocoder
+	}
+
+	address, err := ParseAddress(text)
+	if err != nil {
+		return Result{Output: OutputNoMatch, Error: err.Error()}, nil
+	}
+
+	loc, err := r.geocoder.Geocode(ctx, address)
+	switch {
+	case errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalidCEP):
+		return Result{Output: OutputNoMatch, Address: address, Error: err.Error()}, nil
+	case err != nil:
+		return Result{Output: OutputError, Address: address, Error: err.Error()}, fmt.Errorf("geocode: %w", err)
+	case loc.Quality < qualityMin(opts):
+		return Result{Output: OutputNoMatch, Address: loc.Address, Location: &loc}, nil
+	}
+
+	return r.persist(ctx, Result{Output: OutputResolved, Address: loc.Address, Location: &loc}, opts)
+}
+
+// persist prepara e aplica as gravações do resultado
+func (r *Resolver) persist(ctx context.Context, result Result, opts Options) (Result, error) {
+	p := opts.Persistence
+	if p == nil || !p.Enabled || p.Key == "" {
+		return result, nil
+	}
+
+	result.Writes = writes(p, result.Address, result.Location)
+	if r.store == nil {
+		return result, nil
+	}
+	for _, w := range result.Writes {
+		if err := r.store.Set(ctx, w.Scope, w.Key, w.Value); err != nil {
+			result.Output, result.Error = OutputError, err.Error()
+			return result, fmt.Errorf("persist address %s: %w", w.Key, err)
+		}
+	}
+	return result, nil
+}
+
+// writes monta as gravações: endereço formatado na chave e campos não vazios nas subchaves
+func writes(p *persistence.Config, address Address, loc *Location) []Write {
+	values := map[string]string{
+		"street": address.Street, "number": address.Number, "complement": address.Complement,
+		"neighborhood": address.Neighborhood, "city": address.City, "uf": address.UF, "cep": address.CEP,
+	}
+	if loc != nil {
+		values["lat"] = strconv.FormatFloat(loc.Coordinates.Lat, 'f', -1, 64)
+		values["lng"] = strconv.FormatFloat(loc.Coordinates.Lng, 'f', -1, 64)
+	}
+
+	out := []Write{{Scope: p.Scope, Key: p.Key, Value: address.String()}}
+	for _, field := range addressFields {
+		if value := values[field]; value != "" {
+			out = append(out, Write{Scope: p.Scope, Key: p.Key + "." + field, Value: value})
+		}
+	}
+	return out
+}
+
+func qualityMin(opts Options) float64 {
+	if opts.QualityMin > 0 {
+		return opts.QualityMin
+	}
+	return DefaultQualityMin
+}
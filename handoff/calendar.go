@@ -0,0 +1,165 @@
+// Package handoff implementa o componente human_handoff: calendário de horário de
+// atendimento (jornada semanal, feriados e fuso) e fila de atendimento humano com
+// SLA, recolocação na fila no timeout e retorno ao bot
+package handoff
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// DefaultTimezone fuso do calendário sem timezone configurado
+const DefaultTimezone = "America/Sao_Paulo"
+
+// dayMinutes minutos de um dia
+const dayMinutes = 24 * 60
+
+// weekdayKeys chaves aceitas em BusinessHours.Weekly
+var weekdayKeys = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// interval intervalo em minutos desde a meia-noite
+type interval struct{ start, end int }
+
+// Calendar horário de atendimento imutável
+type Calendar struct {
+	loc      *time.Location
+	weekly   [7][]interval
+	holidays map[string]bool
+}
+
+// AlwaysOpen calendário sem períodos fechados (bot sem business_hours)
+func AlwaysOpen() *Calendar {
+	c := &Calendar{loc: time.UTC, holidays: map[string]bool{}}
+	for day := range c.weekly {
+		c.weekly[day] = []interval{{0, dayMinutes}}
+	}
+	return c
+}
+
+// NewCalendar cria o calendário a partir do business_hours do design
+// Dias ausentes em Weekly ficam fechados; intervalos sobrepostos são unidos
+func NewCalendar(hours io.BusinessHours) (*Calendar, error) {
+	tz := hours.Timezone
+	if tz == "" {
+		tz = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("handoff: timezone: %w", err)
+	}
+
+	c := &Calendar{loc: loc, holidays: make(map[string]bool, len(hours.Holidays))}
+	for key, ranges := range hours.Weekly {
+		day, ok := weekdayKeys[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("handoff: unknown weekday %q (want mon..sun)", key)
+		}
+		for _, r := range ranges {
+			start, err := parseClock(r.Start)
+			if err != nil {
+				return nil, fmt.Errorf("handoff: %s: %w", key, err)
+			}
+			end, err := parseClock(r.End)
+			if err != nil {
+				return nil, fmt.Errorf("handoff: %s: %w", key, err)
+			}
+			if end <= start {
+				return nil, fmt.Errorf("handoff: %s: range %s-%s ends before it starts", key, r.Start, r.End)
+			}
+			c.weekly[day] = append(c.weekly[day], interval{start, end})
+		}
+		c.weekly[day] = merge(c.weekly[day])
+	}
+	for _, day := range hours.Holidays {
+		if _, err := time.Parse(time.DateOnly, day); err != nil {
+			return nil, fmt.Errorf("handoff: holiday %q: %w", day, err)
+		}
+		c.holidays[day] = true
+	}
+	return c, nil
+}
+
+// Location fuso do calendário
+func (c *Calendar) Location() *time.Location { return c.loc }
+
+// IsOpen indica se o atendimento está aberto no instante
+func (c *Calendar) IsOpen(t time.Time) bool {
+	t = t.In(c.loc)
+	if c.holidays[t.Format(time.DateOnly)] {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, r := range c.weekly[t.Weekday()] {
+		if minute >= r.start && minute < r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen próximo instante de abertura a partir de t (o próprio t se aberto)
+// Retorna zero quando não abre nos próximos 366 dias
+func (c *Calendar) NextOpen(t time.Time) time.Time {
+	if c.IsOpen(t) {
+		return t
+	}
+	t = t.In(c.loc)
+	y, m, d := t.Date()
+	for i := range 366 {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, c.loc)
+		if c.holidays[day.Format(time.DateOnly)] {
+			continue
+		}
+		for _, r := range c.weekly[day.Weekday()] {
+			if open := day.Add(time.Duration(r.start) * time.Minute); open.After(t) {
+				return open
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// HasClosedPeriods indica se há algum período fechado (dia sem cobertura
+// integral ou feriado) — nesse caso o human_handoff precisa de off_hours_message
+func (c *Calendar) HasClosedPeriods() bool {
+	if len(c.holidays) > 0 {
+		return true
+	}
+	for _, ranges := range c.weekly {
+		if len(ranges) != 1 || ranges[0] != (interval{0, dayMinutes}) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock "HH:MM" → minutos desde a meia-noite ("24:00" é aceito como fim do dia)
+func parseClock(s string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || h < 0 || m < 0 || m > 59 || h*60+m > dayMinutes {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return h*60 + m, nil
+}
+
+// merge ordena e une intervalos sobrepostos ou contíguos
+func merge(ranges []interval) []interval {
+	slices.SortFunc(ranges, func(a, b interval) int { return cmp.Compare(a.start, b.start) })
+	var out []interval
+	for _, r := range ranges {
+		if n := len(out); n > 0 && r.start <= out[n-1].end {
+			out[n-1].end = max(out[n-1].end, r.end)
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
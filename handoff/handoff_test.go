@@ -0,0 +1,98 @@
+package handoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/handoff"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+func calendar(t *testing.T) *handoff.Calendar {
+	t.Helper()
+	workday := []io.HoursRange{{Start: "09:00", End: "12:00"}, {Start: "13:00", End: "18:00"}}
+	cal, err := handoff.NewCalendar(io.BusinessHours{
+		Weekly:   map[string][]io.HoursRange{"mon": workday, "tue": workday, "wed": workday, "thu": workday, "fri": workday},
+		Holidays: []string{"2026-11-02"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cal
+}
+
+func TestCalendar(t *testing.T) {
+	cal := calendar(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, cal.Location())
+	}
+
+	if !cal.IsOpen(at(30, 9, 0)) || cal.IsOpen(at(30, 12, 30)) || cal.IsOpen(at(31, 10, 0)) {
+		t.Error("unexpected open/closed state")
+	}
+	if !cal.HasClosedPeriods() || handoff.AlwaysOpen().HasClosedPeriods() {
+		t.Error("HasClosedPeriods mismatch")
+	}
+
+	// Sexta 18h → segunda é feriado (02/11) → terça 09:00
+	want := time.Date(2026, 11, 3, 9, 0, 0, 0, cal.Location())
+	if got := cal.NextOpen(at(30, 18, 0)); !got.Equal(want) {
+		t.Errorf("NextOpen = %s, want %s", got, want)
+	}
+
+	if _, err := handoff.NewCalendar(io.BusinessHours{Weekly: map[string][]io.HoursRange{"seg": nil}}); err == nil {
+		t.Error("unknown weekday accepted")
+	}
+}
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 30, 10, 0, 0, 0, time.UTC)
+	queue := handoff.NewMemoryQueue().WithClock(func() time.Time { return now })
+	cfg := handoff.DefaultConfig()
+
+	first, tr, _ := queue.Enqueue(ctx, "s1", cfg)
+	second, _, _ := queue.Enqueue(ctx, "s2", cfg)
+	if again, _, _ := queue.Enqueue(ctx, "s1", cfg); again.ID != first.ID || tr.Output != handoff.OutputQueued {
+		t.Fatalf("re-enqueue created %s", again.ID)
+	}
+
+	now = now.Add(cfg.SLA)
+	transitions, _ := queue.Tick(ctx)
+	if len(transitions) != 2 || transitions[0].Reason != handoff.ReasonRequeued {
+		t.Fatalf("first SLA tick = %+v", transitions)
+	}
+
+	joined, err := queue.Assign(ctx, second.ID, "agent-1")
+	if err != nil || joined.Output != handoff.OutputAgentJoined {
+		t.Fatalf("assign = %+v, %v", joined, err)
+	}
+
+	now = now.Add(cfg.SLA)
+	transitions, _ = queue.Tick(ctx)
+	if len(transitions) != 1 || transitions[0].SessionID != "s1" || transitions[0].Output != handoff.OutputTimeoutToBot {
+		t.Fatalf("second SLA tick = %+v", transitions)
+	}
+
+	closed, err := queue.Close(ctx, second.ID)
+	if err != nil || closed.Output != handoff.OutputClosedByAgent {
+		t.Errorf("close = %+v, %v", closed, err)
+	}
+	if _, err := queue.Close(ctx, first.ID); err == nil {
+		t.Error("closing a returned ticket should fail")
+	}
+}
+
+func TestDeskOffHours(t *testing.T) {
+	cal := calendar(t)
+	saturday := time.Date(2026, 10, 31, 10, 0, 0, 0, cal.Location())
+	desk := handoff.NewDesk(handoff.NewMemoryQueue(), cal).WithClock(func() time.Time { return saturday })
+
+	cfg := handoff.DefaultConfig()
+	cfg.OffHoursMessage = "Voltamos na segunda às 9h."
+	result, err := desk.Start(context.Background(), "s1", cfg)
+	if err != nil || result.Output != handoff.OutputTimeoutToBot || result.Message != cfg.OffHoursMessage || result.Ticket != nil {
+		t.Fatalf("off-hours result = %+v, %v", result, err)
+	}
+}
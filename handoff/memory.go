@@ -0,0 +1,172 @@
+package handoff
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// queueState estado mutável compartilhado entre as cópias da fila
+type queueState struct {
+	mu      sync.Mutex
+	tickets map[string]*Ticket
+	waiting []string // IDs aguardando, em ordem de atendimento
+	seq     int
+}
+
+// MemoryQueue Queue em memória (um processo; não persiste entre reinícios)
+type MemoryQueue struct {
+	now   Clock
+	state *queueState
+}
+
+var _ Queue = (*MemoryQueue)(nil)
+
+// NewMemoryQueue cria a fila vazia
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{now: time.Now, state: &queueState{tickets: map[string]*Ticket{}}}
+}
+
+// WithClock define o relógio (SLA)
+func (q *MemoryQueue) WithClock(now Clock) *MemoryQueue {
+	cp := *q
+	cp.now = now
+	return &cp
+}
+
+// Enqueue coloca a sessão no fim da fila; se ela já tem ticket aberto, devolve o existente
+func (q *MemoryQueue) Enqueue(_ context.Context, sessionID string, cfg Config) (Ticket, Transition, error) {
+	if cfg.SLA <= 0 {
+		cfg.SLA = DefaultConfig().SLA
+	}
+	if cfg.MaxRequeues <= 0 {
+		cfg.MaxRequeues = DefaultMaxRequeues
+	}
+
+	q.state.mu.Lock()
+	defer q.state.mu.Unlock()
+
+	now := q.now()
+	for _, t := range q.state.tickets {
+		if t.SessionID == sessionID && (t.Status == StatusWaiting || t.Status == StatusAssigned) {
+			return *t, transition(t, OutputFor(t.Status), "", now), nil
+		}
+	}
+
+	q.state.seq++
+	t := &Ticket{
+		ID:         fmt.Sprintf("ticket-%d", q.state.seq),
+		SessionID:  sessionID,
+		Status:     StatusWaiting,
+		Config:     cfg,
+		EnqueuedAt: now,
+		Deadline:   now.Add(cfg.SLA),
+		UpdatedAt:  now,
+	}
+	q.state.tickets[t.ID] = t
+	q.state.waiting = append(q.state.waiting, t.ID)
+	return *t, transition(t, OutputQueued, "", now), nil
+}
+
+// Next próximo ticket aguardando
+func (q *MemoryQueue) Next(_ context.Context) (Ticket, bool, error) {
+	q.state.mu.Lock()
+	defer q.state.mu.Unlock()
+
+	if len(q.state.waiting) == 0 {
+		return Ticket{}, false, nil
+	}
+	return *q.state.tickets[q.state.waiting[0]], true, nil
+}
+
+// Assign atendente assume um ticket aguardando (agent_joined)
+func (q *MemoryQueue) Assign(_ context.Context, ticketID, agentID string) (Transition, error) {
+	q.state.mu.Lock()
+	defer q.state.mu.Unlock()
+
+	t, err := q.ticket(ticketID, StatusWaiting)
+	if err != nil {
+		return Transition{}, err
+	}
+	q.unqueue(ticketID)
+	now := q.now()
+	t.Status, t.AgentID, t.UpdatedAt = StatusAssigned, agentID, now
+	return transition(t, OutputAgentJoined, "", now), nil
+}
+
+// Close atendente encerra o atendimento (closed_by_agent)
+func (q *MemoryQueue) Close(_ context.Context, ticketID string) (Transition, error) {
+	q.state.mu.Lock()
+	defer q.state.mu.Unlock()
+
+	t, err := q.ticket(ticketID, StatusAssigned)
+	if err != nil {
+		return Transition{}, err
+	}
+	now := q.now()
+	t.Status, t.UpdatedAt = StatusClosed, now
+	return transition(t, OutputClosedByAgent, "", now), nil
+}
+
+// Get busca um ticket
+func (q *MemoryQueue) Get(_ context.Context, ticketID string) (Ticket, bool, error) {
+	q.state.mu.Lock()
+	defer q.state.mu.Unlock()
+
+	t, ok := q.state.tickets[ticketID]
+	if !ok {
+		return Ticket{}, false, nil
+	}
+	return *t, true, nil
+}
+
+// Tick aplica o SLA aos tickets aguardando: recoloca no fim da fila com novo
+// prazo (queued/requeued) ou devolve ao bot (timeout_to_bot/sla)
+func (q *MemoryQueue) Tick(_ context.Context) ([]Transition, error) {
+	q.state.mu.Lock()
+	defer q.state.mu.Unlock()
+
+	now := q.now()
+	var out []Transition
+	for _, id := range slices.Clone(q.state.waiting) {
+		t := q.state.tickets[id]
+		if now.Before(t.Deadline) {
+			continue
+		}
+		q.unqueue(id)
+		t.UpdatedAt = now
+		if t.Config.RequeueIfTimeout && t.Requeues < t.Config.MaxRequeues {
+			t.Requeues++
+			t.Deadline = now.Add(t.Config.SLA)
+			q.state.waiting = append(q.state.waiting, id)
+			out = append(out, transition(t, OutputQueued, ReasonRequeued, now))
+			continue
+		}
+		t.Status = StatusReturned
+		out = append(out, transition(t, OutputTimeoutToBot, ReasonSLA, now))
+	}
+	return out, nil
+}
+
+// ticket busca o ticket exigindo o status atual (chamar com o mutex)
+func (q *MemoryQueue) ticket(id string, want TicketStatus) (*Ticket, error) {
+	t, ok := q.state.tickets[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTicketNotFound, id)
+	}
+	if t.Status != want {
+		return nil, fmt.Errorf("%w: %s is %s", ErrInvalidTransition, id, t.Status)
+	}
+	return t, nil
+}
+
+// unqueue retira o ID da lista de espera (chamar com o mutex)
+func (q *MemoryQueue) unqueue(id string) {
+	q.state.waiting = slices.DeleteFunc(q.state.waiting, func(w string) bool { return w == id })
+}
+
+func transition(t *Ticket, output, reason string, at time.Time) Transition {
+	return Transition{TicketID: t.ID, SessionID: t.SessionID, Output: output, Reason: reason, At: at}
+}
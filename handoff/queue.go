@@ -0,0 +1,171 @@
+package handoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+)
+
+// Erros da fila
+var (
+	ErrTicketNotFound    = errors.New("handoff: ticket not found")
+	ErrInvalidTransition = errors.New("handoff: invalid ticket transition")
+)
+
+// Clock relógio injetável (time.Now por padrão)
+type Clock func() time.Time
+
+// Saídas do componente human_handoff
+const (
+	OutputQueued        = "queued"          // Sessão entrou (ou voltou) na fila
+	OutputAgentJoined   = "agent_joined"    // Atendente assumiu
+	OutputClosedByAgent = "closed_by_agent" // Atendente encerrou
+	OutputTimeoutToBot  = "timeout_to_bot"  // SLA estourado ou fora do horário: volta ao bot
+)
+
+// Motivos das transições
+const (
+	ReasonRequeued = "requeued"  // SLA estourado, sessão recolocada no fim da fila
+	ReasonSLA      = "sla"       // SLA estourado sem recolocação
+	ReasonOffHours = "off_hours" // Fora do horário de atendimento
+)
+
+// TicketStatus situação do ticket na fila
+type TicketStatus string
+
+const (
+	StatusWaiting  TicketStatus = "waiting"  // Aguardando atendente
+	StatusAssigned TicketStatus = "assigned" // Em atendimento
+	StatusClosed   TicketStatus = "closed"   // Encerrado pelo atendente
+	StatusReturned TicketStatus = "returned" // Devolvido ao bot
+)
+
+// OutputFor saída do human_handoff correspondente ao status do ticket
+func OutputFor(status TicketStatus) string {
+	switch status {
+	case StatusAssigned:
+		return OutputAgentJoined
+	case StatusClosed:
+		return OutputClosedByAgent
+	case StatusReturned:
+		return OutputTimeoutToBot
+	}
+	return OutputQueued
+}
+
+// DefaultMaxRequeues recolocações na fila antes de voltar ao bot
+const DefaultMaxRequeues = 1
+
+// Config configuração do componente (mesmas props e defaults do component.HumanHandoff)
+type Config struct {
+	SLA              time.Duration // Espera máxima por um atendente
+	RequeueIfTimeout bool
+	MaxRequeues      int    // 0 usa DefaultMaxRequeues
+	OffHoursMessage  string // Texto enviado fora do horário
+}
+
+// DefaultConfig defaults do component.HumanHandoff
+func DefaultConfig() Config {
+	return Config{SLA: 10 * time.Minute, RequeueIfTimeout: true, MaxRequeues: DefaultMaxRequeues}
+}
+
+// ConfigFromSpec lê a configuração do spec gerado pelo component.HumanHandoff
+func ConfigFromSpec(spec component.ComponentSpec) Config {
+	cfg := DefaultConfig()
+	switch v := spec.Meta["sla_minutes"].(type) {
+	case int:
+		cfg.SLA = time.Duration(v) * time.Minute
+	case float64: // spec decodificado de JSON
+		cfg.SLA = time.Duration(v * float64(time.Minute))
+	}
+	if v, ok := spec.Meta["requeue_if_timeout"].(bool); ok {
+		cfg.RequeueIfTimeout = v
+	}
+	if spec.Text != nil {
+		cfg.OffHoursMessage = spec.Text.Raw
+	}
+	return cfg
+}
+
+// Ticket pedido de atendimento humano de uma sessão
+type Ticket struct {
+	ID         string       `json:"id"`
+	SessionID  string       `json:"session_id"`
+	Status     TicketStatus `json:"status"`
+	AgentID    string       `json:"agent_id,omitempty"`
+	Requeues   int          `json:"requeues"`
+	Config     Config       `json:"-"`
+	EnqueuedAt time.Time    `json:"enqueued_at"`
+	Deadline   time.Time    `json:"deadline"` // Fim do SLA enquanto aguarda
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// Transition mudança de estado a refletir na sessão (saída do human_handoff)
+type Transition struct {
+	TicketID  string    `json:"ticket_id,omitempty"`
+	SessionID string    `json:"session_id"`
+	Output    string    `json:"output"`
+	Reason    string    `json:"reason,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// Queue fila de atendimento humano
+// Tick deve ser chamado periodicamente: tickets aguardando além do SLA voltam ao
+// fim da fila (requeue_if_timeout, até MaxRequeues) ou retornam ao bot
+type Queue interface {
+	Enqueue(ctx context.Context, sessionID string, cfg Config) (Ticket, Transition, error)
+	Next(ctx context.Context) (Ticket, bool, error) // Próximo ticket aguardando (FIFO)
+	Assign(ctx context.Context, ticketID, agentID string) (Transition, error)
+	Close(ctx context.Context, ticketID string) (Transition, error)
+	Get(ctx context.Context, ticketID string) (Ticket, bool, error)
+	Tick(ctx context.Context) ([]Transition, error)
+}
+
+// Result resultado da entrada no human_handoff
+type Result struct {
+	Output   string    `json:"output"`
+	Reason   string    `json:"reason,omitempty"`
+	Ticket   *Ticket   `json:"ticket,omitempty"`
+	Message  string    `json:"message,omitempty"`   // off_hours_message a enviar
+	NextOpen time.Time `json:"next_open,omitempty"` // Próxima abertura (fora do horário)
+}
+
+// Desk porta de entrada do human_handoff: consulta o calendário e enfileira
+type Desk struct {
+	queue    Queue
+	calendar *Calendar
+	now      Clock
+}
+
+// NewDesk cria a porta de entrada; calendar nil considera sempre aberto
+func NewDesk(queue Queue, calendar *Calendar) *Desk {
+	if calendar == nil {
+		calendar = AlwaysOpen()
+	}
+	return &Desk{queue: queue, calendar: calendar, now: time.Now}
+}
+
+// WithClock define o relógio
+func (d *Desk) WithClock(now Clock) *Desk {
+	cp := *d
+	cp.now = now
+	return &cp
+}
+
+// Start enfileira a sessão (queued) ou, fora do horário, devolve ao bot com a
+// off_hours_message e a próxima abertura (timeout_to_bot)
+func (d *Desk) Start(ctx context.Context, sessionID string, cfg Config) (Result, error) {
+	now := d.now()
+	if !d.calendar.IsOpen(now) {
+		return Result{Output: OutputTimeoutToBot, Reason: ReasonOffHours, Message: cfg.OffHoursMessage, NextOpen: d.calendar.NextOpen(now)}, nil
+	}
+
+	ticket, transition, err := d.queue.Enqueue(ctx, sessionID, cfg)
+	if err != nil {
+		return Result{}, fmt.Errorf("enqueue session %s: %w", sessionID, err)
+	}
+	return Result{Output: transition.Output, Ticket: &ticket}, nil
+}
@@ -4,7 +4,8 @@ import "errors"
 
 // HSM (Highly Structured Messages) - Templates pré-aprovados
 // O usuário só precisa informar o 'name' do template HSM.
-// A validação e execução é responsabilidade do microserviço.
+// A execução é responsabilidade do microserviço; nome, idioma, variáveis e
+// botões podem ser conferidos localmente contra o Registry de templates aprovados.
 
 // HSMTemplate representa um template HSM simplificado
 type HSMTemplate struct {
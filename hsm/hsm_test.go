@@ -0,0 +1,58 @@
+package hsm_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/hsm"
+)
+
+const export = `{"data": [
+  {"name": "agc_confirm_v1", "language": "pt_BR", "category": "UTILITY", "status": "APPROVED",
+   "components": [
+     {"type": "HEADER", "format": "TEXT", "text": "Olá {{1}}"},
+     {"type": "BODY", "text": "Seu horário {{2}} em {{3}}. Confirma, {{1}}?"},
+     {"type": "BUTTONS", "buttons": [{"type": "QUICK_REPLY", "text": "Confirmar"}, {"type": "QUICK_REPLY", "text": "Alterar"}]}
+   ]},
+  {"name": "agc_confirm_v1", "language": "en_US", "parameter_count": 2, "button_types": ["quick_reply"]},
+  {"name": "agc_promo", "language": "pt_BR", "status": "REJECTED"}
+]}`
+
+func TestRegistry(t *testing.T) {
+	reg, err := hsm.LoadRegistry(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl, ok := reg.Lookup("agc_confirm_v1", "pt_BR")
+	if !ok || tpl.Params() != 3 || len(tpl.Buttons()) != 2 {
+		t.Fatalf("pt_BR template = %+v", tpl)
+	}
+	if langs := reg.Languages("agc_confirm_v1"); len(langs) != 2 || langs[0] != "en_US" {
+		t.Errorf("languages = %v", langs)
+	}
+
+	checks := []struct {
+		usage hsm.Usage
+		want  error
+	}{
+		{hsm.Usage{Name: "agc_confirm_v1", Language: "pt_BR", Params: 3, Buttons: []string{hsm.ButtonQuickReply}}, nil},
+		{hsm.Usage{Name: "agc_promo", Language: "pt_BR", Params: 0}, hsm.ErrUnknownTemplate},
+		{hsm.Usage{Name: "agc_confirm_v1", Language: "es_ES", Params: 3}, hsm.ErrWrongLanguage},
+		{hsm.Usage{Name: "agc_confirm_v1", Language: "pt_BR", Params: 2}, hsm.ErrParamCount},
+		{hsm.Usage{Name: "agc_confirm_v1", Language: "pt_BR", Params: 3, Buttons: []string{hsm.ButtonURL}}, hsm.ErrUnsupportedButton},
+		{hsm.Usage{Name: "agc_confirm_v1", Params: -1}, nil},
+	}
+	for _, c := range checks {
+		errs := reg.Check(c.usage)
+		if c.want == nil && len(errs) != 0 || c.want != nil && (len(errs) != 1 || !errors.Is(errs[0], c.want)) {
+			t.Errorf("Check(%+v) = %v, want %v", c.usage, errs, c.want)
+		}
+	}
+
+	// Idioma da sessão: confere contra todos os idiomas aprovados
+	if errs := reg.Check(hsm.Usage{Name: "agc_confirm_v1", Params: 3}); len(errs) != 1 || !strings.Contains(errs[0].Error(), "en_US") {
+		t.Errorf("session language check = %v", errs)
+	}
+}
@@ -0,0 +1,230 @@
+package hsm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Erros de conferência contra o registro de templates aprovados
+var (
+	ErrUnknownTemplate   = errors.New("hsm: unknown template")
+	ErrWrongLanguage     = errors.New("hsm: template not approved in language")
+	ErrParamCount        = errors.New("hsm: parameter count mismatch")
+	ErrUnsupportedButton = errors.New("hsm: button not supported by template")
+)
+
+// Tipos de botão dos templates (nomenclatura da Meta)
+const (
+	ButtonQuickReply = "QUICK_REPLY"
+	ButtonURL        = "URL"
+	ButtonPhone      = "PHONE_NUMBER"
+	ButtonCopyCode   = "COPY_CODE"
+)
+
+// StatusApproved status dos templates utilizáveis; templates com outro status
+// na exportação são ignorados
+const StatusApproved = "APPROVED"
+
+// TemplateButton botão declarado no componente BUTTONS
+type TemplateButton struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// TemplateComponent componente do template (HEADER, BODY, FOOTER, BUTTONS)
+type TemplateComponent struct {
+	Type    string           `json:"type"`
+	Format  string           `json:"format,omitempty"` // HEADER: TEXT|IMAGE|VIDEO|DOCUMENT
+	Text    string           `json:"text,omitempty"`
+	Buttons []TemplateButton `json:"buttons,omitempty"`
+}
+
+// Template template aprovado, no formato da exportação da Meta
+// ParamCount e ButtonTypes podem vir explícitos; quando ausentes são
+// derivados dos componentes (variáveis {{n}} do HEADER de texto e do BODY)
+type Template struct {
+	Name        string              `json:"name"`
+	Language    string              `json:"language"` // Ex: pt_BR
+	Category    string              `json:"category,omitempty"`
+	Status      string              `json:"status,omitempty"` // Vazio = aprovado
+	Components  []TemplateComponent `json:"components,omitempty"`
+	ParamCount  *int                `json:"parameter_count,omitempty"`
+	ButtonTypes []string            `json:"button_types,omitempty"`
+}
+
+// placeholderRe variáveis do template: {{1}} ou {{nome}}
+var placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// Params número de variáveis do template
+func (t Template) Params() int {
+	if t.ParamCount != nil {
+		return *t.ParamCount
+	}
+	seen := map[string]bool{}
+	for _, c := range t.Components {
+		switch strings.ToUpper(c.Type) {
+		case "BODY":
+		case "HEADER":
+			if c.Format != "" && !strings.EqualFold(c.Format, "TEXT") {
+				continue
+			}
+		default:
+			continue
+		}
+		for _, m := range placeholderRe.FindAllStringSubmatch(c.Text, -1) {
+			seen[m[1]] = true
+		}
+	}
+	return len(seen)
+}
+
+// Buttons tipos de botão aceitos pelo template
+func (t Template) Buttons() []string {
+	if len(t.ButtonTypes) > 0 {
+		out := make([]string, len(t.ButtonTypes))
+		for i, b := range t.ButtonTypes {
+			out[i] = strings.ToUpper(b)
+		}
+		return out
+	}
+	var out []string
+	for _, c := range t.Components {
+		if !strings.EqualFold(c.Type, "BUTTONS") {
+			continue
+		}
+		for _, b := range c.Buttons {
+			out = append(out, strings.ToUpper(b.Type))
+		}
+	}
+	return out
+}
+
+// Usage uso de um template no fluxo (hsm_trigger, hsm_ref ou message.hsm)
+type Usage struct {
+	Name     string
+	Language string   // Vazio: idioma resolvido na sessão (não conferido)
+	Params   int      // Variáveis informadas; -1 quando o uso não informa parâmetros
+	Buttons  []string // Tipos de botão usados (QUICK_REPLY, URL, PHONE_NUMBER...)
+}
+
+// Registry templates aprovados indexados por nome e idioma
+type Registry struct {
+	templates map[string]map[string]Template
+}
+
+// NewRegistry cria o registro com os templates aprovados
+func NewRegistry(templates ...Template) (*Registry, error) {
+	r := &Registry{templates: map[string]map[string]Template{}}
+	for i, t := range templates {
+		if t.Name == "" || t.Language == "" {
+			return nil, fmt.Errorf("hsm: template %d: name and language are required", i)
+		}
+		if t.Status != "" && !strings.EqualFold(t.Status, StatusApproved) {
+			continue
+		}
+		if r.templates[t.Name] == nil {
+			r.templates[t.Name] = map[string]Template{}
+		}
+		r.templates[t.Name][t.Language] = t
+	}
+	return r, nil
+}
+
+// LoadRegistry lê a exportação JSON: array de templates ou objeto {"data": [...]}
+// (resposta da Graph API)
+func LoadRegistry(r io.Reader) (*Registry, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []Template
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "{") {
+		var envelope struct {
+			Data []Template `json:"data"`
+		}
+		err = json.Unmarshal(raw, &envelope)
+		templates = envelope.Data
+	} else {
+		err = json.Unmarshal(raw, &templates)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hsm: decode templates: %w", err)
+	}
+	return NewRegistry(templates...)
+}
+
+// LoadRegistryFile lê a exportação JSON do arquivo
+func LoadRegistryFile(path string) (*Registry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadRegistry(f)
+}
+
+// Lookup template pelo nome e idioma
+func (r *Registry) Lookup(name, language string) (Template, bool) {
+	t, ok := r.templates[name][language]
+	return t, ok
+}
+
+// Languages idiomas aprovados do template (ordenados)
+func (r *Registry) Languages(name string) []string {
+	out := make([]string, 0, len(r.templates[name]))
+	for lang := range r.templates[name] {
+		out = append(out, lang)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Check confere o uso contra o registro; os erros envolvem ErrUnknownTemplate,
+// ErrWrongLanguage, ErrParamCount ou ErrUnsupportedButton
+// Com idioma resolvido na sessão, parâmetros e botões são conferidos contra
+// todos os idiomas aprovados
+func (r *Registry) Check(u Usage) []error {
+	langs := r.Languages(u.Name)
+	if len(langs) == 0 {
+		return []error{fmt.Errorf("%w: %q", ErrUnknownTemplate, u.Name)}
+	}
+	if u.Language != "" {
+		if _, ok := r.Lookup(u.Name, u.Language); !ok {
+			return []error{fmt.Errorf("%w: %q is approved in %s, not %s",
+				ErrWrongLanguage, u.Name, strings.Join(langs, ", "), u.Language)}
+		}
+		langs = []string{u.Language}
+	}
+
+	var errs []error
+	for _, lang := range langs {
+		t, _ := r.Lookup(u.Name, lang)
+		if want := t.Params(); u.Params >= 0 && u.Params != want {
+			errs = append(errs, fmt.Errorf("%w: %q (%s) expects %d variable(s), got %d",
+				ErrParamCount, u.Name, lang, want, u.Params))
+		}
+
+		// Cada botão usado consome um botão do mesmo tipo no template
+		available := map[string]int{}
+		for _, b := range t.Buttons() {
+			available[b]++
+		}
+		for _, b := range u.Buttons {
+			if available[b] == 0 {
+				errs = append(errs, fmt.Errorf("%w: %q (%s) has no %s button available",
+					ErrUnsupportedButton, u.Name, lang, b))
+				continue
+			}
+			available[b]--
+		}
+	}
+	return errs
+}
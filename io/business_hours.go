@@ -0,0 +1,15 @@
+package io
+
+// BusinessHours horário de atendimento humano do bot (usado pelo human_handoff)
+// Fora dos intervalos semanais e nos feriados o atendimento está fechado
+type BusinessHours struct {
+	Timezone string                  `json:"timezone,omitempty"` // IANA (ex: "America/Sao_Paulo"); vazio usa America/Sao_Paulo
+	Weekly   map[string][]HoursRange `json:"weekly"`             // Dia ("mon", "tue", "wed", "thu", "fri", "sat", "sun") -> intervalos
+	Holidays []string                `json:"holidays,omitempty"` // Datas fechadas "2006-01-02"
+}
+
+// HoursRange intervalo do dia no formato "HH:MM" ("24:00" fecha o dia)
+type HoursRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
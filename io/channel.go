@@ -0,0 +1,64 @@
+package io
+
+import (
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/flow"
+)
+
+// ChannelFallback substitui um nó em um canal específico (ex: carousel vira message no SMS)
+// O nó mantém ID e arestas; Kind/Props/Outputs vazios herdam do nó original
+type ChannelFallback struct {
+	Kind    string         `json:"kind,omitempty"`    // Tipo do componente no canal
+	Props   map[string]any `json:"props,omitempty"`   // Props usadas no canal
+	Outputs []string       `json:"outputs,omitempty"` // Outputs no canal (devem casar com os labels das arestas)
+}
+
+// ChannelName extrai o nome do canal de uma entrada de Bot.Channels ("whatsapp:5511..." -> "whatsapp")
+func ChannelName(channel string) string {
+	name, _, _ := strings.Cut(channel, ":")
+	return strings.TrimSpace(name)
+}
+
+// ChannelNames retorna os nomes de canal únicos de Bot.Channels, na ordem declarada
+func (b Bot) ChannelNames() []string {
+	seen := make(map[string]bool, len(b.Channels))
+	out := make([]string, 0, len(b.Channels))
+	for _, c := range b.Channels {
+		if name := ChannelName(c); name != "" && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// ForChannel retorna uma cópia do design com os fallbacks do canal aplicados
+// Retorna também os IDs dos nós substituídos
+func (d DesignDoc) ForChannel(channel string) (DesignDoc, []flow.ID) {
+	fallbacks := d.ChannelFallbacks[channel]
+	if len(fallbacks) == 0 {
+		return d, nil
+	}
+
+	out := d
+	out.Graph.Nodes = make([]flow.Node, len(d.Graph.Nodes))
+	var applied []flow.ID
+	for i, n := range d.Graph.Nodes {
+		if fb, ok := fallbacks[string(n.ID)]; ok {
+			if fb.Kind != "" {
+				n.Kind = fb.Kind
+			}
+			if fb.Props != nil {
+				n.Props = fb.Props
+				n.PropsRef = ""
+			}
+			if len(fb.Outputs) > 0 {
+				n.Outputs = fb.Outputs
+			}
+			applied = append(applied, n.ID)
+		}
+		out.Graph.Nodes[i] = n
+	}
+	return out, applied
+}
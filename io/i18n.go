@@ -0,0 +1,284 @@
+package io
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	goio "io"
+	"sort"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/flow"
+)
+
+// I18n tabela de traduções do design com cadeia de fallback por locale
+// As chaves seguem o formato "<node_id>.<caminho da prop>" (ex: "welcome.text", "menu.buttons[0].label")
+// O texto das props do nó é o texto do DefaultLocale (fonte para os tradutores)
+type I18n struct {
+	DefaultLocale string                       `json:"default_locale"`         // Locale do texto original das props (ex: "pt-BR")
+	Locales       []string                     `json:"locales"`                // Locales suportados pelo bot
+	Fallbacks     map[string][]string          `json:"fallbacks,omitempty"`    // Fallback explícito por locale (ex: "es-AR": ["es", "pt-BR"])
+	Translations  map[string]map[string]string `json:"translations,omitempty"` // chave -> locale -> texto
+}
+
+// LocalizableText texto traduzível extraído das props de um nó
+type LocalizableText struct {
+	Key  string  // Chave na tabela de traduções (node_id.caminho)
+	Node flow.ID // Nó de origem
+	Kind string  // Tipo do nó (usado para limites de tamanho)
+	Prop string  // Caminho da prop (ex: "buttons[0].label")
+	Text string  // Texto no DefaultLocale
+}
+
+// NormalizeLocale normaliza um locale para o formato BCP 47 (pt_br -> pt-BR)
+func NormalizeLocale(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	if len(parts) == 0 || parts[0] == "" {
+		return ""
+	}
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// TranslationKey monta a chave de tradução de uma prop de nó
+func TranslationKey(node flow.ID, prop string) string {
+	return string(node) + "." + prop
+}
+
+// Chain retorna a cadeia de fallback para um locale de sessão:
+// locale exato -> fallbacks explícitos -> idioma base (es-AR -> es) -> DefaultLocale
+func (t *I18n) Chain(locale string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(l string) {
+		l = NormalizeLocale(l)
+		if l != "" && !seen[l] {
+			seen[l] = true
+			chain = append(chain, l)
+		}
+	}
+
+	locale = NormalizeLocale(locale)
+	add(locale)
+	if t == nil {
+		return chain
+	}
+
+	for key, fallbacks := range t.Fallbacks {
+		if NormalizeLocale(key) == locale {
+			for _, f := range fallbacks {
+				add(f)
+			}
+		}
+	}
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		add(base)
+	}
+	add(t.DefaultLocale)
+
+	return chain
+}
+
+// Lookup busca a tradução de uma chave seguindo a cadeia de fallback do locale
+// Retorna o texto, o locale efetivamente usado e se encontrou (o DefaultLocale não é buscado na tabela)
+func (t *I18n) Lookup(key, locale string) (string, string, bool) {
+	if t == nil {
+		return "", "", false
+	}
+	variants := t.Translations[key]
+	for _, l := range t.Chain(locale) {
+		for variantLocale, text := range variants {
+			if NormalizeLocale(variantLocale) == l && text != "" {
+				return text, l, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Variants retorna todas as traduções de uma chave com locales normalizados
+func (t *I18n) Variants(key string) map[string]string {
+	if t == nil || len(t.Translations[key]) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(t.Translations[key]))
+	for locale, text := range t.Translations[key] {
+		if text != "" {
+			out[NormalizeLocale(locale)] = text
+		}
+	}
+	return out
+}
+
+// Policy retorna a configuração de locales sem a tabela de traduções (usada no plano)
+func (t *I18n) Policy() *I18n {
+	if t == nil {
+		return nil
+	}
+	return &I18n{DefaultLocale: t.DefaultLocale, Locales: t.Locales, Fallbacks: t.Fallbacks}
+}
+
+// LocalizableTexts lista os textos traduzíveis do grafo principal e dos subflows locais
+// do design em ordem determinística; nós de subflow usam "<subflow>/<nó>" na chave
+func (d DesignDoc) LocalizableTexts() []LocalizableText {
+	var out []LocalizableText
+	for _, n := range d.Graph.Nodes {
+		out = appendLocalizable(out, n.ID, n.Kind, d.ResolveProps(n))
+	}
+
+	ids := make([]string, 0, len(d.Subflows))
+	for id := range d.Subflows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		sf := d.Subflows[id]
+		for _, n := range sf.Graph.Nodes {
+			out = appendLocalizable(out, flow.ID(id+"/"+string(n.ID)), n.Kind, sf.ResolveProps(n))
+		}
+	}
+	return out
+}
+
+// appendLocalizable adiciona os textos traduzíveis das props de um nó
+func appendLocalizable(out []LocalizableText, node flow.ID, kind string, props map[string]any) []LocalizableText {
+	add := func(prop string, v any) {
+		if s, ok := v.(string); ok && s != "" {
+			out = append(out, LocalizableText{Key: TranslationKey(node, prop), Node: node, Kind: kind, Prop: prop, Text: s})
+		}
+	}
+
+	for _, key := range []string{"text", "caption", "title", "description"} {
+		add(key, props[key])
+	}
+	for i, b := range asMaps(props["buttons"]) {
+		add(fmt.Sprintf("buttons[%d].label", i), b["label"])
+	}
+	for i, sec := range asMaps(props["sections"]) {
+		add(fmt.Sprintf("sections[%d].title", i), sec["title"])
+		for j, item := range asMaps(sec["items"]) {
+			add(fmt.Sprintf("sections[%d].items[%d].title", i, j), item["title"])
+			add(fmt.Sprintf("sections[%d].items[%d].description", i, j), item["description"])
+		}
+	}
+	for i, card := range asMaps(props["cards"]) {
+		add(fmt.Sprintf("cards[%d].title", i), card["title"])
+		add(fmt.Sprintf("cards[%d].description", i), card["description"])
+	}
+	return out
+}
+
+// asMaps converte um []any de objetos em []map[string]any (ignora itens inválidos mantendo índices)
+func asMaps(v any) []map[string]any {
+	raw, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]map[string]any, len(raw))
+	for i, item := range raw {
+		out[i], _ = item.(map[string]any)
+	}
+	return out
+}
+
+// ExportTranslationsCSV exporta os textos traduzíveis para tradutores
+// Colunas: key, <default_locale>, <demais locales...>; a coluna do DefaultLocale é a fonte
+func ExportTranslationsCSV(w goio.Writer, d DesignDoc) error {
+	if d.I18n == nil || d.I18n.DefaultLocale == "" {
+		return errors.New("i18n: design has no default_locale")
+	}
+
+	def := NormalizeLocale(d.I18n.DefaultLocale)
+	locales := []string{def}
+	for _, l := range d.I18n.Locales {
+		if l = NormalizeLocale(l); l != def {
+			locales = append(locales, l)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"key"}, locales...)); err != nil {
+		return err
+	}
+	for _, t := range d.LocalizableTexts() {
+		variants := d.I18n.Variants(t.Key)
+		row := []string{t.Key, t.Text}
+		for _, l := range locales[1:] {
+			row = append(row, variants[l])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportTranslationsCSV lê um CSV no formato de ExportTranslationsCSV e mescla as traduções no design
+// A coluna do DefaultLocale é ignorada (o texto fonte fica nas props); células vazias não sobrescrevem
+func ImportTranslationsCSV(r goio.Reader, d *DesignDoc) error {
+	if d.I18n == nil || d.I18n.DefaultLocale == "" {
+		return errors.New("i18n: design has no default_locale")
+	}
+
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return fmt.Errorf("i18n: invalid csv: %w", err)
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 || rows[0][0] != "key" {
+		return errors.New("i18n: csv header must start with 'key'")
+	}
+
+	known := make(map[string]bool)
+	for _, t := range d.LocalizableTexts() {
+		known[t.Key] = true
+	}
+
+	def := NormalizeLocale(d.I18n.DefaultLocale)
+	header := rows[0]
+	if d.I18n.Translations == nil {
+		d.I18n.Translations = make(map[string]map[string]string)
+	}
+
+	for line, row := range rows[1:] {
+		if len(row) == 0 || row[0] == "" {
+			continue
+		}
+		key := row[0]
+		if !known[key] {
+			return fmt.Errorf("i18n: line %d: unknown key %q", line+2, key)
+		}
+		for col := 1; col < len(row) && col < len(header); col++ {
+			locale := NormalizeLocale(header[col])
+			if locale == def || row[col] == "" {
+				continue
+			}
+			if d.I18n.Translations[key] == nil {
+				d.I18n.Translations[key] = make(map[string]string)
+			}
+			d.I18n.Translations[key][locale] = row[col]
+		}
+	}
+
+	// Locales novos vindos do CSV passam a ser suportados
+	declared := make(map[string]bool)
+	for _, l := range d.I18n.Locales {
+		declared[NormalizeLocale(l)] = true
+	}
+	var added []string
+	for _, h := range header[1:] {
+		if l := NormalizeLocale(h); l != "" && l != def && !declared[l] {
+			declared[l] = true
+			added = append(added, l)
+		}
+	}
+	sort.Strings(added)
+	d.I18n.Locales = append(d.I18n.Locales, added...)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package io_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+func TestI18nChain(t *testing.T) {
+	i18n := &io.I18n{
+		DefaultLocale: "pt-BR",
+		Locales:       []string{"pt-BR", "es", "es-AR", "en"},
+		Fallbacks:     map[string][]string{"es_ar": {"es-MX", "en"}},
+	}
+	cases := []struct {
+		locale string
+		chain  []string
+	}{
+		{"es-AR", []string{"es-AR", "es-MX", "en", "es", "pt-BR"}},
+		{"en_us", []string{"en-US", "en", "pt-BR"}},
+		{"pt-BR", []string{"pt-BR", "pt"}},
+		{"", []string{"pt-BR"}},
+	}
+	for _, c := range cases {
+		if got := i18n.Chain(c.locale); !reflect.DeepEqual(got, c.chain) {
+			t.Errorf("Chain(%q) = %v, want %v", c.locale, got, c.chain)
+		}
+	}
+
+	var none *io.I18n
+	if got := none.Chain("es-AR"); !reflect.DeepEqual(got, []string{"es-AR"}) {
+		t.Errorf("nil Chain = %v", got)
+	}
+
+	i18n.Translations = map[string]map[string]string{"welcome.text": {"es": "Hola", "en": "Hi"}}
+	if text, locale, ok := i18n.Lookup("welcome.text", "es-AR"); !ok || text != "Hi" || locale != "en" {
+		t.Errorf("Lookup(es-AR) = %q, %q, %v", text, locale, ok)
+	}
+	if _, _, ok := i18n.Lookup("welcome.text", "pt-BR"); ok {
+		t.Error("Lookup(pt-BR) found a translation for the default locale")
+	}
+}
+
+func TestTranslationsCSVRoundTrip(t *testing.T) {
+	design := io.DesignDoc{
+		Graph: io.Graph{Nodes: []flow.Node{
+			{ID: "welcome", Kind: "message", Props: map[string]any{"text": "Olá, tudo bem?"}},
+			{ID: "menu", Kind: "buttons", Props: map[string]any{
+				"text":    "Escolha",
+				"buttons": []any{map[string]any{"label": "Sim"}, map[string]any{"label": "Não, obrigado"}},
+			}},
+		}},
+		I18n: &io.I18n{
+			DefaultLocale: "pt-BR",
+			Locales:       []string{"pt-BR", "en"},
+			Translations:  map[string]map[string]string{"welcome.text": {"en": "Hello, how are you?"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := io.ExportTranslationsCSV(&buf, design); err != nil {
+		t.Fatal(err)
+	}
+	want := "key,pt-BR,en\n" +
+		"welcome.text,\"Olá, tudo bem?\",\"Hello, how are you?\"\n" +
+		"menu.text,Escolha,\n" +
+		"menu.buttons[0].label,Sim,\n" +
+		"menu.buttons[1].label,\"Não, obrigado\",\n"
+	if buf.String() != want {
+		t.Fatalf("export =\n%s", buf.String())
+	}
+
+	// Tradutor preenche o inglês e adiciona o espanhol
+	filled := "key,pt-BR,en,es\n" +
+		"welcome.text,IGNORADO,\"Hello, how are you?\",\"Hola, ¿qué tal?\"\n" +
+		"menu.text,Escolha,Choose,Elige\n" +
+		"menu.buttons[0].label,Sim,Yes,\n" +
+		"menu.buttons[1].label,\"Não, obrigado\",\"No, thanks\",\"No, gracias\"\n"
+	if err := io.ImportTranslationsCSV(strings.NewReader(filled), &design); err != nil {
+		t.Fatal(err)
+	}
+	if got := design.I18n.Variants("menu.buttons[1].label"); !reflect.DeepEqual(got, map[string]string{"en": "No, thanks", "es": "No, gracias"}) {
+		t.Errorf("variants = %v", got)
+	}
+	if _, ok := design.I18n.Variants("menu.buttons[0].label")["es"]; ok {
+		t.Error("empty cell imported")
+	}
+	if !reflect.DeepEqual(design.I18n.Locales, []string{"pt-BR", "en", "es"}) {
+		t.Errorf("locales = %v", design.I18n.Locales)
+	}
+
+	// Exportar de novo reproduz o CSV importado (exceto a coluna fonte)
+	buf.Reset()
+	if err := io.ExportTranslationsCSV(&buf, design); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != strings.Replace(filled, "IGNORADO", "\"Olá, tudo bem?\"", 1) {
+		t.Errorf("round trip =\n%s", got)
+	}
+
+	if err := io.ImportTranslationsCSV(strings.NewReader("key,en\nghost.text,Boo\n"), &design); err == nil || !strings.Contains(err.Error(), "unknown key") {
+		t.Errorf("unknown key err = %v", err)
+	}
+}
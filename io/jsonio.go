@@ -31,13 +31,22 @@ func (JSONCodec) EncodePlan(p RuntimePlan) ([]byte, error) { return json.Marshal
 
 // DesignDoc representa um documento de design editável (formato de entrada)
 type DesignDoc struct {
-	Schema    string         `json:"schema"`    // Versão do schema (ex: "flowkit/1.0")
-	Bot       Bot            `json:"bot"`       // Informações do bot
-	Version   Version        `json:"version"`   // Versão do fluxo
-	Entries   []flow.Entry   `json:"entries"`   // Pontos de entrada do fluxo
-	Variables Variables      `json:"variables"` // Variáveis do bot (context, state, global)
-	Graph     Graph          `json:"graph"`     // Grafo de nós e arestas
-	Props     map[string]any `json:"props"`     // Propriedades compartilhadas/templates
+	Schema    string              `json:"schema"`             // Versão do schema (ex: "flowkit/1.0")
+	Bot       Bot                 `json:"bot"`                // Informações do bot
+	Version   Version             `json:"version"`            // Versão do fluxo
+	Entries   []flow.Entry        `json:"entries"`            // Pontos de entrada do fluxo
+	Variables Variables           `json:"variables"`          // Variáveis do bot (context, state, global)
+	Graph     Graph               `json:"graph"`              // Grafo de nós e arestas
+	Props     map[string]any      `json:"props"`              // Propriedades compartilhadas/templates
+	Subflows  map[string]Subflow  `json:"subflows,omitempty"` // Módulos reutilizáveis invocados por nós "subflow"
+	I18n      *I18n               `json:"i18n,omitempty"`     // Traduções dos textos por locale
+	Intents   map[string][]string `json:"intents,omitempty"`  // Frases de exemplo por intent (treino do classificador do modo intent)
+
+	// Horário de atendimento humano (human_handoff fora do horário usa off_hours_message)
+	BusinessHours *BusinessHours `json:"business_hours,omitempty"`
+
+	// Substituições de nós por canal: canal -> node_id -> fallback (ver CompileAll)
+	ChannelFallbacks map[string]map[string]ChannelFallback `json:"channel_fallbacks,omitempty"`
 }
 
 // Variables contém as variáveis disponíveis no bot
@@ -80,12 +89,27 @@ func (d DesignDoc) ResolveProps(n flow.Node) map[string]any {
 
 // RuntimePlan representa um plano compilado pronto para execução
 type RuntimePlan struct {
-	Schema         string         `json:"schema"`                // Versão do schema
-	PlanID         string         `json:"plan_id"`               // ID único do plano
-	DesignChecksum string         `json:"design_checksum"`       // Checksum do design original
-	Adapter        string         `json:"adapter"`               // Adapter utilizado (whatsapp, etc.)
-	Routes         []Route        `json:"routes"`                // Rotas compiladas
-	Constraints    map[string]any `json:"constraints,omitempty"` // Restrições do adapter
+	Schema         string              `json:"schema"`                 // Versão do schema
+	PlanID         string              `json:"plan_id"`                // ID único do plano
+	DesignChecksum string              `json:"design_checksum"`        // Checksum do design original
+	Adapter        string              `json:"adapter"`                // Adapter utilizado (whatsapp, etc.)
+	Routes         []Route             `json:"routes"`                 // Rotas compiladas
+	Constraints    map[string]any      `json:"constraints,omitempty"`  // Restrições do adapter
+	Subflows       []SubflowBinding    `json:"subflows,omitempty"`     // Subflows inlined/linkados no plano
+	I18n           *I18n               `json:"i18n,omitempty"`         // Locales e fallbacks (variantes ficam nos TextValue e em meta.i18n)
+	Degradations   []Degradation       `json:"degradations,omitempty"` // Degradações aplicadas por falta de capacidade do canal
+	Intents        map[string][]string `json:"intents,omitempty"`      // Exemplos de intents para treinar o classificador no runtime
+
+	// Horário de atendimento do human_handoff (copiado do design)
+	BusinessHours *BusinessHours `json:"business_hours,omitempty"`
+}
+
+// Degradation registra uma regra de degradação aplicada a um nó na compilação
+type Degradation struct {
+	Node string `json:"node,omitempty"` // ID do nó degradado
+	Rule string `json:"rule"`           // Regra aplicada (ex: "carousel_to_text")
+	From string `json:"from"`           // Tipo original do componente
+	To   string `json:"to"`             // Tipo resultante
 }
 
 // Route representa uma rota compilada para um nó específico
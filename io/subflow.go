@@ -0,0 +1,53 @@
+package io
+
+import "github.com/AgendoCerto/lib-bot/flow"
+
+// SubflowExitPrefix marca arestas internas de um subflow que saem por um output declarado
+// Ex.: {"from": "confirm", "to": "@confirmed"} sai do subflow pelo output "confirmed"
+const SubflowExitPrefix = "@"
+
+// Subflow representa um módulo de fluxo reutilizável invocado por nós do tipo "subflow"
+type Subflow struct {
+	ID      string         `json:"id"`               // Identificador do subflow (chave em DesignDoc.Subflows)
+	Title   string         `json:"title,omitempty"`  // Título para exibição no editor
+	Entry   flow.ID        `json:"entry"`            // Nó interno de entrada
+	Inputs  []string       `json:"inputs,omitempty"` // Parâmetros de entrada (expostos como context.<nome>)
+	Outputs []string       `json:"outputs"`          // Outputs declarados (mapeados para as arestas do nó pai)
+	Graph   Graph          `json:"graph"`            // Grafo interno do subflow
+	Props   map[string]any `json:"props,omitempty"`  // Propriedades compartilhadas do subflow
+}
+
+// ResolveProps resolve referências de propriedades de um nó interno do subflow
+func (s Subflow) ResolveProps(n flow.Node) map[string]any {
+	if n.PropsRef != "" {
+		if p, ok := s.Props[n.PropsRef]; ok {
+			if m, _ := p.(map[string]any); m != nil {
+				return m
+			}
+		}
+	}
+	return n.Props
+}
+
+// HasOutput verifica se o subflow declara o output informado
+func (s Subflow) HasOutput(name string) bool {
+	for _, o := range s.Outputs {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SubflowBinding descreve como um nó subflow foi resolvido no plano de execução
+type SubflowBinding struct {
+	Node      string            `json:"node"`                 // ID do nó subflow no grafo pai
+	Mode      string            `json:"mode"`                 // inline | link
+	Ref       string            `json:"ref,omitempty"`        // Subflow local referenciado
+	BotID     string            `json:"bot_id,omitempty"`     // Bot referenciado (subflow externo)
+	VersionID string            `json:"version_id,omitempty"` // Versão do bot referenciado
+	Entry     string            `json:"entry,omitempty"`      // Rota de entrada (modo inline)
+	Inputs    map[string]string `json:"inputs,omitempty"`     // Parâmetro -> expressão avaliada no pai
+	Outputs   map[string]string `json:"outputs,omitempty"`    // Output declarado -> nó de destino no pai
+	Edges     []flow.Edge       `json:"edges,omitempty"`      // Arestas internas reescritas (modo inline)
+}
@@ -0,0 +1,40 @@
+package liquid
+
+import (
+	"github.com/AgendoCerto/lib-bot/sanitize"
+)
+
+// FilterFunc é a implementação de um filtro Liquid customizado.
+// Recebe o valor de entrada e os argumentos do filtro ({{x | phone: "AR"}} → args = ["AR"]).
+type FilterFunc func(input string, args ...string) (string, error)
+
+// Filters retorna as implementações dos filtros customizados da lib, prontas
+// para registro no renderizador Liquid usado pelo runtime.
+func Filters() map[string]FilterFunc {
+	return map[string]FilterFunc{
+		"phone": PhoneFilter,
+		"cpf":   sanitizeFilter(sanitize.CPF),
+		"cnpj":  sanitizeFilter(sanitize.CNPJ),
+		"cep":   sanitizeFilter(sanitize.CEP),
+		"rg":    sanitizeFilter(sanitize.RG),
+		"pis":   sanitizeFilter(sanitize.PIS),
+	}
+}
+
+// PhoneFilter normaliza o telefone para E.164: {{phone | phone}} → +5511987654321.
+// O argumento opcional é a região padrão para números sem código do país
+// ({{phone | phone: "US"}}); sem argumento usa sanitize.DefaultPhoneRegion.
+func PhoneFilter(input string, args ...string) (string, error) {
+	region := sanitize.DefaultPhoneRegion
+	if len(args) > 0 && args[0] != "" {
+		region = args[0]
+	}
+	return sanitize.FormatE164(input, region)
+}
+
+// sanitizeFilter expõe um tipo de sanitização como filtro sem argumentos.
+func sanitizeFilter(sanitizationType sanitize.Type) FilterFunc {
+	return func(input string, _ ...string) (string, error) {
+		return sanitize.Default().Sanitize(input, sanitize.Config{Type: sanitizationType})
+	}
+}
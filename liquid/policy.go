@@ -163,15 +163,16 @@ func DefaultAllowedFilters() map[string]bool {
 		"url_decode":  true,
 
 		// 🌍 Filtros de formatação internacional
-		"phone":    true, // Formata telefone: {{phone | phone: "BR"}} ou {{phone | phone: "US"}}
+		"phone":    true, // Normaliza para E.164: {{phone | phone}} → +5511987654321 ou {{phone | phone: "US"}} (região padrão)
 		"currency": true, // Formata moeda: {{value | currency: "BRL"}} ou {{value | currency: "USD"}}
 		"money":    true, // Alias para currency
 
 		// 🇧🇷 Filtros de documentos brasileiros
 		"cpf":  true, // Formata CPF: {{doc | cpf}} → 123.456.789-00
-		"cnpj": true, // Formata CNPJ: {{doc | cnpj}} → 12.345.678/0001-00
+		"cnpj": true, // Formata CNPJ (numérico ou alfanumérico): {{doc | cnpj}} → 12.ABC.345/01DE-35
 		"cep":  true, // Formata CEP: {{cep | cep}} → 12345-678
 		"rg":   true, // Formata RG: {{doc | rg}} → 12.345.678-9
+		"pis":  true, // Formata PIS/PASEP: {{doc | pis}} → 120.54321.77-1
 
 		// 📅 Filtros de data/hora avançados
 		"date_tz":   true, // Data com timezone: {{date | date_tz: "America/Sao_Paulo", "%d/%m/%Y %H:%M"}}
@@ -22,7 +22,7 @@ import (
 func main() {
 	// Configuração de flags de linha de comando
 	in := flag.String("in", "", "Caminho do arquivo Design JSON (opcional; usa exemplo se vazio)")
-	out := flag.String("out", "plan", "Tipo de saída: plan | plan-full | reactflow | reactflow-auto-v | reactflow-auto-h")
+	out := flag.String("out", "plan", "Tipo de saída: plan | plan-full | bundle | reactflow | reactflow-auto-v | reactflow-auto-h")
 	outFile := flag.String("outfile", "", "Arquivo de saída (opcional; se vazio, imprime no stdout)")
 	adapterName := flag.String("adapter", "whatsapp", "Adapter: whatsapp (por enquanto)")
 	pretty := flag.Bool("pretty", true, "Imprimir JSON com identação")
@@ -59,6 +59,8 @@ func main() {
 		doPlan(design, reg, a, *pretty, finalOutFile, false)
 	case "plan-full":
 		doPlan(design, reg, a, *pretty, finalOutFile, true)
+	case "bundle":
+		doBundle(design, reg, *pretty, finalOutFile)
 	case "reactflow":
 		doReactFlow(design, *pretty, finalOutFile)
 	case "reactflow-auto-v":
@@ -66,7 +68,7 @@ func main() {
 	case "reactflow-auto-h":
 		doReactFlowAutoHorizontal(design, *pretty, finalOutFile)
 	default:
-		log.Fatalf("valor inválido para -out: %q (use: plan | plan-full | reactflow | reactflow-auto-v | reactflow-auto-h)", *out)
+		log.Fatalf("valor inválido para -out: %q (use: plan | plan-full | bundle | reactflow | reactflow-auto-v | reactflow-auto-h)", *out)
 	}
 }
 
@@ -139,6 +141,23 @@ func doPlan(design io.DesignDoc, reg *component.Registry, a adapter.Adapter, pre
 	}
 }
 
+// doBundle compila o design para todos os canais de bot.channels (um plano por canal)
+func doBundle(design io.DesignDoc, reg *component.Registry, pretty bool, outFile string) {
+	provider := adapter.NewProvider()
+	provider.Register(whatsapp.New())
+
+	bundle, err := compile.DefaultCompiler{}.CompileAll(context.Background(), design, reg, provider)
+	must(err)
+
+	fmt.Fprintf(os.Stderr, "Bundle: %d channel(s), valid=%v invalid=%v, %d errors, %d warnings\n",
+		len(bundle.Report.Channels), bundle.Report.Valid, bundle.Report.Invalid, bundle.Report.Errors, bundle.Report.Warnings)
+	for node, channels := range bundle.Report.ChannelOnly {
+		fmt.Fprintf(os.Stderr, " - node %s only supported on %v\n", node, channels)
+	}
+
+	writeJSON(bundle, pretty, outFile)
+}
+
 // doReactFlow converte o design para formato React Flow
 func doReactFlow(design io.DesignDoc, pretty bool, outFile string) {
 	nodes, edges := rf.DesignToReactFlow(design)
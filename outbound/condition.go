@@ -0,0 +1,299 @@
+package outbound
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+// ErrCondition expressão condicional inválida
+var ErrCondition = errors.New("outbound: invalid condition")
+
+// Evaluate avalia condition_expr contra a sessão
+// Gramática: caminhos (context.x, state.y.z, global.w), literais (strings entre
+// aspas, números, true, false, nil), comparações (== != > >= < <=), negação (!),
+// && e || e parênteses; chaves Liquid ({{ }}) ao redor da expressão são ignoradas
+// Caminho inexistente vale nil; um valor isolado vale pela sua veracidade
+func Evaluate(expr string, rctx runtime.Context) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{{") && strings.HasSuffix(expr, "}}") {
+		expr = strings.TrimSpace(expr[2 : len(expr)-2])
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return false, err
+	}
+	p := &parser{tokens: tokens, scope: rctx.LiquidScope()}
+	v, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("%w: unexpected %q", ErrCondition, p.tokens[p.pos].text)
+	}
+	return truthy(v), nil
+}
+
+// tokenKind tipo do token
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota // Caminho ou palavra-chave
+	tokString
+	tokNumber
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize separa a expressão em tokens
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string", ErrCondition)
+			}
+			tokens = append(tokens, token{tokString, s[i+1 : i+1+end]})
+			i += end + 2
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			j := i + 1
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, s[i:j]})
+			i = j
+		case unicode.IsLetter(rune(c)) || c == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_' || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, s[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", ">=", "<=", ">", "<", "!", "(", ")"} {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: unexpected %q", ErrCondition, c)
+			}
+			tokens = append(tokens, token{tokOp, op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// parser analisador descendente recursivo (precedência: ||, &&, comparação, !)
+type parser struct {
+	tokens []token
+	pos    int
+	scope  map[string]any
+}
+
+// accept consome o operador se for o próximo token
+func (p *parser) accept(op string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokOp && p.tokens[p.pos].text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) or() (any, error) {
+	left, err := p.and()
+	for err == nil && p.accept("||") {
+		var right any
+		right, err = p.and()
+		left = truthy(left) || truthy(right)
+	}
+	return left, err
+}
+
+func (p *parser) and() (any, error) {
+	left, err := p.comparison()
+	for err == nil && p.accept("&&") {
+		var right any
+		right, err = p.comparison()
+		left = truthy(left) && truthy(right)
+	}
+	return left, err
+}
+
+func (p *parser) comparison() (any, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", ">=", "<=", ">", "<"} {
+		if p.accept(op) {
+			right, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			return compare(left, op, right), nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (any, error) {
+	if p.accept("!") {
+		v, err := p.unary()
+		return !truthy(v), err
+	}
+	if p.accept("(") {
+		v, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("%w: missing ')'", ErrCondition)
+		}
+		return v, nil
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrCondition)
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokString:
+		return t.text, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: number %q", ErrCondition, t.text)
+		}
+		return f, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "nil", "null":
+			return nil, nil
+		}
+		return lookup(p.scope, t.text), nil
+	}
+	return nil, fmt.Errorf("%w: unexpected %q", ErrCondition, t.text)
+}
+
+// lookup resolve o caminho pontuado no escopo da sessão
+func lookup(scope map[string]any, path string) any {
+	var cur any = scope
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// compare compara números numericamente e os demais valores como texto
+func compare(left any, op string, right any) bool {
+	if l, ok := number(left); ok {
+		if r, ok := number(right); ok {
+			switch op {
+			case "==":
+				return l == r
+			case "!=":
+				return l != r
+			case ">":
+				return l > r
+			case ">=":
+				return l >= r
+			case "<":
+				return l < r
+			case "<=":
+				return l <= r
+			}
+		}
+	}
+
+	if left == nil || right == nil {
+		switch op {
+		case "==":
+			return left == nil && right == nil
+		case "!=":
+			return (left == nil) != (right == nil)
+		}
+		return false
+	}
+
+	l, r := fmt.Sprint(left), fmt.Sprint(right)
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	}
+	return false
+}
+
+// number converte valores numéricos (inclusive strings numéricas)
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// truthy veracidade: nil, false, "", 0 e coleções vazias são falsos
+func truthy(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	case map[string]any:
+		return len(x) > 0
+	case []any:
+		return len(x) > 0
+	}
+	if n, ok := number(v); ok {
+		return n != 0
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+// Package outbound agenda os envios de template do componente hsm_trigger:
+// modos immediate/schedule/condition, cooldown por chave, novas tentativas com
+// intervalo mínimo e trilha de auditoria de cada tentativa
+package outbound
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+)
+
+// Erros do agendador
+var (
+	ErrJobNotFound = errors.New("outbound: job not found")
+	ErrInvalidJob  = errors.New("outbound: invalid job")
+)
+
+// Clock relógio injetável (time.Now por padrão)
+type Clock func() time.Time
+
+// Modos de disparo do hsm_trigger
+const (
+	ModeImmediate = "immediate"
+	ModeSchedule  = "schedule"
+	ModeCondition = "condition"
+)
+
+// Reavaliação padrão do modo condition: condição falsa mantém o job pendente e
+// reavalia a cada ConditionInterval até ConditionDeadline (contado da criação)
+// ou ConditionMaxChecks avaliações
+const (
+	DefaultConditionInterval = 15 * time.Minute
+	DefaultConditionDeadline = 24 * time.Hour
+)
+
+// Config configuração de envio lida do spec do component.HSMTrigger
+type Config struct {
+	TemplateID         string            `json:"template_id"`
+	Language           string            `json:"language,omitempty"`
+	Variables          map[string]string `json:"variables,omitempty"`
+	Mode               string            `json:"trigger_mode"`
+	ScheduleIn         time.Duration     `json:"schedule_in,omitempty"`          // schedule.in_minutes
+	Condition          string            `json:"condition_expr,omitempty"`       // Avaliada na hora do envio
+	ConditionInterval  time.Duration     `json:"condition_interval,omitempty"`   // Padrão DefaultConditionInterval
+	ConditionDeadline  time.Duration     `json:"condition_deadline,omitempty"`   // Prazo desde a criação (padrão DefaultConditionDeadline)
+	ConditionMaxChecks int               `json:"condition_max_checks,omitempty"` // 0 = sem limite além do prazo
+	RetryCount         int               `json:"retry_count,omitempty"`          // Tentativas extras após a primeira falha
+	RetryInterval      time.Duration     `json:"retry_interval,omitempty"`       // Intervalo mínimo entre tentativas
+	CooldownKey        string            `json:"cooldown_key,omitempty"`         // Chave de cooldown (por sessão)
+	Cooldown           time.Duration     `json:"cooldown,omitempty"`
+}
+
+// ConfigFromSpec lê a configuração do spec do component.HSMTrigger
+func ConfigFromSpec(spec component.ComponentSpec) Config {
+	cfg := Config{Mode: ModeImmediate}
+	cfg.TemplateID, _ = spec.Meta["template_id"].(string)
+	cfg.Language, _ = spec.Meta["language"].(string)
+	if mode, _ := spec.Meta["trigger_mode"].(string); mode != "" {
+		cfg.Mode = mode
+	}
+	switch vars := spec.Meta["variables"].(type) {
+	case map[string]string:
+		cfg.Variables = vars
+	case map[string]any:
+		cfg.Variables = make(map[string]string, len(vars))
+		for k, v := range vars {
+			if s, ok := v.(string); ok {
+				cfg.Variables[k] = s
+			}
+		}
+	}
+	cfg.ScheduleIn = time.Duration(nestedInt(spec.Meta["schedule"], "in_minutes")) * time.Minute
+	cfg.Condition, _ = spec.Meta["condition_expr"].(string)
+	cfg.RetryCount = nestedInt(spec.Meta["retries"], "count")
+	cfg.RetryInterval = time.Duration(nestedInt(spec.Meta["retries"], "min_interval_s")) * time.Second
+	cfg.CooldownKey, _ = spec.Meta["cooldown_key"].(string)
+	cfg.Cooldown = time.Duration(metaInt(spec.Meta["cooldown_s"])) * time.Second
+	return cfg
+}
+
+// nestedInt inteiro do mapa aninhado (map[string]int do spec gerado ou
+// map[string]any do JSON)
+func nestedInt(v any, key string) int {
+	switch m := v.(type) {
+	case map[string]int:
+		return m[key]
+	case map[string]any:
+		return metaInt(m[key])
+	}
+	return 0
+}
+
+// metaInt inteiro do Meta (int no spec gerado, float64 no JSON)
+func metaInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+// Status estado do job
+type Status string
+
+const (
+	StatusPending Status = "pending" // Aguardando NextAt
+	StatusSent    Status = "sent"
+	StatusFailed  Status = "failed"  // Tentativas esgotadas
+	StatusSkipped Status = "skipped" // Cooldown ativo, condição inválida ou falsa até o prazo
+)
+
+// Job envio de template agendado para uma sessão
+type Job struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	NodeID    string    `json:"node_id,omitempty"`
+	Config    Config    `json:"config"`
+	Status    Status    `json:"status"`
+	Attempts  int       `json:"attempts"` // Envios tentados
+	NextAt    time.Time `json:"next_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Resultados registrados na auditoria
+const (
+	OutcomeSent           = "sent"
+	OutcomeSendFailed     = "send_failed"     // Falha com nova tentativa agendada
+	OutcomeFailed         = "failed"          // Falha sem tentativas restantes
+	OutcomeCooldown       = "cooldown"        // Envio suprimido pelo cooldown da chave
+	OutcomeConditionFalse = "condition_false" // Condição avaliada como falsa (reavaliada até o prazo)
+	OutcomeConditionError = "condition_error" // Condição inválida
+)
+
+// Attempt registro de auditoria de uma tentativa de processamento do job
+type Attempt struct {
+	JobID     string    `json:"job_id"`
+	SessionID string    `json:"session_id"`
+	Template  string    `json:"template_id"`
+	Seq       int       `json:"seq"` // Ordem da tentativa no job (1..n)
+	At        time.Time `json:"at"`
+	Outcome   string    `json:"outcome"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// JobStore persistência dos jobs, cooldowns e auditoria
+type JobStore interface {
+	Create(ctx context.Context, job Job) (Job, error) // Atribui o ID
+	Update(ctx context.Context, job Job) error
+	Get(ctx context.Context, id string) (Job, error)
+	Due(ctx context.Context, now time.Time) ([]Job, error) // Pendentes com NextAt <= now, por NextAt
+
+	LastSent(ctx context.Context, key string) (time.Time, bool, error) // Último envio na chave de cooldown
+	MarkSent(ctx context.Context, key string, at time.Time) error
+
+	AppendAttempt(ctx context.Context, a Attempt) error
+	Attempts(ctx context.Context, jobID string) ([]Attempt, error)
+}
+
+// Message envio de template
+type Message struct {
+	SessionID  string
+	TemplateID string
+	Language   string
+	Variables  map[string]string
+}
+
+// Sender envia o template pelo canal
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SenderFunc adapta função para Sender
+type SenderFunc func(ctx context.Context, msg Message) error
+
+func (f SenderFunc) Send(ctx context.Context, msg Message) error { return f(ctx, msg) }
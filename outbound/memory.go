@@ -0,0 +1,100 @@
+package outbound
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore JobStore em memória (um processo; não persiste entre reinícios)
+type MemoryStore struct {
+	mu       sync.Mutex
+	jobs     map[string]Job
+	order    []string             // IDs em ordem de criação
+	sent     map[string]time.Time // chave de cooldown → último envio
+	attempts map[string][]Attempt // job → auditoria
+	seq      int
+}
+
+var _ JobStore = (*MemoryStore)(nil)
+
+// NewMemoryStore cria o armazenamento em memória
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: map[string]Job{}, sent: map[string]time.Time{}, attempts: map[string][]Attempt{}}
+}
+
+func (m *MemoryStore) Create(_ context.Context, job Job) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seq++
+	job.ID = fmt.Sprintf("job-%d", m.seq)
+	m.jobs[job.ID] = job
+	m.order = append(m.order, job.ID)
+	return job, nil
+}
+
+func (m *MemoryStore) Update(_ context.Context, job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.jobs[job.ID]; !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, job.ID)
+	}
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+func (m *MemoryStore) Due(_ context.Context, now time.Time) ([]Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []Job
+	for _, id := range m.order {
+		if job := m.jobs[id]; job.Status == StatusPending && !job.NextAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAt.Before(due[j].NextAt) })
+	return due, nil
+}
+
+func (m *MemoryStore) LastSent(_ context.Context, key string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	at, ok := m.sent[key]
+	return at, ok, nil
+}
+
+func (m *MemoryStore) MarkSent(_ context.Context, key string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent[key] = at
+	return nil
+}
+
+func (m *MemoryStore) AppendAttempt(_ context.Context, a Attempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts[a.JobID] = append(m.attempts[a.JobID], a)
+	return nil
+}
+
+func (m *MemoryStore) Attempts(_ context.Context, jobID string) ([]Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Attempt(nil), m.attempts[jobID]...), nil
+}
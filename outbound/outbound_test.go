@@ -0,0 +1,232 @@
+package outbound_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/liquid"
+	"github.com/AgendoCerto/lib-bot/outbound"
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+func TestConfigFromSpec(t *testing.T) {
+	spec, err := component.NewHSMTrigger(liquid.NoRenderDetector{}).
+		WithTemplate("lembrete", "pt_BR").
+		WithTriggerMode("schedule", 30).
+		WithRetry(2, 60).
+		WithCooldown("reminder", 3600).
+		Spec(context.Background(), runtime.Context{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := outbound.ConfigFromSpec(spec)
+	if cfg.TemplateID != "lembrete" || cfg.Mode != outbound.ModeSchedule || cfg.ScheduleIn != 30*time.Minute ||
+		cfg.RetryCount != 2 || cfg.RetryInterval != time.Minute || cfg.CooldownKey != "reminder" || cfg.Cooldown != time.Hour {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rctx := runtime.Context{
+		State:   map[string]any{"plan": "pro", "visits": float64(3), "opt_out": false},
+		Context: map[string]any{"cart": map[string]any{"total": 150}},
+	}
+	cases := map[string]bool{
+		`state.plan == "pro"`:                           true,
+		`state.visits >= 3 && !state.opt_out`:           true,
+		`context.cart.total > 200 || state.plan == 'x'`: false,
+		`(state.missing || state.visits < 5) && true`:   true,
+		`{{ state.missing }}`:                           false,
+		`state.missing == nil`:                          true,
+	}
+	for expr, want := range cases {
+		if got, err := outbound.Evaluate(expr, rctx); err != nil || got != want {
+			t.Errorf("Evaluate(%s) = %v, %v; want %v", expr, got, err, want)
+		}
+	}
+	for _, bad := range []string{`state.plan ==`, `(state.plan`, `"unterminated`, `state.plan # 1`} {
+		if _, err := outbound.Evaluate(bad, rctx); !errors.Is(err, outbound.ErrCondition) {
+			t.Errorf("Evaluate(%s) err = %v", bad, err)
+		}
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	failures := 1
+	var sent []outbound.Message
+	sender := outbound.SenderFunc(func(_ context.Context, msg outbound.Message) error {
+		if failures > 0 {
+			failures--
+			return errors.New("provider unavailable")
+		}
+		sent = append(sent, msg)
+		return nil
+	})
+	scheduler := outbound.NewScheduler(outbound.NewMemoryStore(), sender).WithClock(func() time.Time { return now })
+
+	cfg := outbound.Config{
+		TemplateID: "lembrete", Mode: outbound.ModeSchedule, ScheduleIn: 30 * time.Minute,
+		RetryCount: 1, RetryInterval: time.Minute, CooldownKey: "reminder", Cooldown: time.Hour,
+	}
+	job, err := scheduler.Enqueue(ctx, "s1", "n1", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 0 {
+		t.Fatalf("scheduled job ran early: %+v", attempts)
+	}
+
+	now = now.Add(30 * time.Minute)
+	attempts, _ := scheduler.Run(ctx)
+	if len(attempts) != 1 || attempts[0].Outcome != outbound.OutcomeSendFailed {
+		t.Fatalf("first attempt = %+v", attempts)
+	}
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 0 {
+		t.Fatal("retry ignored min interval")
+	}
+
+	now = now.Add(time.Minute)
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 1 || attempts[0].Outcome != outbound.OutcomeSent || len(sent) != 1 {
+		t.Fatalf("retry = %+v, sent %d", attempts, len(sent))
+	}
+
+	// Mesma chave dentro do cooldown: suprimido
+	second, _ := scheduler.Enqueue(ctx, "s1", "n2", outbound.Config{TemplateID: "lembrete", CooldownKey: "reminder", Cooldown: time.Hour})
+	other, _ := scheduler.Enqueue(ctx, "s2", "n2", outbound.Config{TemplateID: "lembrete", CooldownKey: "reminder", Cooldown: time.Hour})
+	attempts, _ = scheduler.Run(ctx)
+	if len(attempts) != 2 || attempts[0].JobID != second.ID || attempts[0].Outcome != outbound.OutcomeCooldown || attempts[1].JobID != other.ID || attempts[1].Outcome != outbound.OutcomeSent {
+		t.Fatalf("cooldown run = %+v", attempts)
+	}
+
+	trail, _ := scheduler.Attempts(ctx, job.ID)
+	if len(trail) != 2 || trail[0].Seq != 1 || trail[1].Seq != 2 || trail[0].Detail == "" {
+		t.Errorf("audit trail = %+v", trail)
+	}
+}
+
+func TestSchedulerCondition(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	confirmed := map[string]bool{"s1": true}
+	sessions := outbound.SessionsFunc(func(_ context.Context, sessionID string) (runtime.Context, error) {
+		return runtime.Context{State: map[string]any{"confirmed": confirmed[sessionID]}}, nil
+	})
+	sends := 0
+	store := outbound.NewMemoryStore()
+	scheduler := outbound.NewScheduler(store, outbound.SenderFunc(func(context.Context, outbound.Message) error {
+		sends++
+		return nil
+	})).WithSessions(sessions).WithClock(func() time.Time { return now })
+
+	cfg := outbound.Config{
+		TemplateID: "pos_consulta", Mode: outbound.ModeCondition, Condition: "state.confirmed",
+		ConditionInterval: 10 * time.Minute, ConditionDeadline: time.Hour,
+	}
+	scheduler.Enqueue(ctx, "s1", "n1", cfg)
+	waiting, _ := scheduler.Enqueue(ctx, "s2", "n1", cfg)
+	attempts, err := scheduler.Run(ctx)
+	if err != nil || len(attempts) != 2 || sends != 1 || attempts[1].Outcome != outbound.OutcomeConditionFalse {
+		t.Fatalf("condition run = %+v, %v (sends %d)", attempts, err, sends)
+	}
+
+	// Condição falsa continua pendente e só é reavaliada após o intervalo
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 0 {
+		t.Fatalf("recheck ignored interval: %+v", attempts)
+	}
+	now = now.Add(10 * time.Minute)
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 1 || attempts[0].Outcome != outbound.OutcomeConditionFalse {
+		t.Fatalf("second check = %+v", attempts)
+	}
+	confirmed["s2"] = true
+	now = now.Add(10 * time.Minute)
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 1 || attempts[0].Outcome != outbound.OutcomeSent || sends != 2 {
+		t.Fatalf("check after condition turned true = %+v (sends %d)", attempts, sends)
+	}
+	if trail, _ := scheduler.Attempts(ctx, waiting.ID); len(trail) != 3 || trail[0].Outcome != outbound.OutcomeConditionFalse || trail[2].Seq != 3 {
+		t.Errorf("audit trail = %+v", trail)
+	}
+
+	// Prazo esgotado encerra o job
+	expiring, _ := scheduler.Enqueue(ctx, "s3", "n1", cfg)
+	for range 7 { // Avaliações de 0 a 60min
+		scheduler.Run(ctx)
+		now = now.Add(10 * time.Minute)
+	}
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 0 {
+		t.Errorf("job ran after deadline: %+v", attempts)
+	}
+	if job, _ := store.Get(ctx, expiring.ID); job.Status != outbound.StatusSkipped {
+		t.Errorf("expired job = %+v", job)
+	}
+
+	// Limite de avaliações
+	limited := cfg
+	limited.ConditionMaxChecks = 2
+	capped, _ := scheduler.Enqueue(ctx, "s4", "n1", limited)
+	scheduler.Run(ctx)
+	now = now.Add(10 * time.Minute)
+	scheduler.Run(ctx)
+	now = now.Add(10 * time.Minute)
+	if attempts, _ := scheduler.Run(ctx); len(attempts) != 0 {
+		t.Errorf("job ran after max checks: %+v", attempts)
+	}
+	if trail, _ := scheduler.Attempts(ctx, capped.ID); len(trail) != 2 || trail[1].Outcome != outbound.OutcomeConditionFalse {
+		t.Errorf("capped audit trail = %+v", trail)
+	}
+
+	if _, err := scheduler.Enqueue(ctx, "s1", "n1", outbound.Config{TemplateID: "x", Mode: outbound.ModeCondition}); !errors.Is(err, outbound.ErrInvalidJob) {
+		t.Errorf("missing condition err = %v", err)
+	}
+}
+
+// failingStore JobStore cuja consulta de cooldown falha
+type failingStore struct{ *outbound.MemoryStore }
+
+func (failingStore) LastSent(context.Context, string) (time.Time, bool, error) {
+	return time.Time{}, false, errors.New("store unavailable")
+}
+
+func TestSchedulerRunContinuesAfterJobError(t *testing.T) {
+	ctx := context.Background()
+	sessions := outbound.SessionsFunc(func(_ context.Context, sessionID string) (runtime.Context, error) {
+		if sessionID == "gone" {
+			return runtime.Context{}, errors.New("session expired")
+		}
+		return runtime.Context{State: map[string]any{"confirmed": true}}, nil
+	})
+	sends := 0
+	sender := outbound.SenderFunc(func(context.Context, outbound.Message) error {
+		sends++
+		return nil
+	})
+	store := failingStore{outbound.NewMemoryStore()}
+	scheduler := outbound.NewScheduler(store, sender).WithSessions(sessions)
+
+	gone, _ := scheduler.Enqueue(ctx, "gone", "n1", outbound.Config{TemplateID: "t", Mode: outbound.ModeCondition, Condition: "state.confirmed"})
+	broken, _ := scheduler.Enqueue(ctx, "s1", "n1", outbound.Config{TemplateID: "t", CooldownKey: "k", Cooldown: time.Hour})
+	scheduler.Enqueue(ctx, "s2", "n1", outbound.Config{TemplateID: "t"})
+
+	attempts, err := scheduler.Run(ctx)
+	if err == nil || len(attempts) != 3 || sends != 1 {
+		t.Fatalf("run = %+v, %v (sends %d)", attempts, err, sends)
+	}
+	if attempts[0].Outcome != outbound.OutcomeConditionError || attempts[1].Outcome != outbound.OutcomeFailed || attempts[2].Outcome != outbound.OutcomeSent {
+		t.Errorf("outcomes = %+v", attempts)
+	}
+	if job, _ := store.Get(ctx, gone.ID); job.Status != outbound.StatusSkipped {
+		t.Errorf("gone session job = %+v", job)
+	}
+	if job, _ := store.Get(ctx, broken.ID); job.Status != outbound.StatusFailed || job.LastError == "" {
+		t.Errorf("broken job = %+v", job)
+	}
+
+	// Nada fica preso na fila
+	if attempts, err := scheduler.Run(ctx); err != nil || len(attempts) != 0 {
+		t.Errorf("second run = %+v, %v", attempts, err)
+	}
+}
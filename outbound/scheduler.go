@@ -0,0 +1,269 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+// Sessions fornece o contexto da sessão para avaliar condições
+type Sessions interface {
+	Context(ctx context.Context, sessionID string) (runtime.Context, error)
+}
+
+// SessionsFunc adapta função para Sessions
+type SessionsFunc func(ctx context.Context, sessionID string) (runtime.Context, error)
+
+func (f SessionsFunc) Context(ctx context.Context, sessionID string) (runtime.Context, error) {
+	return f(ctx, sessionID)
+}
+
+// Scheduler enfileira e processa os envios de template
+// Enqueue agenda conforme o modo; Run processa os jobs vencidos (chamar
+// periodicamente): avalia a condição (reavaliando enquanto falsa), respeita o
+// cooldown e reagenda falhas
+type Scheduler struct {
+	store    JobStore
+	sender   Sender
+	sessions Sessions
+	now      Clock
+}
+
+// NewScheduler cria o agendador
+func NewScheduler(store JobStore, sender Sender) *Scheduler {
+	return &Scheduler{store: store, sender: sender, now: time.Now}
+}
+
+// WithSessions define a fonte de contexto das sessões (obrigatória para o modo condition)
+func (s *Scheduler) WithSessions(sessions Sessions) *Scheduler {
+	cp := *s
+	cp.sessions = sessions
+	return &cp
+}
+
+// WithClock define o relógio
+func (s *Scheduler) WithClock(now Clock) *Scheduler {
+	cp := *s
+	cp.now = now
+	return &cp
+}
+
+// Enqueue agenda o envio: immediate e condition vencem agora, schedule após
+// ScheduleIn
+func (s *Scheduler) Enqueue(ctx context.Context, sessionID, nodeID string, cfg Config) (Job, error) {
+	if sessionID == "" || cfg.TemplateID == "" {
+		return Job{}, fmt.Errorf("%w: session and template_id are required", ErrInvalidJob)
+	}
+
+	now := s.now()
+	job := Job{
+		SessionID: sessionID,
+		NodeID:    nodeID,
+		Config:    cfg,
+		Status:    StatusPending,
+		NextAt:    now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	switch cfg.Mode {
+	case ModeImmediate, "":
+	case ModeSchedule:
+		job.NextAt = now.Add(cfg.ScheduleIn)
+	case ModeCondition:
+		if cfg.Condition == "" {
+			return Job{}, fmt.Errorf("%w: condition mode requires condition_expr", ErrInvalidJob)
+		}
+	default:
+		return Job{}, fmt.Errorf("%w: unknown trigger_mode %q", ErrInvalidJob, cfg.Mode)
+	}
+
+	job, err := s.store.Create(ctx, job)
+	if err != nil {
+		return Job{}, fmt.Errorf("outbound enqueue %s: %w", sessionID, err)
+	}
+	return job, nil
+}
+
+// Run processa os jobs vencidos e retorna as tentativas registradas; erros de
+// um job não interrompem os demais e voltam agregados
+func (s *Scheduler) Run(ctx context.Context) ([]Attempt, error) {
+	now := s.now()
+	jobs, err := s.store.Due(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("outbound run: %w", err)
+	}
+
+	var attempts []Attempt
+	var errs []error
+	for _, job := range jobs {
+		a, err := s.process(ctx, job, now)
+		if err != nil {
+			// Falha ao processar não pode travar a fila: o job é encerrado como
+			// failed e os demais seguem
+			errs = append(errs, fmt.Errorf("outbound job %s: %w", job.ID, err))
+			if a, err = s.fail(ctx, job, now, err); err != nil {
+				errs = append(errs, fmt.Errorf("outbound job %s: %w", job.ID, err))
+				continue
+			}
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, errors.Join(errs...)
+}
+
+// Attempts trilha de auditoria do job
+func (s *Scheduler) Attempts(ctx context.Context, jobID string) ([]Attempt, error) {
+	return s.store.Attempts(ctx, jobID)
+}
+
+// process executa uma tentativa do job e grava job e auditoria
+func (s *Scheduler) process(ctx context.Context, job Job, now time.Time) (Attempt, error) {
+	prev, err := s.store.Attempts(ctx, job.ID)
+	if err != nil {
+		return Attempt{}, err
+	}
+	a := Attempt{
+		JobID:     job.ID,
+		SessionID: job.SessionID,
+		Template:  job.Config.TemplateID,
+		Seq:       len(prev) + 1,
+		At:        now,
+	}
+	cfg := job.Config
+
+	a.Outcome, a.Detail, err = s.precheck(ctx, job, now)
+	if err != nil {
+		return Attempt{}, err
+	}
+	switch {
+	case a.Outcome == OutcomeConditionFalse:
+		s.recheck(&job, &a, prev, now)
+	case a.Outcome != "":
+		job.Status = StatusSkipped
+	default:
+		job.Attempts++
+		sendErr := s.sender.Send(ctx, Message{
+			SessionID:  job.SessionID,
+			TemplateID: cfg.TemplateID,
+			Language:   cfg.Language,
+			Variables:  cfg.Variables,
+		})
+		switch {
+		case sendErr == nil:
+			job.Status, job.LastError, a.Outcome = StatusSent, "", OutcomeSent
+			if cfg.CooldownKey != "" {
+				if err := s.store.MarkSent(ctx, cooldownKey(job), now); err != nil {
+					return Attempt{}, err
+				}
+			}
+		case job.Attempts <= cfg.RetryCount:
+			job.NextAt = now.Add(cfg.RetryInterval)
+			job.LastError, a.Outcome, a.Detail = sendErr.Error(), OutcomeSendFailed, sendErr.Error()
+		default:
+			job.Status = StatusFailed
+			job.LastError, a.Outcome, a.Detail = sendErr.Error(), OutcomeFailed, sendErr.Error()
+		}
+	}
+
+	job.UpdatedAt = now
+	if err := s.store.Update(ctx, job); err != nil {
+		return Attempt{}, err
+	}
+	if err := s.store.AppendAttempt(ctx, a); err != nil {
+		return Attempt{}, err
+	}
+	return a, nil
+}
+
+// fail encerra como failed o job cujo processamento falhou e registra a tentativa
+func (s *Scheduler) fail(ctx context.Context, job Job, now time.Time, cause error) (Attempt, error) {
+	prev, _ := s.store.Attempts(ctx, job.ID)
+	a := Attempt{
+		JobID:     job.ID,
+		SessionID: job.SessionID,
+		Template:  job.Config.TemplateID,
+		Seq:       len(prev) + 1,
+		At:        now,
+		Outcome:   OutcomeFailed,
+		Detail:    cause.Error(),
+	}
+	job.Status, job.LastError, job.UpdatedAt = StatusFailed, cause.Error(), now
+	if err := s.store.Update(ctx, job); err != nil {
+		return Attempt{}, err
+	}
+	if err := s.store.AppendAttempt(ctx, a); err != nil {
+		return Attempt{}, err
+	}
+	return a, nil
+}
+
+// recheck reagenda a avaliação da condição falsa; esgotado o prazo ou o limite
+// de avaliações, o job é encerrado como skipped
+func (s *Scheduler) recheck(job *Job, a *Attempt, prev []Attempt, now time.Time) {
+	cfg := job.Config
+	interval, deadline := cfg.ConditionInterval, cfg.ConditionDeadline
+	if interval <= 0 {
+		interval = DefaultConditionInterval
+	}
+	if deadline <= 0 {
+		deadline = DefaultConditionDeadline
+	}
+
+	checks := 1 // Inclui a avaliação atual
+	for _, p := range prev {
+		if p.Outcome == OutcomeConditionFalse {
+			checks++
+		}
+	}
+	next := now.Add(interval)
+	switch {
+	case cfg.ConditionMaxChecks > 0 && checks >= cfg.ConditionMaxChecks:
+		job.Status = StatusSkipped
+		a.Detail += fmt.Sprintf(" (gave up after %d checks)", checks)
+	case next.After(job.CreatedAt.Add(deadline)):
+		job.Status = StatusSkipped
+		a.Detail += " (deadline reached)"
+	default:
+		job.NextAt = next
+	}
+}
+
+// precheck avalia a condição e o cooldown; Outcome vazio libera o envio
+func (s *Scheduler) precheck(ctx context.Context, job Job, now time.Time) (string, string, error) {
+	cfg := job.Config
+	if cfg.Mode == ModeCondition {
+		if s.sessions == nil {
+			return OutcomeConditionError, "no session source configured", nil
+		}
+		rctx, err := s.sessions.Context(ctx, job.SessionID)
+		if err != nil {
+			return OutcomeConditionError, "session: " + err.Error(), nil // Sessão expirada ou removida
+		}
+		ok, err := Evaluate(cfg.Condition, rctx)
+		if err != nil {
+			return OutcomeConditionError, err.Error(), nil
+		}
+		if !ok {
+			return OutcomeConditionFalse, cfg.Condition, nil
+		}
+	}
+
+	if cfg.CooldownKey != "" && cfg.Cooldown > 0 {
+		last, ok, err := s.store.LastSent(ctx, cooldownKey(job))
+		if err != nil {
+			return "", "", err
+		}
+		if ok && now.Before(last.Add(cfg.Cooldown)) {
+			return OutcomeCooldown, "last sent " + last.Format(time.RFC3339), nil
+		}
+	}
+	return "", "", nil
+}
+
+// cooldownKey chave de cooldown escopada pela sessão
+func cooldownKey(job Job) string {
+	return job.SessionID + "|" + job.Config.CooldownKey
+}
@@ -0,0 +1,131 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FakeProvider provedor em memória para testes: links são criados pendentes e
+// mudam de status via Settle; os webhooks são o JSON de Event
+type FakeProvider struct {
+	mu      sync.Mutex
+	links   map[string]*Link
+	byKey   map[string]string // idempotency key → link
+	created int
+	now     Clock
+}
+
+var (
+	_ PaymentProvider = (*FakeProvider)(nil)
+	_ LinkCanceller   = (*FakeProvider)(nil)
+)
+
+// NewFakeProvider cria o provedor fake
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{links: map[string]*Link{}, byKey: map[string]string{}, now: time.Now}
+}
+
+// SetClock define o relógio usado para expirar links
+func (f *FakeProvider) SetClock(now Clock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// CreateLink cria o link (ou retorna o existente para a mesma IdempotencyKey)
+func (f *FakeProvider) CreateLink(_ context.Context, req CreateRequest) (Link, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id, ok := f.byKey[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		return *f.links[id], nil
+	}
+	if req.Amount <= 0 {
+		return Link{}, fmt.Errorf("%w: %d", ErrInvalidAmount, req.Amount)
+	}
+
+	f.created++
+	id := fmt.Sprintf("fake_%d", f.created)
+	link := &Link{
+		ID:             id,
+		URL:            "https://pay.example.com/" + id,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		ExpiresAt:      req.ExpiresAt,
+		IdempotencyKey: req.IdempotencyKey,
+		Status:         StatusPending,
+		Metadata:       req.Metadata,
+	}
+	f.links[id] = link
+	if req.IdempotencyKey != "" {
+		f.byKey[req.IdempotencyKey] = id
+	}
+	return *link, nil
+}
+
+// Status status atual; links pendentes vencidos passam a expired
+func (f *FakeProvider) Status(_ context.Context, linkID string) (Status, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, ok := f.links[linkID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrLinkNotFound, linkID)
+	}
+	if link.Status == StatusPending && !link.ExpiresAt.IsZero() && !f.now().Before(link.ExpiresAt) {
+		link.Status = StatusExpired
+	}
+	return link.Status, nil
+}
+
+// CancelLink cancela o link pendente; links já terminados não podem ser cancelados
+func (f *FakeProvider) CancelLink(_ context.Context, linkID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, ok := f.links[linkID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrLinkNotFound, linkID)
+	}
+	if link.Status != StatusPending {
+		return fmt.Errorf("%w: %s is %s", ErrNotCancellable, linkID, link.Status)
+	}
+	link.Status = StatusCancelled
+	return nil
+}
+
+// ParseWebhook decodifica o JSON de Event gerado por Settle
+func (f *FakeProvider) ParseWebhook(_ context.Context, _ http.Header, body []byte) (Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return Event{}, fmt.Errorf("%w: %w", ErrInvalidWebhook, err)
+	}
+	if event.LinkID == "" || event.Status == "" {
+		return Event{}, fmt.Errorf("%w: missing link_id or status", ErrInvalidWebhook)
+	}
+	return event, nil
+}
+
+// Settle muda o status do link e retorna o corpo do webhook correspondente
+func (f *FakeProvider) Settle(linkID string, status Status) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, ok := f.links[linkID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLinkNotFound, linkID)
+	}
+	link.Status = status
+	return json.Marshal(Event{ID: fmt.Sprintf("evt_%s_%s", linkID, status), LinkID: linkID, Status: status, At: f.now()})
+}
+
+// Created quantidade de links efetivamente criados (cobranças distintas)
+func (f *FakeProvider) Created() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.created
+}
@@ -0,0 +1,178 @@
+// Package payment implementa o ciclo de vida do componente payment_link: interface
+// de provedor (criação do link, consulta de status e webhook), provedor fake para
+// testes e rastreador que concilia eventos com as saídas do componente e retoma a
+// sessão que aguarda o pagamento, sem cobrar duas vezes quem reentra no nó
+package payment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/cart"
+	"github.com/AgendoCerto/lib-bot/component"
+)
+
+// Erros de pagamento
+var (
+	ErrLinkNotFound   = errors.New("payment: link not found")
+	ErrInvalidWebhook = errors.New("payment: invalid webhook")
+	ErrInvalidAmount  = errors.New("payment: invalid amount")
+	ErrChargeChanged  = errors.New("payment: charge changed while a link is pending")
+	ErrNotCancellable = errors.New("payment: link cannot be cancelled")
+)
+
+// Clock relógio injetável (time.Now por padrão)
+type Clock func() time.Time
+
+// Status situação do link no provedor
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusPaid      Status = "paid"
+	StatusExpired   Status = "expired"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Terminal indica status final (não muda mais e gera saída do componente)
+func (s Status) Terminal() bool {
+	return s == StatusPaid || s == StatusExpired || s == StatusFailed || s == StatusCancelled
+}
+
+// Saídas do componente payment_link produzidas pelo rastreador
+const (
+	OutputPaid      = "paid"
+	OutputExpired   = "expired"
+	OutputFailed    = "failed"
+	OutputCancelled = "cancelled"
+)
+
+// OutputFor saída do componente para o status ("" enquanto pendente)
+func OutputFor(status Status) string {
+	switch status {
+	case StatusPaid:
+		return OutputPaid
+	case StatusExpired:
+		return OutputExpired
+	case StatusFailed:
+		return OutputFailed
+	case StatusCancelled:
+		return OutputCancelled
+	}
+	return ""
+}
+
+// CreateRequest pedido de criação de link
+type CreateRequest struct {
+	Amount         cart.Money        // Unidades menores da moeda
+	Currency       string            // ISO 4217
+	Description    string            // Texto exibido na página de pagamento
+	ExpiresAt      time.Time         // Link deixa de aceitar pagamento após este instante
+	IdempotencyKey string            // Mesma chave, mesmo link (nunca uma segunda cobrança)
+	Metadata       map[string]string // Repassado ao provedor (ex.: slot, pedido)
+}
+
+// Link link de pagamento criado no provedor
+type Link struct {
+	ID             string            `json:"id"`
+	URL            string            `json:"url"`
+	Amount         cart.Money        `json:"amount"`
+	Currency       string            `json:"currency"`
+	ExpiresAt      time.Time         `json:"expires_at"`
+	IdempotencyKey string            `json:"idempotency_key"`
+	Status         Status            `json:"status"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// Event mudança de status notificada pelo provedor (webhook) ou obtida por consulta
+type Event struct {
+	ID     string    `json:"id,omitempty"` // Identificador do evento no provedor (pode repetir em reenvios)
+	LinkID string    `json:"link_id"`
+	Status Status    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// PaymentProvider integração com o provedor de pagamentos (Stripe, Mercado Pago...)
+// CreateLink deve respeitar IdempotencyKey: repetir a chave retorna o link já criado
+type PaymentProvider interface {
+	CreateLink(ctx context.Context, req CreateRequest) (Link, error)
+	Status(ctx context.Context, linkID string) (Status, error)
+	ParseWebhook(ctx context.Context, header http.Header, body []byte) (Event, error)
+}
+
+// LinkCanceller interface opcional para provedores que cancelam links pendentes
+// Usada pelo Tracker quando a cobrança muda de valor ou moeda com um link em aberto
+type LinkCanceller interface {
+	CancelLink(ctx context.Context, linkID string) error
+}
+
+// Config configuração do componente (mesmas props e defaults do component.PaymentLink)
+type Config struct {
+	Amount      cart.Money
+	Currency    string
+	ExpiresIn   time.Duration
+	LockSlotTTL time.Duration
+	Metadata    map[string]string
+}
+
+// ConfigFromSpec lê a configuração do spec do component.PaymentLink
+// amount chega como texto decimal já renderizado ("49,90" ou "49.90")
+func ConfigFromSpec(spec component.ComponentSpec) (Config, error) {
+	cfg := Config{Currency: "BRL", ExpiresIn: 15 * time.Minute, LockSlotTTL: 900 * time.Second}
+	if v, ok := spec.Meta["currency"].(string); ok && v != "" {
+		cfg.Currency = v
+	}
+	currency, err := cart.LookupCurrency(cfg.Currency)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Currency = currency.Code
+
+	amount, _ := spec.Meta["amount"].(string)
+	if cfg.Amount, err = currency.ParseAmount(amount); err != nil || cfg.Amount <= 0 {
+		return cfg, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	if v := metaInt(spec.Meta, "expires_in_min"); v > 0 {
+		cfg.ExpiresIn = time.Duration(v) * time.Minute
+	}
+	if v := metaInt(spec.Meta, "lock_slot_ttl_s"); v > 0 {
+		cfg.LockSlotTTL = time.Duration(v) * time.Second
+	}
+	switch v := spec.Meta["metadata"].(type) {
+	case map[string]string:
+		cfg.Metadata = v
+	case map[string]any: // spec decodificado de JSON
+		cfg.Metadata = make(map[string]string, len(v))
+		for k, value := range v {
+			if s, ok := value.(string); ok {
+				cfg.Metadata[k] = s
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// IdempotencyKey chave estável de uma tentativa de cobrança: mesma sessão, nó,
+// valor, moeda e tentativa geram sempre a mesma chave
+func IdempotencyKey(sessionID, nodeID string, cfg Config, attempt int) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s|%s|%d|%s|%d", sessionID, nodeID, cfg.Amount, cfg.Currency, attempt))
+	return hex.EncodeToString(sum[:16])
+}
+
+// metaInt lê inteiro do meta (int no spec gerado, float64 quando decodificado de JSON)
+func metaInt(meta map[string]any, key string) int {
+	switch v := meta[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,179 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/payment"
+	"github.com/AgendoCerto/lib-bot/runtime"
+)
+
+type resumed struct{ session, node, output string }
+
+func setup(t *testing.T, now *time.Time) (*payment.FakeProvider, *payment.Tracker, *[]resumed, payment.Config) {
+	t.Helper()
+	clock := func() time.Time { return *now }
+	provider := payment.NewFakeProvider()
+	provider.SetClock(clock)
+
+	var calls []resumed
+	tracker := payment.NewTracker(provider, nil).WithClock(clock).WithResumer(payment.ResumerFunc(
+		func(_ context.Context, session, node, output string) error {
+			calls = append(calls, resumed{session, node, output})
+			return nil
+		}))
+
+	c, err := component.NewPaymentLinkFactory(nil).New("payment", map[string]any{"amount": "49,90", "expires_in_min": float64(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, _ := c.Spec(context.Background(), runtime.Context{})
+	cfg, err := payment.ConfigFromSpec(spec)
+	if err != nil || cfg.Amount != 4990 || cfg.ExpiresIn != 10*time.Minute {
+		t.Fatalf("config = %+v, %v", cfg, err)
+	}
+	return provider, tracker, &calls, cfg
+}
+
+func TestReentryDoesNotChargeTwice(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	provider, tracker, calls, cfg := setup(t, &now)
+
+	first, err := tracker.Start(ctx, "s1", "payment", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := tracker.Start(ctx, "s1", "payment", cfg)
+	if again.Link.ID != first.Link.ID || provider.Created() != 1 {
+		t.Fatalf("reentry created a new link: %s vs %s (%d created)", again.Link.ID, first.Link.ID, provider.Created())
+	}
+
+	body, _ := provider.Settle(first.Link.ID, payment.StatusPaid)
+	paid, err := tracker.HandleWebhook(ctx, nil, body)
+	if err != nil || paid.Output != payment.OutputPaid {
+		t.Fatalf("webhook = %+v, %v", paid, err)
+	}
+	_, _ = tracker.HandleWebhook(ctx, nil, body) // Reenvio do provedor
+	if len(*calls) != 1 || (*calls)[0] != (resumed{"s1", "payment", "paid"}) {
+		t.Fatalf("resumes = %+v", *calls)
+	}
+
+	afterPaid, _ := tracker.Start(ctx, "s1", "payment", cfg)
+	if afterPaid.Status != payment.StatusPaid || provider.Created() != 1 {
+		t.Errorf("reentry after payment = %+v (%d created)", afterPaid, provider.Created())
+	}
+
+	// Voltar ao nó com outro total é uma nova cobrança, não o pagamento anterior
+	changed := cfg
+	changed.Amount = 7990
+	again, err = tracker.Start(ctx, "s1", "payment", changed)
+	if err != nil || again.Status != payment.StatusPending || again.Attempt != 2 || again.Link.Amount != 7990 || provider.Created() != 2 {
+		t.Errorf("reentry with a new total = %+v, %v (%d created)", again, err, provider.Created())
+	}
+}
+
+func TestExpiryAndRetry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	provider, tracker, calls, cfg := setup(t, &now)
+
+	first, _ := tracker.Start(ctx, "s1", "payment", cfg)
+	now = now.Add(11 * time.Minute)
+	expired, err := tracker.Reconcile(ctx, first.Link.ID)
+	if err != nil || expired.Output != payment.OutputExpired || len(*calls) != 1 {
+		t.Fatalf("reconcile = %+v, %v (resumes %d)", expired, err, len(*calls))
+	}
+
+	// Evento tardio não muda a saída já definida
+	late, _ := provider.Settle(first.Link.ID, payment.StatusPaid)
+	if p, _ := tracker.HandleWebhook(ctx, nil, late); p.Output != payment.OutputExpired {
+		t.Errorf("late event changed output to %q", p.Output)
+	}
+
+	retry, _ := tracker.Start(ctx, "s1", "payment", cfg)
+	if retry.Attempt != 2 || retry.Link.ID == first.Link.ID || provider.Created() != 2 {
+		t.Errorf("retry = %+v (%d created)", retry, provider.Created())
+	}
+
+	body, _ := provider.Settle(retry.Link.ID, payment.StatusCancelled)
+	if p, _ := tracker.HandleWebhook(ctx, nil, body); p.Output != payment.OutputCancelled {
+		t.Errorf("cancelled output = %q", p.Output)
+	}
+}
+
+func TestChangedChargeCancelsPendingLink(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	provider, tracker, calls, cfg := setup(t, &now)
+
+	first, err := tracker.Start(ctx, "s1", "payment", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O carrinho mudou: o link antigo é cancelado antes da nova cobrança
+	changed := cfg
+	changed.Amount = 5990
+	second, err := tracker.Start(ctx, "s1", "payment", changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Link.ID == first.Link.ID || second.Attempt != 2 || second.Link.Amount != 5990 || provider.Created() != 2 {
+		t.Fatalf("second = %+v (%d created)", second, provider.Created())
+	}
+	if status, _ := provider.Status(ctx, first.Link.ID); status != payment.StatusCancelled {
+		t.Errorf("old link status = %s", status)
+	}
+	old, err := tracker.Reconcile(ctx, first.Link.ID)
+	if err != nil || old.Status != payment.StatusCancelled || len(*calls) != 0 {
+		t.Errorf("old payment = %+v, %v (resumes %+v)", old, err, *calls)
+	}
+
+	// Pagamento tardio do link antigo não muda a cobrança corrente
+	if body, err := provider.Settle(first.Link.ID, payment.StatusPaid); err == nil {
+		if p, _ := tracker.HandleWebhook(ctx, nil, body); p.Output != payment.OutputCancelled {
+			t.Errorf("late payment on cancelled link = %+v", p)
+		}
+	}
+	if again, _ := tracker.Start(ctx, "s1", "payment", changed); again.Link.ID != second.Link.ID {
+		t.Errorf("reentry with the new amount = %+v", again)
+	}
+
+	// Moeda diferente também é outra cobrança
+	changed.Currency = "USD"
+	third, err := tracker.Start(ctx, "s1", "payment", changed)
+	if err != nil || third.Attempt != 3 || third.Link.Currency != "USD" {
+		t.Errorf("currency change = %+v, %v", third, err)
+	}
+}
+
+// providerWithoutCancel provedor que não expõe CancelLink
+type providerWithoutCancel struct{ payment.PaymentProvider }
+
+func TestChangedChargeWithoutCancelRefuses(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	provider, _, _, cfg := setup(t, &now)
+	tracker := payment.NewTracker(providerWithoutCancel{provider}, nil).WithClock(func() time.Time { return now })
+
+	first, err := tracker.Start(ctx, "s1", "payment", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := cfg
+	changed.Amount = 100
+	p, err := tracker.Start(ctx, "s1", "payment", changed)
+	if !errors.Is(err, payment.ErrChargeChanged) || p.Link.ID != first.Link.ID || provider.Created() != 1 {
+		t.Errorf("Start = %+v, %v (%d created)", p, err, provider.Created())
+	}
+
+	// Depois que o link antigo termina, a nova cobrança segue normalmente
+	_, _ = provider.Settle(first.Link.ID, payment.StatusExpired)
+	if p, err := tracker.Start(ctx, "s1", "payment", changed); err != nil || p.Attempt != 2 || p.Link.Amount != 100 {
+		t.Errorf("Start after expiry = %+v, %v", p, err)
+	}
+}
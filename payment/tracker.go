@@ -0,0 +1,266 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Payment cobrança de uma sessão num nó payment_link
+type Payment struct {
+	SessionID string    `json:"session_id"`
+	NodeID    string    `json:"node_id"`
+	Attempt   int       `json:"attempt"` // Incrementa quando a tentativa anterior terminou sem pagamento
+	Link      Link      `json:"link"`
+	Status    Status    `json:"status"`
+	Output    string    `json:"output,omitempty"`  // Saída do componente (status terminal)
+	Resumed   bool      `json:"resumed,omitempty"` // Sessão já retomada com Output
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store armazenamento das cobranças acompanhadas
+type Store interface {
+	Save(ctx context.Context, p Payment) error
+	ByLink(ctx context.Context, linkID string) (Payment, bool, error)
+	Latest(ctx context.Context, sessionID, nodeID string) (Payment, bool, error)
+}
+
+// Resumer retoma a sessão que aguarda o pagamento seguindo a saída do nó
+type Resumer interface {
+	Resume(ctx context.Context, sessionID, nodeID, output string) error
+}
+
+// ResumerFunc adapta uma função a Resumer
+type ResumerFunc func(ctx context.Context, sessionID, nodeID, output string) error
+
+func (f ResumerFunc) Resume(ctx context.Context, sessionID, nodeID, output string) error {
+	return f(ctx, sessionID, nodeID, output)
+}
+
+// Tracker acompanha o ciclo de vida dos links: cria (idempotente por sessão e nó),
+// concilia webhooks/consultas com as saídas do componente e retoma a sessão
+type Tracker struct {
+	provider PaymentProvider
+	store    Store
+	resumer  Resumer
+	now      Clock
+}
+
+// NewTracker cria o rastreador; store nil usa MemoryStore
+func NewTracker(provider PaymentProvider, store Store) *Tracker {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Tracker{provider: provider, store: store, now: time.Now}
+}
+
+// WithResumer define quem retoma a sessão quando o pagamento termina
+func (t *Tracker) WithResumer(resumer Resumer) *Tracker {
+	cp := *t
+	cp.resumer = resumer
+	return &cp
+}
+
+// WithClock define o relógio (expiração dos links)
+func (t *Tracker) WithClock(now Clock) *Tracker {
+	cp := *t
+	cp.now = now
+	return &cp
+}
+
+// Start cria o link da sessão no nó ou, na reentrada, devolve a cobrança em
+// andamento (link pendente e válido) ou já paga com o mesmo valor e moeda, sem nova
+// cobrança; tentativas terminadas sem pagamento (expired/failed/cancelled) ou pagas
+// com outro valor ou moeda geram um novo link
+// Se o valor ou a moeda mudou com um link pendente, o link anterior é cancelado no
+// provedor antes da nova cobrança; sem LinkCanceller, retorna ErrChargeChanged
+func (t *Tracker) Start(ctx context.Context, sessionID, nodeID string, cfg Config) (Payment, error) {
+	attempt := 1
+	latest, ok, err := t.store.Latest(ctx, sessionID, nodeID)
+	if err != nil {
+		return Payment{}, err
+	}
+	if ok {
+		if latest.Status == StatusPending {
+			// A sessão está no nó: quem chamou segue a saída, sem retomada
+			status, err := t.poll(ctx, latest)
+			if err != nil {
+				return latest, err
+			}
+			if status.Terminal() {
+				latest.Resumed = true
+				if latest, err = t.settle(ctx, latest, status); err != nil {
+					return latest, err
+				}
+			}
+		}
+		sameCharge := latest.Link.Amount == cfg.Amount && latest.Link.Currency == cfg.Currency
+		if (latest.Status == StatusPaid || latest.Status == StatusPending) && sameCharge {
+			return latest, nil
+		}
+		if latest.Status == StatusPending {
+			if latest, err = t.cancel(ctx, latest); err != nil {
+				return latest, err
+			}
+		}
+		attempt = latest.Attempt + 1
+	}
+
+	now := t.now()
+	link, err := t.provider.CreateLink(ctx, CreateRequest{
+		Amount:         cfg.Amount,
+		Currency:       cfg.Currency,
+		ExpiresAt:      now.Add(cfg.ExpiresIn),
+		IdempotencyKey: IdempotencyKey(sessionID, nodeID, cfg, attempt),
+		Metadata:       cfg.Metadata,
+	})
+	if err != nil {
+		return Payment{}, fmt.Errorf("create payment link: %w", err)
+	}
+
+	p := Payment{SessionID: sessionID, NodeID: nodeID, Attempt: attempt, Link: link, Status: StatusPending, CreatedAt: now, UpdatedAt: now}
+	if link.Status.Terminal() { // Provedor devolveu um link já concluído para a chave
+		p.Status, p.Output, p.Resumed = link.Status, OutputFor(link.Status), true
+	}
+	return p, t.store.Save(ctx, p)
+}
+
+// HandleWebhook interpreta o webhook do provedor e aplica o evento
+func (t *Tracker) HandleWebhook(ctx context.Context, header http.Header, body []byte) (Payment, error) {
+	event, err := t.provider.ParseWebhook(ctx, header, body)
+	if err != nil {
+		return Payment{}, err
+	}
+	return t.Handle(ctx, event)
+}
+
+// Handle aplica o evento: o primeiro status terminal define a saída e retoma a
+// sessão; eventos repetidos ou tardios são ignorados (a retomada que falhou é refeita)
+func (t *Tracker) Handle(ctx context.Context, event Event) (Payment, error) {
+	p, ok, err := t.store.ByLink(ctx, event.LinkID)
+	if err != nil {
+		return Payment{}, err
+	}
+	if !ok {
+		return Payment{}, fmt.Errorf("%w: %s", ErrLinkNotFound, event.LinkID)
+	}
+
+	if !p.Status.Terminal() {
+		if !event.Status.Terminal() {
+			return p, nil
+		}
+		if p, err = t.settle(ctx, p, event.Status); err != nil {
+			return p, err
+		}
+	}
+	return t.resume(ctx, p)
+}
+
+// Reconcile consulta o status no provedor (para quando o webhook não chega);
+// links pendentes vencidos são dados como expirados
+func (t *Tracker) Reconcile(ctx context.Context, linkID string) (Payment, error) {
+	p, ok, err := t.store.ByLink(ctx, linkID)
+	if err != nil {
+		return Payment{}, err
+	}
+	if !ok {
+		return Payment{}, fmt.Errorf("%w: %s", ErrLinkNotFound, linkID)
+	}
+	if p.Status.Terminal() {
+		return t.resume(ctx, p)
+	}
+
+	status, err := t.poll(ctx, p)
+	if err != nil {
+		return p, err
+	}
+	return t.Handle(ctx, Event{LinkID: linkID, Status: status, At: t.now()})
+}
+
+// poll status do link no provedor; pendente após o vencimento conta como expirado
+func (t *Tracker) poll(ctx context.Context, p Payment) (Status, error) {
+	status, err := t.provider.Status(ctx, p.Link.ID)
+	if err != nil {
+		return "", fmt.Errorf("payment status %s: %w", p.Link.ID, err)
+	}
+	if status == StatusPending && !p.Link.ExpiresAt.IsZero() && !t.now().Before(p.Link.ExpiresAt) {
+		status = StatusExpired
+	}
+	return status, nil
+}
+
+// cancel cancela no provedor o link pendente substituído por outra cobrança
+// A sessão continua no nó, então a tentativa cancelada não é retomada
+func (t *Tracker) cancel(ctx context.Context, p Payment) (Payment, error) {
+	canceller, ok := t.provider.(LinkCanceller)
+	if !ok {
+		return p, fmt.Errorf("%w: link %s is still payable", ErrChargeChanged, p.Link.ID)
+	}
+	if err := canceller.CancelLink(ctx, p.Link.ID); err != nil {
+		return p, fmt.Errorf("cancel payment link %s: %w", p.Link.ID, err)
+	}
+	p.Resumed = true
+	return t.settle(ctx, p, StatusCancelled)
+}
+
+// settle registra o status terminal e a saída correspondente
+func (t *Tracker) settle(ctx context.Context, p Payment, status Status) (Payment, error) {
+	p.Status, p.Output, p.Link.Status = status, OutputFor(status), status
+	p.UpdatedAt = t.now()
+	return p, t.store.Save(ctx, p)
+}
+
+// resume retoma a sessão uma única vez por cobrança terminada
+func (t *Tracker) resume(ctx context.Context, p Payment) (Payment, error) {
+	if !p.Status.Terminal() || p.Resumed || t.resumer == nil {
+		return p, nil
+	}
+	if err := t.resumer.Resume(ctx, p.SessionID, p.NodeID, p.Output); err != nil {
+		return p, fmt.Errorf("resume session %s: %w", p.SessionID, err)
+	}
+	p.Resumed = true
+	return p, t.store.Save(ctx, p)
+}
+
+// MemoryStore Store em memória
+type MemoryStore struct {
+	mu       sync.Mutex
+	payments map[string]Payment // link → cobrança
+	latest   map[string]string  // sessão|nó → link da última tentativa
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore cria o armazenamento em memória
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{payments: map[string]Payment{}, latest: map[string]string{}}
+}
+
+func (m *MemoryStore) Save(_ context.Context, p Payment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.payments[p.Link.ID] = p
+	key := p.SessionID + "|" + p.NodeID
+	if current, ok := m.payments[m.latest[key]]; !ok || p.Attempt >= current.Attempt {
+		m.latest[key] = p.Link.ID
+	}
+	return nil
+}
+
+func (m *MemoryStore) ByLink(_ context.Context, linkID string) (Payment, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.payments[linkID]
+	return p, ok, nil
+}
+
+func (m *MemoryStore) Latest(_ context.Context, sessionID, nodeID string) (Payment, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.payments[m.latest[sessionID+"|"+nodeID]]
+	return p, ok, nil
+}
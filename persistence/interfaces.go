@@ -3,9 +3,14 @@ package persistence
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrKeyNotFound is returned by KeyReader implementations when the key has no value.
+var ErrKeyNotFound = errors.New("persistence: key not found")
+
 // KeyReader provides read access to persistence keys.
+// Missing keys yield ErrKeyNotFound (or an empty value with a nil error).
 type KeyReader interface {
 	Get(ctx context.Context, scope Scope, key string) (string, error)
 }
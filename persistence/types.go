@@ -1,6 +1,12 @@
 // Package persistence provides types for data persistence configuration.
 package persistence
 
+import (
+	"encoding/json"
+
+	"github.com/AgendoCerto/lib-bot/sanitize"
+)
+
 // Scope defines where information will be persisted.
 type Scope string
 
@@ -10,56 +16,104 @@ const (
 	ScopeGlobal  Scope = "global"  // Global shared data (bot-scoped, shared across all users)
 )
 
-// SanitizationType defines predefined sanitization types.
-type SanitizationType string
+// SanitizationType defines predefined sanitization types (alias of sanitize.Type).
+type SanitizationType = sanitize.Type
 
+// Sanitization types, kept here for compatibility with existing configurations.
 const (
 	// Number extractors.
-	SanitizeNumbersOnly  SanitizationType = "numbers_only" // Extract numbers only
-	SanitizeLettersOnly  SanitizationType = "letters_only" // Extract letters only
-	SanitizeAlphanumeric SanitizationType = "alphanumeric" // Extract letters and numbers
+	SanitizeNumbersOnly  = sanitize.NumbersOnly
+	SanitizeLettersOnly  = sanitize.LettersOnly
+	SanitizeAlphanumeric = sanitize.Alphanumeric
 
 	// Specific formatters.
-	SanitizeCPF   SanitizationType = "cpf"   // Extract and format CPF
-	SanitizeCEP   SanitizationType = "cep"   // Extract and format CEP
-	SanitizePhone SanitizationType = "phone" // Extract and format Brazilian phone
+	SanitizeCPF   = sanitize.CPF
+	SanitizeCNPJ  = sanitize.CNPJ
+	SanitizeRG    = sanitize.RG
+	SanitizePIS   = sanitize.PIS
+	SanitizeCEP   = sanitize.CEP
+	SanitizePhone = sanitize.Phone
+
+	// International phone.
+	SanitizePhoneE164 = sanitize.PhoneE164
 
 	// Monetary.
-	SanitizeBRL SanitizationType = "monetary_brl" // Extract and format monetary value in reais
+	SanitizeBRL = sanitize.BRL
 
 	// Text normalizers.
-	SanitizeNameCase   SanitizationType = "name_case"   // Convert to proper name format
-	SanitizeUpperCase  SanitizationType = "uppercase"   // Convert to uppercase
-	SanitizeLowerCase  SanitizationType = "lowercase"   // Convert to lowercase
-	SanitizeTrimSpaces SanitizationType = "trim_spaces" // Remove extra spaces
+	SanitizeNameCase   = sanitize.NameCase
+	SanitizeUpperCase  = sanitize.UpperCase
+	SanitizeLowerCase  = sanitize.LowerCase
+	SanitizeTrimSpaces = sanitize.TrimSpaces
 
 	// Email (simple validation).
-	SanitizeEmail SanitizationType = "email" // Validate email format
+	SanitizeEmail = sanitize.Email
 
 	// Date with timezone.
-	SanitizeDateTimezone SanitizationType = "get_date_timezone" // Extract date/time with configurable timezone
+	SanitizeDateTimezone = sanitize.DateTimezone
 
 	// Custom regex.
-	SanitizeCustom SanitizationType = "custom" // Custom regex
+	SanitizeCustom = sanitize.Custom
 )
 
-// SanitizationConfig configures input data sanitization.
-type SanitizationConfig struct {
-	Type        SanitizationType `json:"type"`                  // Sanitization type
-	CustomRegex string           `json:"customRegex,omitempty"` // Custom regex (when type=custom)
-	Replacement string           `json:"replacement,omitempty"` // Replacement string
-	Description string           `json:"description,omitempty"` // Sanitization description
-	StrictMode  bool             `json:"strictMode,omitempty"`  // If true, fail if cannot sanitize
-}
+// SanitizationConfig configures input data sanitization (alias of sanitize.Config).
+type SanitizationConfig = sanitize.Config
+
+// SanitizationStep traces one step of a sanitization pipeline (alias of sanitize.StepTrace).
+type SanitizationStep = sanitize.StepTrace
 
 // Config configures data persistence for a match.
 type Config struct {
-	Enabled      bool                `json:"enabled"`                // If persistence is enabled
-	Scope        Scope               `json:"scope"`                  // Where to persist: context, state, or global
-	Key          string              `json:"key"`                    // Storage key (e.g., "phone_number")
-	Sanitization *SanitizationConfig `json:"sanitization,omitempty"` // Sanitization configuration
-	Required     bool                `json:"required,omitempty"`     // If field is required
-	DefaultValue string              `json:"defaultValue,omitempty"` // Default value if empty
+	Enabled      bool                 `json:"enabled"`                        // If persistence is enabled
+	Scope        Scope                `json:"scope"`                          // Where to persist: context, state, or global
+	Key          string               `json:"key"`                            // Storage key (e.g., "phone_number")
+	Sanitization *SanitizationConfig  `json:"sanitization,omitempty"`         // Single sanitization step
+	Pipeline     []SanitizationConfig `json:"sanitizationPipeline,omitempty"` // Ordered sanitization steps, applied after Sanitization
+	Required     bool                 `json:"required,omitempty"`             // If field is required
+	DefaultValue string               `json:"defaultValue,omitempty"`         // Default value if empty
+}
+
+// SanitizationSteps returns the sanitization chain in execution order:
+// the single Sanitization step (if any) followed by the Pipeline steps.
+func (c Config) SanitizationSteps() []SanitizationConfig {
+	steps := make([]SanitizationConfig, 0, len(c.Pipeline)+1)
+	if c.Sanitization != nil {
+		steps = append(steps, *c.Sanitization)
+	}
+	return append(steps, c.Pipeline...)
+}
+
+// UnmarshalJSON accepts "sanitization" either as a single object or as an
+// array of steps; the array form is stored in Pipeline.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plain Config
+	var raw struct {
+		plain
+		Sanitization json.RawMessage `json:"sanitization,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = Config(raw.plain)
+	if len(raw.Sanitization) == 0 || string(raw.Sanitization) == "null" {
+		return nil
+	}
+	if raw.Sanitization[0] == '[' {
+		var steps []SanitizationConfig
+		if err := json.Unmarshal(raw.Sanitization, &steps); err != nil {
+			return err
+		}
+		c.Pipeline = append(steps, c.Pipeline...)
+		return nil
+	}
+
+	var single SanitizationConfig
+	if err := json.Unmarshal(raw.Sanitization, &single); err != nil {
+		return err
+	}
+	c.Sanitization = &single
+	return nil
 }
 
 // MatchConfig extends match configuration with persistence.
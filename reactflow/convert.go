@@ -9,6 +9,7 @@ import (
 	"github.com/AgendoCerto/lib-bot/flow"
 	"github.com/AgendoCerto/lib-bot/io"
 	"github.com/AgendoCerto/lib-bot/layout"
+	"github.com/AgendoCerto/lib-bot/persistence"
 )
 
 // isFallbackEdge detecta se uma edge é de fallback/retry/timeout
@@ -221,14 +222,8 @@ func DesignToReactFlowWithDirection(d io.DesignDoc, direction layout.Direction)
 							"scope":   string(persistenceConfig.Scope),
 							"key":     persistenceConfig.Key,
 						}
-						if persistenceConfig.Sanitization != nil {
-							persistenceData["sanitization"] = map[string]any{
-								"type":         string(persistenceConfig.Sanitization.Type),
-								"custom_regex": persistenceConfig.Sanitization.CustomRegex,
-								"replacement":  persistenceConfig.Sanitization.Replacement,
-								"description":  persistenceConfig.Sanitization.Description,
-								"strict_mode":  persistenceConfig.Sanitization.StrictMode,
-							}
+						if sanitization := sanitizationData(persistenceConfig); sanitization != nil {
+							persistenceData["sanitization"] = sanitization
 						}
 						if persistenceConfig.Required {
 							persistenceData["required"] = persistenceConfig.Required
@@ -256,14 +251,8 @@ func DesignToReactFlowWithDirection(d io.DesignDoc, direction layout.Direction)
 					"scope":   string(persistenceConfig.Scope),
 					"key":     persistenceConfig.Key,
 				}
-				if persistenceConfig.Sanitization != nil {
-					persistenceData["sanitization"] = map[string]any{
-						"type":         string(persistenceConfig.Sanitization.Type),
-						"custom_regex": persistenceConfig.Sanitization.CustomRegex,
-						"replacement":  persistenceConfig.Sanitization.Replacement,
-						"description":  persistenceConfig.Sanitization.Description,
-						"strict_mode":  persistenceConfig.Sanitization.StrictMode,
-					}
+				if sanitization := sanitizationData(persistenceConfig); sanitization != nil {
+					persistenceData["sanitization"] = sanitization
 				}
 				if persistenceConfig.Required {
 					persistenceData["required"] = persistenceConfig.Required
@@ -308,6 +297,13 @@ func DesignToReactFlowWithDirection(d io.DesignDoc, direction layout.Direction)
 		}
 
 		nodes = append(nodes, node)
+
+		// Subflows locais são renderizados como grupo colapsável com os nós internos como filhos
+		if n.Kind == "subflow" {
+			groupChildren, groupEdges := subflowGroup(d, n, &nodes[len(nodes)-1])
+			nodes = append(nodes, groupChildren...)
+			edges = append(edges, groupEdges...)
+		}
 	}
 
 	// Adiciona edges regulares às edges existentes (que já podem incluir edges de início)
@@ -403,6 +399,11 @@ func ReactFlowToDesign(nodes []Node, edges []Edge, base io.DesignDoc) io.DesignD
 	// Nodes - converte nós do React Flow de volta para estrutura interna
 	out.Graph.Nodes = make([]flow.Node, 0, len(nodes))
 	for _, n := range nodes {
+		// Filhos de grupos de subflow pertencem à definição do subflow, não ao grafo principal
+		if isSubflowChild(n) {
+			continue
+		}
+
 		kind := n.Type
 		if k, ok := n.Data["kind"].(string); ok && k != "" {
 			kind = k
@@ -466,6 +467,10 @@ func ReactFlowToDesign(nodes []Node, edges []Edge, base io.DesignDoc) io.DesignD
 	// Edges
 	out.Graph.Edges = make([]flow.Edge, 0, len(edges))
 	for _, e := range edges {
+		if internal, _ := e.Data["subflow_internal"].(bool); internal {
+			continue
+		}
+
 		label, _ := e.Data["label"].(string)
 		guard, _ := e.Data["guard"].(string)
 		priority := 0
@@ -574,3 +579,32 @@ func CreateReactFlowDocument(nodes []Node, edges []Edge, layoutInfo *LayoutInfo)
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+// sanitizationData serializa a sanitização no formato das props: objeto único
+// quando há uma etapa só, lista ordenada quando há pipeline
+func sanitizationData(config *persistence.Config) any {
+	steps := config.SanitizationSteps()
+	data := make([]any, 0, len(steps))
+	for _, step := range steps {
+		stepData := map[string]any{
+			"type":         string(step.Type),
+			"custom_regex": step.CustomRegex,
+			"replacement":  step.Replacement,
+			"description":  step.Description,
+			"strict_mode":  step.StrictMode,
+		}
+		if step.Region != "" {
+			stepData["region"] = step.Region
+		}
+		data = append(data, stepData)
+	}
+
+	switch {
+	case len(data) == 0:
+		return nil
+	case len(data) == 1 && len(config.Pipeline) == 0:
+		return data[0]
+	default:
+		return data
+	}
+}
@@ -0,0 +1,107 @@
+package reactflow
+
+import (
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// Dimensões usadas para posicionar filhos sem coordenadas dentro do grupo
+const (
+	subflowChildSpacingY = 120.0
+	subflowChildPadding  = 40.0
+)
+
+// subflowGroup converte o grafo de um subflow local em nós filhos do grupo (colapsado por padrão)
+// O nó "group" é anotado com os metadados do subflow para o editor expandir/colapsar
+func subflowGroup(d io.DesignDoc, n flow.Node, group *Node) ([]Node, []Edge) {
+	props := d.ResolveProps(n)
+	ref, _ := props["ref"].(string)
+
+	group.Data["group"] = true
+	group.Data["collapsible"] = true
+	group.Data["collapsed"] = true
+
+	sf, ok := d.Subflows[ref]
+	if !ok {
+		// Subflow externo (bot_id) ou referência inválida: grupo sem filhos
+		group.Data["subflow"] = map[string]any{"ref": ref, "external": ref == ""}
+		return nil, nil
+	}
+
+	group.Data["subflow"] = map[string]any{
+		"ref":     ref,
+		"title":   sf.Title,
+		"entry":   string(sf.Entry),
+		"inputs":  sf.Inputs,
+		"outputs": sf.Outputs,
+	}
+
+	prefix := string(n.ID) + "/"
+	children := make([]Node, 0, len(sf.Graph.Nodes))
+	for i, inner := range sf.Graph.Nodes {
+		data := map[string]any{
+			"kind":          inner.Kind,
+			"final":         inner.Final,
+			"subflow_child": true,
+			"subflow_ref":   ref,
+		}
+		if inner.Title != "" {
+			data["title"] = inner.Title
+		}
+		if innerProps := sf.ResolveProps(inner); len(innerProps) > 0 {
+			data["props"] = innerProps
+		}
+
+		// Posição relativa ao grupo (React Flow usa coordenadas relativas para filhos)
+		pos := Position{X: subflowChildPadding, Y: subflowChildPadding + float64(i)*subflowChildSpacingY}
+		if inner.X != nil && inner.Y != nil {
+			pos = Position{X: *inner.X, Y: *inner.Y}
+		}
+
+		children = append(children, Node{
+			ID:         prefix + string(inner.ID),
+			Type:       inner.Kind,
+			Data:       data,
+			Position:   pos,
+			ParentID:   string(n.ID),
+			Extent:     "parent",
+			Hidden:     boolPtr(true), // Grupo começa colapsado
+			Draggable:  boolPtr(true),
+			Selectable: boolPtr(true),
+			Deletable:  boolPtr(false), // Edição do subflow é feita na definição
+		})
+	}
+
+	edges := make([]Edge, 0, len(sf.Graph.Edges))
+	for _, e := range sf.Graph.Edges {
+		// Saídas "@output" conectam ao próprio grupo (handles de saída do nó subflow)
+		target := prefix + string(e.To)
+		if output, ok := strings.CutPrefix(string(e.To), io.SubflowExitPrefix); ok {
+			target = string(n.ID)
+			e.Label = output
+		}
+
+		edges = append(edges, Edge{
+			ID:         "__subflow_" + prefix + string(e.From) + "->" + string(e.To),
+			Source:     prefix + string(e.From),
+			Target:     target,
+			Type:       "default",
+			Label:      e.Label,
+			MarkerEnd:  "arrowclosed",
+			Data:       map[string]any{"label": e.Label, "subflow_internal": true},
+			Hidden:     boolPtr(true),
+			Deletable:  boolPtr(false),
+			Selectable: boolPtr(true),
+		})
+	}
+
+	return children, edges
+}
+
+// isSubflowChild indica se o nó React Flow é filho de um grupo de subflow
+func isSubflowChild(n Node) bool {
+	child, _ := n.Data["subflow_child"].(bool)
+	return child && n.ParentID != ""
+}
@@ -17,6 +17,8 @@ type Node struct {
 	Selectable *bool          `json:"selectable,omitempty"` // Se o nó pode ser selecionado
 	Deletable  *bool          `json:"deletable,omitempty"`  // Se o nó pode ser deletado
 	Hidden     *bool          `json:"hidden,omitempty"`     // Se o nó deve ser ocultado
+	ParentID   string         `json:"parentId,omitempty"`   // Nó pai (grupo) - usado pelos filhos de subflows
+	Extent     string         `json:"extent,omitempty"`     // "parent" restringe o filho à área do grupo
 	// Campos para handles de conexão
 	SourcePosition string `json:"sourcePosition,omitempty"` // Posição do handle de saída
 	TargetPosition string `json:"targetPosition,omitempty"` // Posição do handle de entrada
@@ -6,6 +6,7 @@ type Context struct {
 	Context map[string]any // Variáveis de contexto da sessão (temporárias)
 	State   map[string]any // Variáveis do state (persistentes - usuário)
 	Global  map[string]any // Variáveis globais (persistentes - bot)
+	Locale  string         // Locale da sessão (ex: "pt-BR") - resolve textos traduzidos e idioma de HSM
 }
 
 // LiquidScope retorna o escopo completo para renderização de templates Liquid
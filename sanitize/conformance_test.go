@@ -0,0 +1,310 @@
+package sanitize_test
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/persistence"
+	"github.com/AgendoCerto/lib-bot/sanitize"
+)
+
+// entryPoint is one way callers reach the sanitization engine.
+type entryPoint struct {
+	name     string
+	sanitize func(input string, config sanitize.Config) (string, error)
+}
+
+func entryPoints() []entryPoint {
+	return []entryPoint{
+		{"sanitize.Service", sanitize.NewService().Sanitize},
+		{"sanitize.Default", sanitize.Default().Sanitize},
+		{"persistence.DefaultSanitizer", persistence.DefaultSanitizer{}.Sanitize},
+	}
+}
+
+// conformanceCase is an input and its expected outcome for one sanitization type.
+type conformanceCase struct {
+	config  sanitize.Config
+	input   string
+	want    string         // Expected output (ignored when match is set)
+	match   *regexp.Regexp // Expected output pattern, for time-dependent types
+	wantErr error          // Expected error (errors.Is)
+}
+
+// conformanceCases lists at least one case per built-in type.
+func conformanceCases() []conformanceCase {
+	return []conformanceCase{
+		{config: sanitize.Config{Type: sanitize.NumbersOnly}, input: "tel: (11) 9876-5432", want: "1198765432"},
+		{config: sanitize.Config{Type: sanitize.LettersOnly}, input: "João 123!", want: "João"},
+		{config: sanitize.Config{Type: sanitize.Alphanumeric}, input: "AB-12 c.3", want: "AB12c3"},
+
+		{config: sanitize.Config{Type: sanitize.CPF}, input: "52998224725", want: "529.982.247-25"},
+		{config: sanitize.Config{Type: sanitize.CPF}, input: "123", wantErr: sanitize.ErrInvalidCPFLength},
+		{config: sanitize.Config{Type: sanitize.CPF}, input: "111.111.111-11", wantErr: sanitize.ErrInvalidCPFChecksum},
+		{config: sanitize.Config{Type: sanitize.CPF, StrictMode: true}, input: "111.111.111-11", wantErr: sanitize.ErrInvalidCPFChecksum},
+		{config: sanitize.Config{Type: sanitize.CNPJ}, input: "11222333000181", want: "11.222.333/0001-81"},
+		{config: sanitize.Config{Type: sanitize.CNPJ, StrictMode: true}, input: "12.abc.345/01de-35", want: "12.ABC.345/01DE-35"},
+		{config: sanitize.Config{Type: sanitize.CNPJ, StrictMode: true}, input: "11222333000182", wantErr: sanitize.ErrInvalidCNPJChecksum},
+		{config: sanitize.Config{Type: sanitize.RG}, input: "12345678x", want: "12.345.678-X"},
+		{config: sanitize.Config{Type: sanitize.PIS, StrictMode: true}, input: "12054321771", want: "120.54321.77-1"},
+		{config: sanitize.Config{Type: sanitize.PIS, StrictMode: true}, input: "12054321772", wantErr: sanitize.ErrInvalidPISChecksum},
+		{config: sanitize.Config{Type: sanitize.CEP}, input: "01310100", want: "01310-100"},
+		{config: sanitize.Config{Type: sanitize.CEP}, input: "0131", wantErr: sanitize.ErrInvalidCEPLength},
+		{config: sanitize.Config{Type: sanitize.Phone}, input: "+55 11 98765-4321", want: "(11) 98765-4321"},
+		{config: sanitize.Config{Type: sanitize.Phone}, input: "1133334444", want: "(11) 3333-4444"},
+		{config: sanitize.Config{Type: sanitize.Phone}, input: "12345", wantErr: sanitize.ErrInvalidPhoneLength},
+		{config: sanitize.Config{Type: sanitize.PhoneE164}, input: "(11) 8765-4321", want: "+5511987654321"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164}, input: "5511987654321", want: "+5511987654321"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164}, input: "(11) 3333-4444", want: "+551133334444"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164}, input: "+351 912 345 678", want: "+351912345678"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164, Region: "AR"}, input: "011 15 2345-6789", want: "+5491123456789"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164}, input: "+54 9 11 2345-6789", want: "+5491123456789"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164, Region: "US"}, input: "(415) 555-2671", want: "+14155552671"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164}, input: "+44 20 7946 0958", want: "+442079460958"},
+		{config: sanitize.Config{Type: sanitize.PhoneE164}, input: "123", wantErr: sanitize.ErrInvalidPhoneLength},
+		{config: sanitize.Config{Type: sanitize.PhoneE164, Region: "XX"}, input: "123", wantErr: sanitize.ErrUnknownPhoneRegion},
+
+		{config: sanitize.Config{Type: sanitize.BRL}, input: "R$ 1.234,56", want: "R$ 1234.56"},
+		{config: sanitize.Config{Type: sanitize.BRL}, input: "sem valor", wantErr: sanitize.ErrMonetaryNotFound},
+
+		{config: sanitize.Config{Type: sanitize.NameCase}, input: "  maria DA silva  ", want: "Maria da Silva"},
+		{config: sanitize.Config{Type: sanitize.UpperCase}, input: " abc ", want: "ABC"},
+		{config: sanitize.Config{Type: sanitize.LowerCase}, input: " ABC ", want: "abc"},
+		{config: sanitize.Config{Type: sanitize.TrimSpaces}, input: "  a   b  ", want: "a b"},
+
+		{config: sanitize.Config{Type: sanitize.Email}, input: " Ana@Example.COM ", want: "ana@example.com"},
+		{config: sanitize.Config{Type: sanitize.Email}, input: "ana@", wantErr: sanitize.ErrInvalidEmailFormat},
+
+		{config: sanitize.Config{Type: sanitize.DateTimezone, Replacement: "UTC"}, input: "now", match: regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2} UTC$`)},
+		{config: sanitize.Config{Type: sanitize.DateTimezone, Replacement: "Nowhere/City"}, input: "now", wantErr: sanitize.ErrInvalidTimezone},
+
+		{config: sanitize.Config{Type: sanitize.Custom, CustomRegex: `\d+`}, input: "a1b22", want: "122"},
+		{config: sanitize.Config{Type: sanitize.Custom, CustomRegex: `\s+`, Replacement: "-"}, input: "a b  c", want: "a-b-c"},
+		{config: sanitize.Config{Type: sanitize.Custom, CustomRegex: `(`}, input: "x", wantErr: sanitize.ErrInvalidRegex},
+
+		{config: sanitize.Config{Type: "unknown"}, input: "x", wantErr: sanitize.ErrUnsupportedSanitizationType},
+	}
+}
+
+// TestConformanceCoversEveryType ensures new types get a conformance case.
+func TestConformanceCoversEveryType(t *testing.T) {
+	covered := make(map[sanitize.Type]bool)
+	for _, c := range conformanceCases() {
+		covered[c.config.Type] = true
+	}
+	for _, typ := range sanitize.Types() {
+		if !covered[typ] {
+			t.Errorf("sanitization type %q has no conformance case", typ)
+		}
+	}
+}
+
+// TestConformance checks that every entry point produces the same result.
+func TestConformance(t *testing.T) {
+	for _, ep := range entryPoints() {
+		for _, c := range conformanceCases() {
+			t.Run(ep.name+"/"+string(c.config.Type)+"/"+c.input, func(t *testing.T) {
+				got, err := ep.sanitize(c.input, c.config)
+
+				if c.wantErr != nil {
+					if !errors.Is(err, c.wantErr) {
+						t.Fatalf("error = %v, want %v", err, c.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if c.match != nil {
+					if !c.match.MatchString(got) {
+						t.Fatalf("got %q, want match %s", got, c.match)
+					}
+					return
+				}
+				if got != c.want {
+					t.Fatalf("got %q, want %q", got, c.want)
+				}
+			})
+		}
+	}
+}
+
+// TestEmptyInput checks that empty input is returned unchanged by every entry point.
+func TestEmptyInput(t *testing.T) {
+	for _, ep := range entryPoints() {
+		for _, typ := range sanitize.Types() {
+			got, err := ep.sanitize("", sanitize.Config{Type: typ})
+			if got != "" || err != nil {
+				t.Errorf("%s/%s: got (%q, %v), want empty result", ep.name, typ, got, err)
+			}
+		}
+	}
+}
+
+// TestPersistenceErrorsAreShared checks that persistence errors match sanitize errors.
+func TestPersistenceErrorsAreShared(t *testing.T) {
+	_, err := persistence.DefaultSanitizer{}.Sanitize("123", persistence.SanitizationConfig{Type: persistence.SanitizeCPF})
+	if !errors.Is(err, persistence.ErrInvalidCPFLength) || !errors.Is(err, sanitize.ErrInvalidCPFLength) {
+		t.Fatalf("error %v does not match both persistence and sanitize sentinels", err)
+	}
+	if code := sanitize.ErrorCode(err); code != sanitize.CodeInvalidLength {
+		t.Fatalf("error code = %q, want %q", code, sanitize.CodeInvalidLength)
+	}
+}
+
+// TestDocumentCheckDigits checks that invalid check digits are rejected in
+// every mode with the same routable *Error.
+func TestDocumentCheckDigits(t *testing.T) {
+	cases := []struct {
+		typ     sanitize.Type
+		valid   string // Correct check digits (123.456.789-09 is a valid CPF)
+		want    string
+		invalid []string
+		wantErr error
+	}{
+		{sanitize.CPF, "12345678909", "123.456.789-09", []string{"111.111.111-11", "123.456.789-00", "123.456.789-10", "529.982.247-26"}, sanitize.ErrInvalidCPFChecksum},
+		{sanitize.CNPJ, "11222333000181", "11.222.333/0001-81", []string{"11222333000182", "11.111.111/1111-11"}, sanitize.ErrInvalidCNPJChecksum},
+		{sanitize.CNPJ, "12.abc.345/01de-35", "12.ABC.345/01DE-35", []string{"12.ABC.345/01DE-36", "12ABC34501DF35"}, sanitize.ErrInvalidCNPJChecksum},
+		{sanitize.PIS, "12054321771", "120.54321.77-1", []string{"12054321772", "120.5432.177-0"}, sanitize.ErrInvalidPISChecksum},
+	}
+
+	for _, ep := range entryPoints() {
+		for _, c := range cases {
+			for _, strict := range []bool{false, true} {
+				config := sanitize.Config{Type: c.typ, StrictMode: strict}
+				if got, err := ep.sanitize(c.valid, config); err != nil || got != c.want {
+					t.Errorf("%s/%s strict=%v: valid %q got (%q, %v)", ep.name, c.typ, strict, c.valid, got, err)
+				}
+
+				for _, input := range c.invalid {
+					got, err := ep.sanitize(input, config)
+					if got != "" || !errors.Is(err, c.wantErr) {
+						t.Errorf("%s/%s strict=%v: %q got (%q, %v), want %v", ep.name, c.typ, strict, input, got, err, c.wantErr)
+						continue
+					}
+					if code := sanitize.ErrorCode(err); code != sanitize.CodeInvalidChecksum {
+						t.Errorf("%s/%s strict=%v: %q error code = %q, want %q", ep.name, c.typ, strict, input, code, sanitize.CodeInvalidChecksum)
+					}
+				}
+			}
+		}
+	}
+}
+
+// upperFormatter is a custom formatter used to check extension points.
+type upperFormatter struct{}
+
+func (upperFormatter) Format(input string, _ sanitize.Type) (string, error) {
+	return "<" + strings.ToUpper(input) + ">", nil
+}
+
+// TestExtensionPoints checks that registrations on the shared engine apply to
+// every entry point and replace built-in handlers of the same type.
+func TestExtensionPoints(t *testing.T) {
+	const custom sanitize.Type = "conformance_upper"
+
+	sanitize.Default().RegisterFormatter(custom, upperFormatter{})
+	sanitize.Default().RegisterFormatter(sanitize.TrimSpaces, upperFormatter{})
+	t.Cleanup(func() {
+		sanitize.Default().Unregister(custom)
+		sanitize.Default().RegisterNormalizer(sanitize.TrimSpaces, &sanitize.DefaultNormalizer{})
+	})
+
+	shared := []entryPoint{
+		{"sanitize.Default", sanitize.Default().Sanitize},
+		{"persistence.DefaultSanitizer", persistence.DefaultSanitizer{}.Sanitize},
+	}
+	for _, ep := range shared {
+		if got, err := ep.sanitize("abc", sanitize.Config{Type: custom}); err != nil || got != "<ABC>" {
+			t.Errorf("%s: custom type got (%q, %v)", ep.name, got, err)
+		}
+		if got, err := ep.sanitize(" a ", sanitize.Config{Type: sanitize.TrimSpaces}); err != nil || got != "< A >" {
+			t.Errorf("%s: overridden trim_spaces got (%q, %v)", ep.name, got, err)
+		}
+	}
+
+	issues := persistence.DefaultValidator{}.ValidateConfig(persistence.Config{
+		Enabled: true, Scope: persistence.ScopeState, Key: "k",
+		Sanitization: &persistence.SanitizationConfig{Type: custom},
+	})
+	if len(issues) != 0 {
+		t.Errorf("registered type rejected by persistence validator: %v", issues)
+	}
+
+	// Isolated services are not affected by registrations on the shared engine
+	if _, err := sanitize.NewService().Sanitize("abc", sanitize.Config{Type: custom}); !errors.Is(err, sanitize.ErrUnsupportedSanitizationType) {
+		t.Errorf("new service should not see shared registrations, got %v", err)
+	}
+}
+
+// TestConfigHandlers checks that configuration-driven types go through the
+// registry like every other type.
+func TestConfigHandlers(t *testing.T) {
+	service := sanitize.NewService()
+	for _, typ := range []sanitize.Type{sanitize.PhoneE164, sanitize.DateTimezone, sanitize.Custom} {
+		if !service.Supports(typ) {
+			t.Errorf("%s not supported by default", typ)
+		}
+	}
+
+	service.Unregister(sanitize.PhoneE164)
+	if service.Supports(sanitize.PhoneE164) {
+		t.Error("phone_e164 still supported after Unregister")
+	}
+	if _, err := service.Sanitize("11987654321", sanitize.Config{Type: sanitize.PhoneE164}); !errors.Is(err, sanitize.ErrUnsupportedSanitizationType) {
+		t.Errorf("unregistered phone_e164 err = %v", err)
+	}
+
+	service.RegisterHandler(sanitize.Custom, sanitize.HandlerFunc(func(input string, config sanitize.Config) (string, error) {
+		return config.Replacement + input, nil
+	}))
+	if got, err := service.Sanitize("abc", sanitize.Config{Type: sanitize.Custom, Replacement: "x-"}); err != nil || got != "x-abc" {
+		t.Errorf("overridden custom got (%q, %v)", got, err)
+	}
+}
+
+// TestPipeline checks chaining, per-step strict mode and that both JSON forms
+// of persistence sanitization produce the same chain.
+func TestPipeline(t *testing.T) {
+	steps := []sanitize.Config{
+		{Type: sanitize.TrimSpaces},
+		{Type: sanitize.CPF},
+		{Type: sanitize.NameCase},
+	}
+	got, trace, err := sanitize.Default().SanitizePipeline("  ana  ", steps)
+	if err != nil || got != "Ana" || len(trace) != 3 || !trace[1].Skipped || trace[2].Input != "ana" {
+		t.Fatalf("non-strict pipeline got (%q, %+v, %v)", got, trace, err)
+	}
+
+	steps[1].StrictMode = true
+	if _, trace, err = sanitize.Default().SanitizePipeline("  ana  ", steps); !errors.Is(err, sanitize.ErrInvalidCPFLength) || len(trace) != 2 {
+		t.Fatalf("strict pipeline got (%+v, %v)", trace, err)
+	}
+
+	for _, raw := range []string{
+		`{"enabled":true,"scope":"state","key":"cpf","sanitization":{"type":"cpf"}}`,
+		`{"enabled":true,"scope":"state","key":"cpf","sanitization":[{"type":"numbers_only"},{"type":"cpf"}]}`,
+	} {
+		var config persistence.Config
+		if err := json.Unmarshal([]byte(raw), &config); err != nil {
+			t.Fatalf("unmarshal %s: %v", raw, err)
+		}
+		if issues := (persistence.DefaultValidator{}).ValidateConfig(config); len(issues) != 0 {
+			t.Errorf("%s: unexpected issues %v", raw, issues)
+		}
+		if got, _, err := (persistence.DefaultSanitizer{}).SanitizePipeline("cpf 529.982.247-25", config); err != nil || got != "529.982.247-25" {
+			t.Errorf("%s: got (%q, %v)", raw, got, err)
+		}
+	}
+
+	issues := persistence.DefaultValidator{}.ValidateConfig(persistence.Config{
+		Enabled: true, Scope: persistence.ScopeState, Key: "k",
+		Pipeline: []persistence.SanitizationConfig{{Type: sanitize.TrimSpaces}, {Type: "unknown"}},
+	})
+	if len(issues) != 1 || issues[0].Path != "persistence.sanitizationPipeline[1].type" {
+		t.Errorf("invalid step got issues %v", issues)
+	}
+}
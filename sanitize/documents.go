@@ -0,0 +1,159 @@
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error codes returned in Error.Code. They are stable so flows can route on them.
+const (
+	CodeInvalidLength   = "invalid_length"
+	CodeInvalidChecksum = "invalid_checksum"
+	CodeInvalidFormat   = "invalid_format"
+)
+
+// Static errors for document validation.
+var (
+	ErrInvalidCPFChecksum  = errors.New("CPF check digits do not match")
+	ErrInvalidCNPJLength   = errors.New("CNPJ must have 14 characters")
+	ErrInvalidCNPJChecksum = errors.New("CNPJ check digits do not match")
+	ErrInvalidRGLength     = errors.New("RG must have between 5 and 14 characters")
+	ErrInvalidPISLength    = errors.New("PIS must have 11 digits")
+	ErrInvalidPISChecksum  = errors.New("PIS check digit does not match")
+)
+
+// Error describes why an input could not be sanitized.
+// Code is one of the Code* constants; Value holds the best-effort formatted
+// value (for display only: documents with invalid check digits are always rejected).
+type Error struct {
+	Type  Type
+	Code  string
+	Value string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Type, e.Err)
+}
+
+// Unwrap returns the underlying static error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the routing code of a sanitization error, or "" if err is not an *Error.
+func ErrorCode(err error) string {
+	var sanitizeErr *Error
+	if errors.As(err, &sanitizeErr) {
+		return sanitizeErr.Code
+	}
+	return ""
+}
+
+const (
+	cnpjDigits = 14
+	pisDigits  = 11
+	rgMinChars = 5
+	rgMaxChars = 14
+)
+
+// cnpjWeights are the modulo 11 weights for the second CNPJ check digit;
+// the first check digit uses the same list without its first element.
+var cnpjWeights = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// pisWeights are the modulo 11 weights for the PIS/PASEP/NIT check digit.
+var pisWeights = []int{3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// ValidCPF reports whether digits is an 11-digit CPF with valid check digits.
+// Repeated sequences (000.000.000-00, 111.111.111-11...) are rejected.
+func ValidCPF(digits string) bool {
+	if len(digits) != cpfDigits || !onlyDigits(digits) || repeated(digits) {
+		return false
+	}
+
+	return checkDigit(digits[:9], cpfWeights(10)) == digits[9] &&
+		checkDigit(digits[:10], cpfWeights(11)) == digits[10]
+}
+
+// ValidCNPJ reports whether cnpj is a valid CNPJ, numeric or alphanumeric.
+// The alphanumeric format uses [0-9A-Z] in the first 12 positions, each
+// valued as its ASCII code minus 48, and keeps two numeric check digits.
+// Punctuation is ignored and letters are case-insensitive.
+func ValidCNPJ(cnpj string) bool {
+	cnpj = NormalizeCNPJ(cnpj)
+	if len(cnpj) != cnpjDigits || !onlyDigits(cnpj[12:]) || repeated(cnpj) {
+		return false
+	}
+
+	return checkDigit(cnpj[:12], cnpjWeights[1:]) == cnpj[12] &&
+		checkDigit(cnpj[:13], cnpjWeights) == cnpj[13]
+}
+
+// NormalizeCNPJ strips punctuation and upper-cases an (alphanumeric) CNPJ.
+func NormalizeCNPJ(input string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(input) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ValidPIS reports whether digits is an 11-digit PIS/PASEP/NIT with a valid check digit.
+func ValidPIS(digits string) bool {
+	if len(digits) != pisDigits || !onlyDigits(digits) || repeated(digits) {
+		return false
+	}
+
+	sum := 0
+	for i, w := range pisWeights {
+		sum += int(digits[i]-'0') * w
+	}
+	dv := 11 - sum%11
+	if dv >= 10 {
+		dv = 0
+	}
+	return digits[10] == byte('0'+dv)
+}
+
+// cpfWeights returns the descending weights (start..2) for a CPF check digit.
+func cpfWeights(start int) []int {
+	weights := make([]int, 0, start-1)
+	for w := start; w >= 2; w-- {
+		weights = append(weights, w)
+	}
+	return weights
+}
+
+// checkDigit computes a modulo 11 check digit for base using weights.
+// Characters are valued as their ASCII code minus 48, which maps digits to
+// 0-9 and letters to 17-42 as required by the alphanumeric CNPJ.
+func checkDigit(base string, weights []int) byte {
+	sum := 0
+	for i := 0; i < len(base); i++ {
+		sum += int(base[i]-'0') * weights[i]
+	}
+	rest := sum % 11
+	if rest < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - rest)
+}
+
+// onlyDigits reports whether s contains only ASCII digits.
+func onlyDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// repeated reports whether s is the same character repeated.
+func repeated(s string) bool {
+	return s != "" && strings.Count(s, s[:1]) == len(s)
+}
@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-
-	"github.com/AgendoCerto/lib-bot/persistence"
 )
 
 // Static errors for better error handling.
@@ -32,13 +30,13 @@ const (
 type DefaultExtractor struct{}
 
 // Extract extracts specific characters from input text.
-func (e *DefaultExtractor) Extract(input string, sanitizationType persistence.SanitizationType) string {
+func (e *DefaultExtractor) Extract(input string, sanitizationType Type) string {
 	switch sanitizationType {
-	case persistence.SanitizeNumbersOnly:
+	case NumbersOnly:
 		return e.extractNumbers(input)
-	case persistence.SanitizeLettersOnly:
+	case LettersOnly:
 		return e.extractLetters(input)
-	case persistence.SanitizeAlphanumeric:
+	case Alphanumeric:
 		return e.extractAlphanumeric(input)
 	default:
 		return input
@@ -70,32 +68,104 @@ func (e *DefaultExtractor) extractAlphanumeric(input string) string {
 type DefaultFormatter struct{}
 
 // Format formats input according to Brazilian document standards.
-func (f *DefaultFormatter) Format(input string, sanitizationType persistence.SanitizationType) (string, error) {
+func (f *DefaultFormatter) Format(input string, sanitizationType Type) (string, error) {
 	switch sanitizationType {
-	case persistence.SanitizeCPF:
+	case CPF:
 		return f.formatCPF(input)
-	case persistence.SanitizeCEP:
+	case CNPJ:
+		return f.formatCNPJ(input)
+	case RG:
+		return f.formatRG(input)
+	case PIS:
+		return f.formatPIS(input)
+	case CEP:
 		return f.formatCEP(input)
-	case persistence.SanitizePhone:
+	case Phone:
 		return f.formatPhone(input)
-	case persistence.SanitizeBRL:
+	case BRL:
 		return f.formatBRL(input)
 	default:
 		return input, nil
 	}
 }
 
-// formatCPF extracts and formats CPF.
+// formatCPF extracts, verifies and formats CPF.
 func (f *DefaultFormatter) formatCPF(input string) (string, error) {
 	extractor := &DefaultExtractor{}
 	numbers := extractor.extractNumbers(input)
 
 	if len(numbers) != cpfDigits {
-		return "", ErrInvalidCPFLength
+		return "", &Error{Type: CPF, Code: CodeInvalidLength, Err: ErrInvalidCPFLength}
 	}
 
 	// Format: XXX.XXX.XXX-XX
-	return numbers[:3] + "." + numbers[3:6] + "." + numbers[6:9] + "-" + numbers[9:], nil
+	formatted := numbers[:3] + "." + numbers[3:6] + "." + numbers[6:9] + "-" + numbers[9:]
+	if !ValidCPF(numbers) {
+		return "", &Error{Type: CPF, Code: CodeInvalidChecksum, Value: formatted, Err: ErrInvalidCPFChecksum}
+	}
+
+	return formatted, nil
+}
+
+// formatCNPJ extracts, verifies and formats CNPJ (numeric or alphanumeric).
+func (f *DefaultFormatter) formatCNPJ(input string) (string, error) {
+	cnpj := NormalizeCNPJ(input)
+
+	if len(cnpj) != cnpjDigits {
+		return "", &Error{Type: CNPJ, Code: CodeInvalidLength, Err: ErrInvalidCNPJLength}
+	}
+
+	// Format: XX.XXX.XXX/XXXX-XX
+	formatted := cnpj[:2] + "." + cnpj[2:5] + "." + cnpj[5:8] + "/" + cnpj[8:12] + "-" + cnpj[12:]
+	if !ValidCNPJ(cnpj) {
+		return "", &Error{Type: CNPJ, Code: CodeInvalidChecksum, Value: formatted, Err: ErrInvalidCNPJChecksum}
+	}
+
+	return formatted, nil
+}
+
+// formatRG extracts and formats RG.
+// RG numbering is issued per state and has no national check digit, so only
+// the length is verified; 9-character RGs use the XX.XXX.XXX-X layout.
+func (f *DefaultFormatter) formatRG(input string) (string, error) {
+	rg := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == 'x' || r == 'X':
+			return 'X'
+		default:
+			return -1
+		}
+	}, input)
+
+	if len(rg) < rgMinChars || len(rg) > rgMaxChars {
+		return "", &Error{Type: RG, Code: CodeInvalidLength, Err: ErrInvalidRGLength}
+	}
+
+	if len(rg) == 9 {
+		return rg[:2] + "." + rg[2:5] + "." + rg[5:8] + "-" + rg[8:], nil
+	}
+
+	return rg, nil
+}
+
+// formatPIS extracts, verifies and formats PIS/PASEP/NIT.
+func (f *DefaultFormatter) formatPIS(input string) (string, error) {
+	extractor := &DefaultExtractor{}
+	numbers := extractor.extractNumbers(input)
+
+	if len(numbers) != pisDigits {
+		return "", &Error{Type: PIS, Code: CodeInvalidLength, Err: ErrInvalidPISLength}
+	}
+
+	// Format: XXX.XXXXX.XX-X
+	formatted := numbers[:3] + "." + numbers[3:8] + "." + numbers[8:10] + "-" + numbers[10:]
+	if !ValidPIS(numbers) {
+		return "", &Error{Type: PIS, Code: CodeInvalidChecksum, Value: formatted, Err: ErrInvalidPISChecksum}
+	}
+
+	return formatted, nil
 }
 
 // formatCEP extracts and formats CEP.
@@ -104,7 +174,7 @@ func (f *DefaultFormatter) formatCEP(input string) (string, error) {
 	numbers := extractor.extractNumbers(input)
 
 	if len(numbers) != cepDigits {
-		return "", ErrInvalidCEPLength
+		return "", &Error{Type: CEP, Code: CodeInvalidLength, Err: ErrInvalidCEPLength}
 	}
 
 	// Format: XXXXX-XXX
@@ -129,7 +199,7 @@ func (f *DefaultFormatter) formatPhone(input string) (string, error) {
 		// Landline: (XX) XXXX-XXXX
 		return "(" + numbers[:2] + ") " + numbers[2:6] + "-" + numbers[6:], nil
 	default:
-		return "", ErrInvalidPhoneLength
+		return "", &Error{Type: Phone, Code: CodeInvalidLength, Err: ErrInvalidPhoneLength}
 	}
 }
 
@@ -158,9 +228,9 @@ func (f *DefaultFormatter) formatBRL(input string) (string, error) {
 type DefaultValidator struct{}
 
 // Validate validates and sanitizes input.
-func (v *DefaultValidator) Validate(input string, sanitizationType persistence.SanitizationType) (string, error) {
+func (v *DefaultValidator) Validate(input string, sanitizationType Type) (string, error) {
 	switch sanitizationType {
-	case persistence.SanitizeEmail:
+	case Email:
 		return v.validateEmail(input)
 	default:
 		return input, nil
@@ -173,7 +243,7 @@ func (v *DefaultValidator) validateEmail(input string) (string, error) {
 	regexPattern := regexp.MustCompile(`^[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}$`)
 
 	if !regexPattern.MatchString(input) {
-		return "", ErrInvalidEmailFormat
+		return "", &Error{Type: Email, Code: CodeInvalidFormat, Err: ErrInvalidEmailFormat}
 	}
 
 	return input, nil
@@ -183,15 +253,15 @@ func (v *DefaultValidator) validateEmail(input string) (string, error) {
 type DefaultNormalizer struct{}
 
 // Normalize normalizes text format.
-func (n *DefaultNormalizer) Normalize(input string, sanitizationType persistence.SanitizationType) string {
+func (n *DefaultNormalizer) Normalize(input string, sanitizationType Type) string {
 	switch sanitizationType {
-	case persistence.SanitizeNameCase:
+	case NameCase:
 		return n.nameCase(input)
-	case persistence.SanitizeUpperCase:
+	case UpperCase:
 		return strings.ToUpper(strings.TrimSpace(input))
-	case persistence.SanitizeLowerCase:
+	case LowerCase:
 		return strings.ToLower(strings.TrimSpace(input))
-	case persistence.SanitizeTrimSpaces:
+	case TrimSpaces:
 		return n.trimExtraSpaces(input)
 	default:
 		return input
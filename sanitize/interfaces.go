@@ -1,24 +1,35 @@
 // Package sanitize provides interfaces for data sanitization.
 package sanitize
 
-import "github.com/AgendoCerto/lib-bot/persistence"
-
 // TextExtractor extracts specific types of text from input.
 type TextExtractor interface {
-	Extract(input string, sanitizationType persistence.SanitizationType) string
+	Extract(input string, sanitizationType Type) string
 }
 
 // Formatter formats text according to specific rules.
 type Formatter interface {
-	Format(input string, sanitizationType persistence.SanitizationType) (string, error)
+	Format(input string, sanitizationType Type) (string, error)
 }
 
 // Validator validates and sanitizes text.
 type Validator interface {
-	Validate(input string, sanitizationType persistence.SanitizationType) (string, error)
+	Validate(input string, sanitizationType Type) (string, error)
 }
 
 // Normalizer normalizes text format.
 type Normalizer interface {
-	Normalize(input string, sanitizationType persistence.SanitizationType) string
+	Normalize(input string, sanitizationType Type) string
+}
+
+// Handler sanitizes using the whole configuration (region, regex, timezone).
+type Handler interface {
+	Handle(input string, config Config) (string, error)
+}
+
+// HandlerFunc adapts a function to the Handler interface.
+type HandlerFunc func(input string, config Config) (string, error)
+
+// Handle calls f(input, config).
+func (f HandlerFunc) Handle(input string, config Config) (string, error) {
+	return f(input, config)
 }
@@ -0,0 +1,216 @@
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Static errors for international phone normalization.
+var (
+	ErrInvalidPhoneNumber = errors.New("invalid phone number")
+	ErrUnknownPhoneRegion = errors.New("unknown phone region")
+)
+
+// DefaultPhoneRegion is the region assumed for numbers without a country code.
+const DefaultPhoneRegion = "BR"
+
+// PhoneType is a hint of the kind of line a number belongs to.
+type PhoneType string
+
+const (
+	PhoneMobile   PhoneType = "mobile"
+	PhoneLandline PhoneType = "landline"
+	PhoneUnknown  PhoneType = "unknown" // Region does not distinguish (e.g. US) or country code is not known
+)
+
+// PhoneNumber is a phone number normalized to E.164.
+type PhoneNumber struct {
+	E164        string    `json:"e164"`             // +5511987654321
+	CountryCode string    `json:"country_code"`     // 55 (empty when the country code is not known)
+	Region      string    `json:"region,omitempty"` // BR (empty when the country code is not known)
+	National    string    `json:"national"`         // National significant number: 11987654321
+	Type        PhoneType `json:"type"`
+}
+
+// phoneRegion holds the numbering rules of one country.
+type phoneRegion struct {
+	region    string
+	code      string
+	normalize func(national string) (string, PhoneType, error)
+}
+
+// phoneRegions lists supported regions; longer country codes come first so
+// prefix detection is unambiguous.
+var phoneRegions = []phoneRegion{
+	{region: "PT", code: "351", normalize: normalizePT},
+	{region: "AR", code: "54", normalize: normalizeAR},
+	{region: "BR", code: "55", normalize: normalizeBR},
+	{region: "US", code: "1", normalize: normalizeUS},
+}
+
+// PhoneRegions returns the supported region codes.
+func PhoneRegions() []string {
+	regions := make([]string, 0, len(phoneRegions))
+	for _, r := range phoneRegions {
+		regions = append(regions, r.region)
+	}
+	return regions
+}
+
+// ParsePhone normalizes a phone number to E.164.
+// Numbers starting with "+" or "00" carry their country code; other numbers
+// are read in defaultRegion ("" uses DefaultPhoneRegion), also accepting the
+// region's country code without "+" (e.g. "5511987654321").
+// Brazilian 8-digit mobiles (old format) get the ninth digit.
+func ParsePhone(input, defaultRegion string) (PhoneNumber, error) {
+	trimmed := strings.TrimSpace(input)
+	digits := (&DefaultExtractor{}).extractNumbers(trimmed)
+	international := strings.HasPrefix(trimmed, "+")
+	if !international && strings.HasPrefix(digits, "00") {
+		international, digits = true, digits[2:]
+	}
+	if digits == "" {
+		return PhoneNumber{}, phoneError(CodeInvalidFormat, ErrInvalidPhoneNumber)
+	}
+
+	if international {
+		for _, r := range phoneRegions {
+			if strings.HasPrefix(digits, r.code) {
+				return r.parse(digits[len(r.code):])
+			}
+		}
+		// Unknown country code: keep the number as is if it fits E.164
+		if len(digits) < 8 || len(digits) > 15 {
+			return PhoneNumber{}, phoneError(CodeInvalidLength, ErrInvalidPhoneNumber)
+		}
+		return PhoneNumber{E164: "+" + digits, National: digits, Type: PhoneUnknown}, nil
+	}
+
+	if defaultRegion == "" {
+		defaultRegion = DefaultPhoneRegion
+	}
+	r, ok := findPhoneRegion(defaultRegion)
+	if !ok {
+		return PhoneNumber{}, fmt.Errorf("%w: %s", ErrUnknownPhoneRegion, defaultRegion)
+	}
+	phone, err := r.parse(digits)
+	if err != nil && strings.HasPrefix(digits, r.code) {
+		if withCode, codeErr := r.parse(digits[len(r.code):]); codeErr == nil {
+			return withCode, nil
+		}
+	}
+	return phone, err
+}
+
+// FormatE164 normalizes a phone number and returns its E.164 form.
+func FormatE164(input, defaultRegion string) (string, error) {
+	phone, err := ParsePhone(input, defaultRegion)
+	if err != nil {
+		return "", err
+	}
+	return phone.E164, nil
+}
+
+// parse applies the region rules to a national number.
+func (r phoneRegion) parse(national string) (PhoneNumber, error) {
+	nsn, phoneType, err := r.normalize(national)
+	if err != nil {
+		return PhoneNumber{}, err
+	}
+	return PhoneNumber{E164: "+" + r.code + nsn, CountryCode: r.code, Region: r.region, National: nsn, Type: phoneType}, nil
+}
+
+// findPhoneRegion looks up a region by its ISO code (case-insensitive).
+func findPhoneRegion(region string) (phoneRegion, bool) {
+	for _, r := range phoneRegions {
+		if strings.EqualFold(r.region, region) {
+			return r, true
+		}
+	}
+	return phoneRegion{}, false
+}
+
+// normalizeBR handles 2-digit area codes, the trunk prefix 0 and the ninth
+// digit: 8-digit subscribers starting with 6-9 are old-format mobiles.
+func normalizeBR(n string) (string, PhoneType, error) {
+	if strings.HasPrefix(n, "0") {
+		n = n[1:]
+	}
+	if len(n) != phoneDigits && len(n) != cellDigits {
+		return "", "", phoneError(CodeInvalidLength, ErrInvalidPhoneLength)
+	}
+	if n[0] == '0' || n[1] == '0' {
+		return "", "", phoneError(CodeInvalidFormat, ErrInvalidPhoneNumber)
+	}
+
+	area, subscriber := n[:2], n[2:]
+	switch {
+	case len(subscriber) == 9 && subscriber[0] == '9':
+		return n, PhoneMobile, nil
+	case len(subscriber) == 8 && subscriber[0] >= '6':
+		return area + "9" + subscriber, PhoneMobile, nil
+	case len(subscriber) == 8 && subscriber[0] >= '2':
+		return n, PhoneLandline, nil
+	default:
+		return "", "", phoneError(CodeInvalidFormat, ErrInvalidPhoneNumber)
+	}
+}
+
+// normalizePT handles 9-digit numbers: 9x mobiles and 2x landlines.
+func normalizePT(n string) (string, PhoneType, error) {
+	if len(n) != 9 {
+		return "", "", phoneError(CodeInvalidLength, ErrInvalidPhoneNumber)
+	}
+	switch n[0] {
+	case '9':
+		return n, PhoneMobile, nil
+	case '2':
+		return n, PhoneLandline, nil
+	default:
+		return n, PhoneUnknown, nil
+	}
+}
+
+// normalizeAR handles the trunk prefix 0 and mobiles, which are dialed
+// internationally as 9 + area + number and nationally as area + 15 + number.
+func normalizeAR(n string) (string, PhoneType, error) {
+	if strings.HasPrefix(n, "0") {
+		n = n[1:]
+	}
+	switch len(n) {
+	case 11:
+		if n[0] == '9' {
+			return n, PhoneMobile, nil
+		}
+	case 12:
+		// Area codes have 2 to 4 digits; "15" marks a mobile in national dialing
+		for areaLen := 2; areaLen <= 4; areaLen++ {
+			if n[areaLen:areaLen+2] == "15" {
+				return "9" + n[:areaLen] + n[areaLen+2:], PhoneMobile, nil
+			}
+		}
+	case 10:
+		return n, PhoneLandline, nil
+	}
+	return "", "", phoneError(CodeInvalidLength, ErrInvalidPhoneNumber)
+}
+
+// normalizeUS handles NANP numbers with optional trunk prefix 1.
+// Mobile and landline numbers share ranges, so the type is unknown.
+func normalizeUS(n string) (string, PhoneType, error) {
+	if len(n) == 11 && n[0] == '1' {
+		n = n[1:]
+	}
+	if len(n) != 10 {
+		return "", "", phoneError(CodeInvalidLength, ErrInvalidPhoneNumber)
+	}
+	if n[0] < '2' || n[3] < '2' {
+		return "", "", phoneError(CodeInvalidFormat, ErrInvalidPhoneNumber)
+	}
+	return n, PhoneUnknown, nil
+}
+
+func phoneError(code string, err error) error {
+	return &Error{Type: PhoneE164, Code: code, Err: err}
+}
@@ -0,0 +1,45 @@
+package sanitize
+
+import "fmt"
+
+// StepTrace records what one pipeline step did to the value.
+type StepTrace struct {
+	Index   int    `json:"index"`
+	Type    Type   `json:"type"`
+	Input   string `json:"input"`
+	Output  string `json:"output"`            // Value after the step (the input when the step was skipped)
+	Error   string `json:"error,omitempty"`   // Step error, if any
+	Skipped bool   `json:"skipped,omitempty"` // Step failed outside strict mode and the value passed through unchanged
+}
+
+// SanitizePipeline applies the steps in order, feeding each output into the
+// next step, and returns the final value with one trace entry per executed step.
+// A failing step stops the pipeline when its StrictMode is set; otherwise the
+// step is skipped and the value passes through unchanged.
+func (s *Service) SanitizePipeline(input string, steps []Config) (string, []StepTrace, error) {
+	traces := make([]StepTrace, 0, len(steps))
+	value := input
+
+	for index, step := range steps {
+		trace := StepTrace{Index: index, Type: step.Type, Input: value}
+
+		output, err := s.Sanitize(value, step)
+		if err != nil {
+			trace.Error = err.Error()
+			trace.Output = value
+			if step.StrictMode {
+				traces = append(traces, trace)
+				return "", traces, fmt.Errorf("sanitization step %d (%s): %w", index, step.Type, err)
+			}
+			trace.Skipped = true
+			traces = append(traces, trace)
+			continue
+		}
+
+		trace.Output = output
+		traces = append(traces, trace)
+		value = output
+	}
+
+	return value, traces, nil
+}
@@ -6,9 +6,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/AgendoCerto/lib-bot/persistence"
 )
 
 // Static errors for better error handling.
@@ -18,23 +17,42 @@ var (
 	ErrFormatterNotFound           = errors.New("formatter not found for type")
 	ErrValidatorNotFound           = errors.New("validator not found for type")
 	ErrNormalizerNotFound          = errors.New("normalizer not found for type")
+	ErrInvalidTimezone             = errors.New("invalid timezone")
+	ErrInvalidRegex                = errors.New("invalid regex")
 )
 
+// defaultTimezone is used by get_date_timezone when no timezone is configured.
+const defaultTimezone = "America/Sao_Paulo"
+
 // Service provides sanitization functionality.
+// Each type is handled by exactly one registered implementation; registering
+// a type replaces whatever handled it before, including built-in handlers.
+// A Service is safe for concurrent use.
 type Service struct {
-	extractors  map[persistence.SanitizationType]TextExtractor
-	formatters  map[persistence.SanitizationType]Formatter
-	validators  map[persistence.SanitizationType]Validator
-	normalizers map[persistence.SanitizationType]Normalizer
+	mu          sync.RWMutex
+	extractors  map[Type]TextExtractor
+	formatters  map[Type]Formatter
+	validators  map[Type]Validator
+	normalizers map[Type]Normalizer
+	handlers    map[Type]Handler
+}
+
+var defaultService = NewService()
+
+// Default returns the shared service used by persistence.DefaultSanitizer.
+// Implementations registered on it apply to every entry point.
+func Default() *Service {
+	return defaultService
 }
 
 // NewService creates a new sanitization service with default implementations.
 func NewService() *Service {
 	service := &Service{
-		extractors:  make(map[persistence.SanitizationType]TextExtractor),
-		formatters:  make(map[persistence.SanitizationType]Formatter),
-		validators:  make(map[persistence.SanitizationType]Validator),
-		normalizers: make(map[persistence.SanitizationType]Normalizer),
+		extractors:  make(map[Type]TextExtractor),
+		formatters:  make(map[Type]Formatter),
+		validators:  make(map[Type]Validator),
+		normalizers: make(map[Type]Normalizer),
+		handlers:    make(map[Type]Handler),
 	}
 
 	service.registerDefaults()
@@ -45,103 +63,105 @@ func NewService() *Service {
 func (s *Service) registerDefaults() {
 	// Extractors
 	extractor := &DefaultExtractor{}
-	s.extractors[persistence.SanitizeNumbersOnly] = extractor
-	s.extractors[persistence.SanitizeLettersOnly] = extractor
-	s.extractors[persistence.SanitizeAlphanumeric] = extractor
+	s.extractors[NumbersOnly] = extractor
+	s.extractors[LettersOnly] = extractor
+	s.extractors[Alphanumeric] = extractor
 
 	// Formatters
 	formatter := &DefaultFormatter{}
-	s.formatters[persistence.SanitizeCPF] = formatter
-	s.formatters[persistence.SanitizeCEP] = formatter
-	s.formatters[persistence.SanitizePhone] = formatter
-	s.formatters[persistence.SanitizeBRL] = formatter
+	s.formatters[CPF] = formatter
+	s.formatters[CNPJ] = formatter
+	s.formatters[RG] = formatter
+	s.formatters[PIS] = formatter
+	s.formatters[CEP] = formatter
+	s.formatters[Phone] = formatter
+	s.formatters[BRL] = formatter
 
 	// Validators
 	validator := &DefaultValidator{}
-	s.validators[persistence.SanitizeEmail] = validator
+	s.validators[Email] = validator
 
 	// Normalizers
 	normalizer := &DefaultNormalizer{}
-	s.normalizers[persistence.SanitizeNameCase] = normalizer
-	s.normalizers[persistence.SanitizeUpperCase] = normalizer
-	s.normalizers[persistence.SanitizeLowerCase] = normalizer
-	s.normalizers[persistence.SanitizeTrimSpaces] = normalizer
+	s.normalizers[NameCase] = normalizer
+	s.normalizers[UpperCase] = normalizer
+	s.normalizers[LowerCase] = normalizer
+	s.normalizers[TrimSpaces] = normalizer
+
+	// Handlers (configuration-driven types)
+	s.handlers[PhoneE164] = HandlerFunc(handlePhoneE164)
+	s.handlers[DateTimezone] = HandlerFunc(handleDateTimezone)
+	s.handlers[Custom] = HandlerFunc(handleCustomRegex)
 }
 
 // Sanitize applies sanitization based on the configuration.
-func (s *Service) Sanitize(input string, config persistence.SanitizationConfig) (string, error) {
+// Errors are returned whatever the StrictMode; document failures (length,
+// check digits) are *Error values with a Code.
+func (s *Service) Sanitize(input string, config Config) (string, error) {
 	if input == "" {
 		return input, nil
 	}
 
-	switch config.Type {
-	case persistence.SanitizeNumbersOnly, persistence.SanitizeLettersOnly, persistence.SanitizeAlphanumeric:
-		return s.handleExtraction(input, config)
-	case persistence.SanitizeCPF, persistence.SanitizeCEP, persistence.SanitizePhone, persistence.SanitizeBRL:
-		return s.handleFormatting(input, config)
-	case persistence.SanitizeEmail:
-		return s.handleValidation(input, config)
-	case persistence.SanitizeNameCase, persistence.SanitizeUpperCase, persistence.SanitizeLowerCase, persistence.SanitizeTrimSpaces:
-		return s.handleNormalization(input, config)
-	case persistence.SanitizeDateTimezone:
-		return s.handleDateTimezone(config)
-	case persistence.SanitizeCustom:
-		return s.handleCustomRegex(input, config)
+	s.mu.RLock()
+	extractor, isExtractor := s.extractors[config.Type]
+	formatter, isFormatter := s.formatters[config.Type]
+	validator, isValidator := s.validators[config.Type]
+	normalizer, isNormalizer := s.normalizers[config.Type]
+	handler, isHandler := s.handlers[config.Type]
+	s.mu.RUnlock()
+
+	switch {
+	case isExtractor:
+		return extractor.Extract(input, config.Type), nil
+	case isFormatter:
+		return s.handleFormatting(formatter, input, config)
+	case isValidator:
+		return validator.Validate(input, config.Type)
+	case isNormalizer:
+		return normalizer.Normalize(input, config.Type), nil
+	case isHandler:
+		return handler.Handle(input, config)
 	default:
 		return input, fmt.Errorf("%w: %s", ErrUnsupportedSanitizationType, config.Type)
 	}
 }
 
-// handleExtraction handles text extraction sanitization.
-func (s *Service) handleExtraction(input string, config persistence.SanitizationConfig) (string, error) {
-	extractor, exists := s.extractors[config.Type]
-	if !exists {
-		return input, fmt.Errorf("%w: %s", ErrExtractorNotFound, config.Type)
-	}
-
-	return extractor.Extract(input, config.Type), nil
+// Supports reports whether the service can handle the sanitization type.
+func (s *Service) Supports(sanitizationType Type) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, isExtractor := s.extractors[sanitizationType]
+	_, isFormatter := s.formatters[sanitizationType]
+	_, isValidator := s.validators[sanitizationType]
+	_, isNormalizer := s.normalizers[sanitizationType]
+	_, isHandler := s.handlers[sanitizationType]
+	return isExtractor || isFormatter || isValidator || isNormalizer || isHandler
 }
 
 // handleFormatting handles document formatting sanitization.
-func (s *Service) handleFormatting(input string, config persistence.SanitizationConfig) (string, error) {
-	formatter, exists := s.formatters[config.Type]
-	if !exists {
-		return input, fmt.Errorf("%w: %s", ErrFormatterNotFound, config.Type)
-	}
-
+// Length and check-digit failures are returned as *Error in every mode;
+// StrictMode only matters to SanitizePipeline.
+func (s *Service) handleFormatting(formatter Formatter, input string, config Config) (string, error) {
 	return formatter.Format(input, config.Type)
 }
 
-// handleValidation handles validation-based sanitization.
-func (s *Service) handleValidation(input string, config persistence.SanitizationConfig) (string, error) {
-	validator, exists := s.validators[config.Type]
-	if !exists {
-		return input, fmt.Errorf("%w: %s", ErrValidatorNotFound, config.Type)
-	}
-
-	return validator.Validate(input, config.Type)
-}
-
-// handleNormalization handles text normalization sanitization.
-func (s *Service) handleNormalization(input string, config persistence.SanitizationConfig) (string, error) {
-	normalizer, exists := s.normalizers[config.Type]
-	if !exists {
-		return input, fmt.Errorf("%w: %s", ErrNormalizerNotFound, config.Type)
-	}
-
-	return normalizer.Normalize(input, config.Type), nil
+// handlePhoneE164 handles international phone normalization.
+// The region comes from the configuration because it varies per flow.
+func handlePhoneE164(input string, config Config) (string, error) {
+	return FormatE164(input, config.Region)
 }
 
 // handleDateTimezone handles date/timezone sanitization.
-func (s *Service) handleDateTimezone(config persistence.SanitizationConfig) (string, error) {
+func handleDateTimezone(_ string, config Config) (string, error) {
 	timezone := config.Replacement
 	if timezone == "" {
-		timezone = "America/Sao_Paulo"
+		timezone = defaultTimezone
 	}
 
 	location, err := time.LoadLocation(timezone)
 	if err != nil {
-		return "", fmt.Errorf("invalid timezone: %w", err)
+		return "", fmt.Errorf("%w: %w", ErrInvalidTimezone, err)
 	}
 
 	now := time.Now().In(location)
@@ -149,10 +169,10 @@ func (s *Service) handleDateTimezone(config persistence.SanitizationConfig) (str
 }
 
 // handleCustomRegex handles custom regex sanitization.
-func (s *Service) handleCustomRegex(input string, config persistence.SanitizationConfig) (string, error) {
+func handleCustomRegex(input string, config Config) (string, error) {
 	regexPattern, err := regexp.Compile(config.CustomRegex)
 	if err != nil {
-		return "", fmt.Errorf("invalid regex: %w", err)
+		return "", fmt.Errorf("%w: %w", ErrInvalidRegex, err)
 	}
 
 	if config.Replacement != "" {
@@ -165,21 +185,59 @@ func (s *Service) handleCustomRegex(input string, config persistence.Sanitizatio
 }
 
 // RegisterExtractor registers a custom text extractor.
-func (s *Service) RegisterExtractor(sanitizationType persistence.SanitizationType, extractor TextExtractor) {
+func (s *Service) RegisterExtractor(sanitizationType Type, extractor TextExtractor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unregister(sanitizationType)
 	s.extractors[sanitizationType] = extractor
 }
 
 // RegisterFormatter registers a custom formatter.
-func (s *Service) RegisterFormatter(sanitizationType persistence.SanitizationType, formatter Formatter) {
+func (s *Service) RegisterFormatter(sanitizationType Type, formatter Formatter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unregister(sanitizationType)
 	s.formatters[sanitizationType] = formatter
 }
 
 // RegisterValidator registers a custom validator.
-func (s *Service) RegisterValidator(sanitizationType persistence.SanitizationType, validator Validator) {
+func (s *Service) RegisterValidator(sanitizationType Type, validator Validator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unregister(sanitizationType)
 	s.validators[sanitizationType] = validator
 }
 
 // RegisterNormalizer registers a custom normalizer.
-func (s *Service) RegisterNormalizer(sanitizationType persistence.SanitizationType, normalizer Normalizer) {
+func (s *Service) RegisterNormalizer(sanitizationType Type, normalizer Normalizer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unregister(sanitizationType)
 	s.normalizers[sanitizationType] = normalizer
 }
+
+// RegisterHandler registers a handler that receives the whole configuration.
+func (s *Service) RegisterHandler(sanitizationType Type, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unregister(sanitizationType)
+	s.handlers[sanitizationType] = handler
+}
+
+// Unregister removes every handler registered for the type.
+// Built-in types stop being supported until registered again.
+func (s *Service) Unregister(sanitizationType Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unregister(sanitizationType)
+}
+
+// unregister removes any handler of the type so the new registration wins.
+// Callers must hold the write lock.
+func (s *Service) unregister(sanitizationType Type) {
+	delete(s.extractors, sanitizationType)
+	delete(s.formatters, sanitizationType)
+	delete(s.validators, sanitizationType)
+	delete(s.normalizers, sanitizationType)
+	delete(s.handlers, sanitizationType)
+}
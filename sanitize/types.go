@@ -0,0 +1,67 @@
+// Package sanitize provides types for data sanitization.
+package sanitize
+
+// Type defines predefined sanitization types.
+// persistence.SanitizationType is an alias of this type.
+type Type string
+
+const (
+	// Number extractors.
+	NumbersOnly  Type = "numbers_only" // Extract numbers only
+	LettersOnly  Type = "letters_only" // Extract letters only
+	Alphanumeric Type = "alphanumeric" // Extract letters and numbers
+
+	// Specific formatters.
+	CPF   Type = "cpf"   // Extract, verify and format CPF
+	CNPJ  Type = "cnpj"  // Extract, verify and format CNPJ (numeric or alphanumeric)
+	RG    Type = "rg"    // Extract and format RG
+	PIS   Type = "pis"   // Extract, verify and format PIS/PASEP/NIT
+	CEP   Type = "cep"   // Extract and format CEP
+	Phone Type = "phone" // Extract and format Brazilian phone
+
+	// International phone.
+	PhoneE164 Type = "phone_e164" // Normalize to E.164 (+5511987654321) using Config.Region for numbers without country code
+
+	// Monetary.
+	BRL Type = "monetary_brl" // Extract and format monetary value in reais
+
+	// Text normalizers.
+	NameCase   Type = "name_case"   // Convert to proper name format
+	UpperCase  Type = "uppercase"   // Convert to uppercase
+	LowerCase  Type = "lowercase"   // Convert to lowercase
+	TrimSpaces Type = "trim_spaces" // Remove extra spaces
+
+	// Email (simple validation).
+	Email Type = "email" // Validate email format
+
+	// Date with timezone.
+	DateTimezone Type = "get_date_timezone" // Extract date/time with configurable timezone
+
+	// Custom regex.
+	Custom Type = "custom" // Custom regex
+)
+
+// Types returns every built-in sanitization type.
+func Types() []Type {
+	return []Type{
+		NumbersOnly, LettersOnly, Alphanumeric,
+		CPF, CNPJ, RG, PIS, CEP, Phone,
+		PhoneE164,
+		BRL,
+		NameCase, UpperCase, LowerCase, TrimSpaces,
+		Email,
+		DateTimezone,
+		Custom,
+	}
+}
+
+// Config configures input data sanitization.
+// persistence.SanitizationConfig is an alias of this type.
+type Config struct {
+	Type        Type   `json:"type"`                  // Sanitization type
+	CustomRegex string `json:"customRegex,omitempty"` // Custom regex (when type=custom)
+	Replacement string `json:"replacement,omitempty"` // Replacement string (timezone when type=get_date_timezone)
+	Region      string `json:"region,omitempty"`      // Default phone region when type=phone_e164 (ISO code, default BR)
+	Description string `json:"description,omitempty"` // Sanitization description
+	StrictMode  bool   `json:"strictMode,omitempty"`  // Pipelines only: a failing step stops the pipeline instead of passing the value through unchanged
+}
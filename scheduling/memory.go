@@ -0,0 +1,360 @@
+package scheduling
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultTimezone fuso das unidades sem timezone configurado
+const DefaultTimezone = "America/Sao_Paulo"
+
+// TimeRange intervalo do dia no formato "HH:MM" (ex.: {"09:00", "12:00"})
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Unit unidade de atendimento
+type Unit struct {
+	ID       string   `json:"id"`
+	Timezone string   `json:"timezone,omitempty"` // IANA; vazio usa DefaultTimezone
+	Holidays []string `json:"holidays,omitempty"` // Datas fechadas "2006-01-02"
+}
+
+// Service serviço agendável
+type Service struct {
+	ID          string `json:"id"`
+	DurationMin int    `json:"duration_min"`
+}
+
+// Professional profissional com sua jornada semanal
+type Professional struct {
+	ID       string                       `json:"id"`
+	UnitID   string                       `json:"unit_id"`
+	Services []string                     `json:"services,omitempty"` // Vazio: atende todos os serviços
+	Hours    map[time.Weekday][]TimeRange `json:"hours"`              // Jornada por dia da semana (0 = domingo)
+	Breaks   []TimeRange                  `json:"breaks,omitempty"`   // Pausas diárias (ex.: almoço)
+}
+
+// minuteRange intervalo em minutos desde a meia-noite
+type minuteRange struct{ start, end int }
+
+// schedule jornada já interpretada de um profissional
+type schedule struct {
+	professional Professional
+	hours        map[time.Weekday][]minuteRange
+}
+
+// calendarState estado mutável compartilhado entre as cópias do calendário
+type calendarState struct {
+	mu       sync.Mutex
+	bookings []Slot
+	locks    map[string]heldLock
+}
+
+// heldLock trava com o slot travado (para detectar sobreposição com outros slots)
+type heldLock struct {
+	Lock
+	slot Slot
+}
+
+// MemoryCalendar agenda em memória: implementa Availability e Locker
+// Indicada para testes e bots com agenda pequena; não persiste entre reinícios
+type MemoryCalendar struct {
+	units     map[string]Unit
+	locations map[string]*time.Location
+	holidays  map[string]map[string]bool // unidade → datas fechadas
+	services  map[string]Service
+	schedules []schedule
+	step      time.Duration // Intervalo entre inícios de slots (0 = duração do serviço)
+	now       Clock
+	state     *calendarState
+}
+
+var (
+	_ Availability = (*MemoryCalendar)(nil)
+	_ Locker       = (*MemoryCalendar)(nil)
+)
+
+// NewMemoryCalendar cria a agenda validando timezones, datas e intervalos
+func NewMemoryCalendar(units []Unit, services []Service, professionals []Professional) (*MemoryCalendar, error) {
+	c := &MemoryCalendar{
+		units:     make(map[string]Unit, len(units)),
+		locations: make(map[string]*time.Location, len(units)),
+		holidays:  make(map[string]map[string]bool, len(units)),
+		services:  make(map[string]Service, len(services)),
+		now:       time.Now,
+		state:     &calendarState{locks: make(map[string]heldLock)},
+	}
+
+	for _, u := range units {
+		tz := u.Timezone
+		if tz == "" {
+			tz = DefaultTimezone
+		}
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("scheduling: unit %s: %w", u.ID, err)
+		}
+		c.units[u.ID], c.locations[u.ID] = u, loc
+		c.holidays[u.ID] = make(map[string]bool, len(u.Holidays))
+		for _, day := range u.Holidays {
+			if _, err := time.Parse(time.DateOnly, day); err != nil {
+				return nil, fmt.Errorf("scheduling: unit %s: holiday %q: %w", u.ID, day, err)
+			}
+			c.holidays[u.ID][day] = true
+		}
+	}
+
+	for _, s := range services {
+		if s.DurationMin <= 0 {
+			return nil, fmt.Errorf("scheduling: service %s: duration_min must be positive", s.ID)
+		}
+		c.services[s.ID] = s
+	}
+
+	for _, p := range professionals {
+		if _, ok := c.units[p.UnitID]; !ok {
+			return nil, fmt.Errorf("scheduling: professional %s: unknown unit %q", p.ID, p.UnitID)
+		}
+		breaks, err := parseRanges(p.Breaks)
+		if err != nil {
+			return nil, fmt.Errorf("scheduling: professional %s: breaks: %w", p.ID, err)
+		}
+		s := schedule{professional: p, hours: make(map[time.Weekday][]minuteRange)}
+		for day, ranges := range p.Hours {
+			parsed, err := parseRanges(ranges)
+			if err != nil {
+				return nil, fmt.Errorf("scheduling: professional %s: %s: %w", p.ID, day, err)
+			}
+			s.hours[day] = subtract(parsed, breaks)
+		}
+		c.schedules = append(c.schedules, s)
+	}
+	return c, nil
+}
+
+// WithClock define o relógio (horários no passado nunca são oferecidos)
+func (c *MemoryCalendar) WithClock(now Clock) *MemoryCalendar {
+	cp := *c
+	cp.now = now
+	return &cp
+}
+
+// WithStep define o intervalo entre inícios de slots (ex.: 15min); 0 usa a duração do serviço
+func (c *MemoryCalendar) WithStep(step time.Duration) *MemoryCalendar {
+	cp := *c
+	cp.step = step
+	return &cp
+}
+
+// FreeSlots horários livres na janela, em ordem cronológica (desempate pelo profissional)
+// Exclui feriados, pausas, horários passados, agendamentos e travas válidas
+func (c *MemoryCalendar) FreeSlots(_ context.Context, req Request) ([]Slot, error) {
+	service, ok := c.services[req.ServiceID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownService, req.ServiceID)
+	}
+	duration := time.Duration(service.DurationMin) * time.Minute
+	step := c.step
+	if step <= 0 {
+		step = duration
+	}
+
+	now := c.now()
+	from := req.From
+	if from.Before(now) {
+		from = now
+	}
+
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	var slots []Slot
+	for _, s := range c.schedules {
+		p := s.professional
+		if (req.UnitID != "" && p.UnitID != req.UnitID) ||
+			(req.ProfessionalID != "" && p.ID != req.ProfessionalID) ||
+			(len(p.Services) > 0 && !slices.Contains(p.Services, service.ID)) {
+			continue
+		}
+
+		loc := c.locations[p.UnitID]
+		start, end := from.In(loc), req.To.In(loc)
+		for day := midnight(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+			if c.holidays[p.UnitID][day.Format(time.DateOnly)] {
+				continue
+			}
+			for _, r := range s.hours[day.Weekday()] {
+				rangeEnd := day.Add(time.Duration(r.end) * time.Minute)
+				for t := day.Add(time.Duration(r.start) * time.Minute); !t.Add(duration).After(rangeEnd); t = t.Add(step) {
+					slot := Slot{UnitID: p.UnitID, ServiceID: service.ID, ProfessionalID: p.ID, Start: t, End: t.Add(duration)}
+					if slot.Start.Before(from) || slot.End.After(req.To) || c.busy(slot, now) {
+						continue
+					}
+					slots = append(slots, slot)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(slots, func(a, b Slot) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ProfessionalID, b.ProfessionalID)
+	})
+
+	if req.ProfessionalID == "" && req.PreferredProfessionalID != "" {
+		preferred := slices.DeleteFunc(slices.Clone(slots), func(s Slot) bool { return s.ProfessionalID != req.PreferredProfessionalID })
+		if len(preferred) > 0 {
+			return preferred, nil
+		}
+	}
+	return slots, nil
+}
+
+// Book confirma o agendamento; o slot não pode sobrepor agendamento nem trava
+// válida de outro owner (a trava do próprio owner é liberada ao confirmar)
+func (c *MemoryCalendar) Book(_ context.Context, slot Slot, owner string) error {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if c.booked(slot) || c.lockedByOther(slot, owner, c.now()) {
+		return fmt.Errorf("%w: %s", ErrSlotUnavailable, slot.Key())
+	}
+	if lock, ok := c.state.locks[slot.Key()]; ok && lock.Owner == owner {
+		delete(c.state.locks, slot.Key())
+	}
+	c.state.bookings = append(c.state.bookings, slot)
+	return nil
+}
+
+// Lock trava o slot por ttl para o owner (renova se o owner já tem a trava)
+// Falha com ErrSlotLocked se outro owner trava um slot sobreposto do mesmo profissional
+func (c *MemoryCalendar) Lock(_ context.Context, slot Slot, owner string, ttl time.Duration) (Lock, error) {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	now := c.now()
+	key := slot.Key()
+	if c.lockedByOther(slot, owner, now) {
+		return Lock{}, fmt.Errorf("%w: %s", ErrSlotLocked, key)
+	}
+	if c.booked(slot) {
+		return Lock{}, fmt.Errorf("%w: %s", ErrSlotUnavailable, key)
+	}
+
+	lock := Lock{Key: key, Owner: owner, ExpiresAt: now.Add(ttl)}
+	c.state.locks[key] = heldLock{Lock: lock, slot: slot}
+	return lock, nil
+}
+
+// Release libera a trava do owner; travas expiradas ou de outro owner retornam ErrLockNotHeld
+func (c *MemoryCalendar) Release(_ context.Context, key, owner string) error {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	lock, ok := c.state.locks[key]
+	if !ok || lock.Owner != owner || !c.now().Before(lock.ExpiresAt) {
+		return fmt.Errorf("%w: %s", ErrLockNotHeld, key)
+	}
+	delete(c.state.locks, key)
+	return nil
+}
+
+// busy indica se o slot conflita com agendamento ou trava válida (chamar com o mutex)
+func (c *MemoryCalendar) busy(slot Slot, now time.Time) bool {
+	return c.lockedByOther(slot, "", now) || c.booked(slot)
+}
+
+// lockedByOther indica sobreposição com trava válida de outro owner ("" considera
+// qualquer owner); travas expiradas são descartadas (chamar com o mutex)
+func (c *MemoryCalendar) lockedByOther(slot Slot, owner string, now time.Time) bool {
+	for key, lock := range c.state.locks {
+		switch {
+		case !now.Before(lock.ExpiresAt):
+			delete(c.state.locks, key)
+		case lock.Owner != owner && overlaps(lock.slot, slot):
+			return true
+		}
+	}
+	return false
+}
+
+// booked indica sobreposição com agendamento do mesmo profissional (chamar com o mutex)
+func (c *MemoryCalendar) booked(slot Slot) bool {
+	return slices.ContainsFunc(c.state.bookings, func(b Slot) bool { return overlaps(b, slot) })
+}
+
+// overlaps indica se os slots são do mesmo profissional na mesma unidade e se sobrepõem no tempo
+func overlaps(a, b Slot) bool {
+	return a.ProfessionalID == b.ProfessionalID && a.UnitID == b.UnitID &&
+		a.Start.Before(b.End) && b.Start.Before(a.End)
+}
+
+// parseRanges converte e valida intervalos "HH:MM"
+func parseRanges(ranges []TimeRange) ([]minuteRange, error) {
+	out := make([]minuteRange, 0, len(ranges))
+	for _, r := range ranges {
+		start, err := parseClock(r.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(r.End)
+		if err != nil {
+			return nil, err
+		}
+		if end <= start {
+			return nil, fmt.Errorf("range %s-%s ends before it starts", r.Start, r.End)
+		}
+		out = append(out, minuteRange{start, end})
+	}
+	slices.SortFunc(out, func(a, b minuteRange) int { return cmp.Compare(a.start, b.start) })
+	return out, nil
+}
+
+// parseClock "HH:MM" → minutos desde a meia-noite ("24:00" é aceito como fim do dia)
+func parseClock(s string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return h*60 + m, nil
+}
+
+// subtract remove as pausas dos intervalos de trabalho
+func subtract(ranges, breaks []minuteRange) []minuteRange {
+	out := ranges
+	for _, b := range breaks {
+		var next []minuteRange
+		for _, r := range out {
+			if b.end <= r.start || b.start >= r.end {
+				next = append(next, r)
+				continue
+			}
+			if b.start > r.start {
+				next = append(next, minuteRange{r.start, b.start})
+			}
+			if b.end < r.end {
+				next = append(next, minuteRange{b.end, r.end})
+			}
+		}
+		out = next
+	}
+	return out
+}
+
+// midnight início do dia no fuso de t
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
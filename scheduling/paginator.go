@@ -0,0 +1,270 @@
+package scheduling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/component"
+)
+
+// Saídas do componente slot_picker
+const (
+	OutputChosen   = "chosen"    // Usuário escolheu um horário
+	OutputNoSlots  = "no_slots"  // Nenhum horário livre na janela
+	OutputNextPage = "next_page" // Usuário pediu a próxima página
+	OutputPrevPage = "prev_page" // Usuário pediu a página anterior
+)
+
+// DefaultPageSize horários por página (mesmo default do component.SlotPicker)
+const DefaultPageSize = 9
+
+// weekdays abreviações pt-BR dos dias da semana (índice time.Weekday)
+var weekdays = [...]string{"Dom", "Seg", "Ter", "Qua", "Qui", "Sex", "Sáb"}
+
+// Page página de horários
+type Page struct {
+	Slots     []Slot `json:"slots"`
+	Number    int    `json:"number"`     // Página atual (1..PageCount)
+	PageCount int    `json:"page_count"` // Total de páginas
+	Total     int    `json:"total"`      // Total de horários livres
+	Output    string `json:"output,omitempty"`
+}
+
+// HasNext indica se há próxima página
+func (p Page) HasNext() bool { return p.Number < p.PageCount }
+
+// HasPrev indica se há página anterior
+func (p Page) HasPrev() bool { return p.Number > 1 }
+
+// Choice resposta do usuário à lista de horários
+type Choice struct {
+	Output string   `json:"output"`
+	Slot   *SlotRef `json:"slot,omitempty"` // Horário escolhido (chosen)
+	Page   int      `json:"page,omitempty"` // Página pedida (next_page/prev_page)
+}
+
+// RenderOptions textos da lista renderizada
+type RenderOptions struct {
+	Text          string            // Corpo da mensagem (padrão: "Escolha o melhor horário:")
+	ButtonText    string            // Botão que abre a lista (padrão: "Ver horários")
+	NavTitle      string            // Título da seção de navegação (padrão: "Mais horários")
+	NextLabel     string            // Item "próxima página" (padrão: "Próximos horários")
+	PrevLabel     string            // Item "página anterior" (padrão: "Horários anteriores")
+	Professionals map[string]string // Nome por ID do profissional, exibido na descrição do item
+}
+
+// Paginator divide os horários livres em páginas de listpicker agrupadas por dia
+type Paginator struct {
+	pageSize int
+	caps     adapter.Capabilities
+	loc      *time.Location
+}
+
+// NewPaginator cria o paginador com os limites de lista padrão do adapter
+// (use WithCapabilities para o canal); pageSize <= 0 usa DefaultPageSize
+func NewPaginator(pageSize int) *Paginator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &Paginator{pageSize: pageSize, caps: adapter.NewCaps(), loc: time.Local}
+}
+
+// PaginatorFromSpec paginador com o page_size do spec do component.SlotPicker
+func PaginatorFromSpec(spec component.ComponentSpec) *Paginator {
+	return NewPaginator(metaInt(spec.Meta, "page_size", DefaultPageSize))
+}
+
+// WithCapabilities define os limites de lista do canal (itens, seções, tamanhos de texto)
+func (p *Paginator) WithCapabilities(caps adapter.Capabilities) *Paginator {
+	cp := *p
+	cp.caps = caps
+	return &cp
+}
+
+// WithLocation define o fuso usado para agrupar e exibir os horários (fuso da unidade)
+func (p *Paginator) WithLocation(loc *time.Location) *Paginator {
+	cp := *p
+	if loc != nil {
+		cp.loc = loc
+	}
+	return &cp
+}
+
+// Paginate retorna a página pedida (1..N; valores fora do intervalo são ajustados)
+// Tudo cabendo numa lista, não há navegação; caso contrário cada página reserva
+// uma seção com até dois itens (anterior/próxima) e não passa do limite de seções
+func (p *Paginator) Paginate(slots []Slot, number int) Page {
+	page := Page{Total: len(slots)}
+	if page.Total == 0 {
+		page.Output = OutputNoSlots
+		return page
+	}
+
+	bounds := p.split(slots)
+	page.PageCount = len(bounds)
+	page.Number = min(max(number, 1), page.PageCount)
+	b := bounds[page.Number-1]
+	page.Slots = slots[b[0]:b[1]]
+	return page
+}
+
+// split limites [início, fim) de cada página
+func (p *Paginator) split(slots []Slot) [][2]int {
+	maxItems, maxSections := p.pageSize, 0
+	if limit := p.caps.MaxListItems; limit > 0 {
+		maxItems = min(maxItems, limit)
+	}
+	if limit := p.caps.MaxListSections; limit > 0 {
+		maxSections = limit
+	}
+	if len(slots) <= maxItems && (maxSections == 0 || p.days(slots) <= maxSections) {
+		return [][2]int{{0, len(slots)}}
+	}
+
+	// Com navegação: duas linhas e uma seção reservadas
+	if limit := p.caps.MaxListItems; limit > 2 {
+		maxItems = min(maxItems, limit-2)
+	}
+	if maxSections > 1 {
+		maxSections--
+	}
+
+	var bounds [][2]int
+	start, days := 0, 0
+	for i, s := range slots {
+		newDay := i == start || p.dayKey(s) != p.dayKey(slots[i-1])
+		if i > start && (i-start >= maxItems || (newDay && maxSections > 0 && days >= maxSections)) {
+			bounds = append(bounds, [2]int{start, i})
+			start, days, newDay = i, 0, true
+		}
+		if newDay {
+			days++
+		}
+	}
+	return append(bounds, [2]int{start, len(slots)})
+}
+
+// days quantidade de dias distintos (slots em ordem cronológica)
+func (p *Paginator) days(slots []Slot) int {
+	n := 0
+	for i, s := range slots {
+		if i == 0 || p.dayKey(s) != p.dayKey(slots[i-1]) {
+			n++
+		}
+	}
+	return n
+}
+
+func (p *Paginator) dayKey(s Slot) string {
+	return s.Start.In(p.loc).Format(time.DateOnly)
+}
+
+// Render monta a página como spec de listpicker: uma seção por dia ("Seg, 20/10"),
+// itens com a hora de início ("09:00") e ID Slot.Key, e uma seção de navegação
+// com adapter.PagePrevPayload/adapter.PageNextPayload quando há outras páginas
+func (p *Paginator) Render(page Page, opts RenderOptions) component.ComponentSpec {
+	opts = renderDefaults(opts)
+	titleLen, descLen := p.caps.MaxButtonTitleLen, p.caps.MaxDescriptionLen
+
+	var sections []component.SectionData
+	for i, s := range page.Slots {
+		if i == 0 || p.dayKey(s) != p.dayKey(page.Slots[i-1]) {
+			sections = append(sections, component.SectionData{Title: truncate(DayTitle(s.Start.In(p.loc)), titleLen)})
+		}
+		last := &sections[len(sections)-1]
+		last.Items = append(last.Items, component.ItemData{
+			ID:          s.Key(),
+			Title:       s.Start.In(p.loc).Format("15:04"),
+			Description: truncate(p.description(s, opts), descLen),
+		})
+	}
+
+	var nav []component.ItemData
+	if page.HasPrev() {
+		nav = append(nav, component.ItemData{ID: fmt.Sprintf("%s:%d", adapter.PagePrevPayload, page.Number-1), Title: truncate(opts.PrevLabel, titleLen)})
+	}
+	if page.HasNext() {
+		nav = append(nav, component.ItemData{ID: fmt.Sprintf("%s:%d", adapter.PageNextPayload, page.Number+1), Title: truncate(opts.NextLabel, titleLen)})
+	}
+	if len(nav) > 0 {
+		sections = append(sections, component.SectionData{Title: truncate(opts.NavTitle, titleLen), Items: nav})
+	}
+
+	return component.ComponentSpec{
+		Kind: "listpicker",
+		Text: &component.TextValue{Raw: opts.Text},
+		Meta: map[string]any{
+			"output_mode": "single",
+			"button_text": component.TextValue{Raw: truncate(opts.ButtonText, titleLen)},
+			"sections":    sections,
+			"page":        page.Number,
+			"page_count":  page.PageCount,
+		},
+	}
+}
+
+// description término e profissional: "até 09:30 · Ana"
+func (p *Paginator) description(s Slot, opts RenderOptions) string {
+	desc := "até " + s.End.In(p.loc).Format("15:04")
+	if name := opts.Professionals[s.ProfessionalID]; name != "" {
+		desc += " · " + name
+	}
+	return desc
+}
+
+func renderDefaults(opts RenderOptions) RenderOptions {
+	if opts.Text == "" {
+		opts.Text = "Escolha o melhor horário:"
+	}
+	if opts.ButtonText == "" {
+		opts.ButtonText = "Ver horários"
+	}
+	if opts.NavTitle == "" {
+		opts.NavTitle = "Mais horários"
+	}
+	if opts.NextLabel == "" {
+		opts.NextLabel = "Próximos horários"
+	}
+	if opts.PrevLabel == "" {
+		opts.PrevLabel = "Horários anteriores"
+	}
+	return opts
+}
+
+// DayTitle título da seção do dia: "Seg, 20/10"
+func DayTitle(t time.Time) string {
+	return weekdays[t.Weekday()] + ", " + t.Format("02/01")
+}
+
+// ParseChoice interpreta o payload escolhido na lista: navegação (next_page/prev_page
+// com a página pedida) ou chave de slot (chosen)
+func ParseChoice(payload string) (Choice, error) {
+	for prefix, output := range map[string]string{adapter.PageNextPayload: OutputNextPage, adapter.PagePrevPayload: OutputPrevPage} {
+		if rest, ok := strings.CutPrefix(payload, prefix+":"); ok {
+			n, err := strconv.Atoi(rest)
+			if err != nil || n < 1 {
+				return Choice{}, fmt.Errorf("scheduling: invalid page payload %q", payload)
+			}
+			return Choice{Output: output, Page: n}, nil
+		}
+	}
+
+	ref, err := ParseSlotKey(payload)
+	if err != nil {
+		return Choice{}, err
+	}
+	return Choice{Output: OutputChosen, Slot: &ref}, nil
+}
+
+// truncate corta em limit caracteres (runas) terminando com "…"; limit <= 0 não corta
+func truncate(s string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
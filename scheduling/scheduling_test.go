@@ -0,0 +1,196 @@
+package scheduling_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/adapter/whatsapp"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/runtime"
+	"github.com/AgendoCerto/lib-bot/scheduling"
+)
+
+var saoPaulo, _ = time.LoadLocation(scheduling.DefaultTimezone)
+
+func newCalendar(t *testing.T, now *time.Time) *scheduling.MemoryCalendar {
+	t.Helper()
+	workday := []scheduling.TimeRange{{Start: "09:00", End: "18:00"}}
+	cal, err := scheduling.NewMemoryCalendar(
+		[]scheduling.Unit{{ID: "paulista", Holidays: []string{"2026-10-20"}}},
+		[]scheduling.Service{{ID: "corte", DurationMin: 30}},
+		[]scheduling.Professional{{
+			ID:     "ana",
+			UnitID: "paulista",
+			Hours: map[time.Weekday][]scheduling.TimeRange{
+				time.Monday: workday, time.Tuesday: workday, time.Wednesday: workday, time.Thursday: workday, time.Friday: workday,
+			},
+			Breaks: []scheduling.TimeRange{{Start: "12:00", End: "13:00"}},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cal.WithClock(func() time.Time { return *now })
+}
+
+func freeSlots(t *testing.T, cal *scheduling.MemoryCalendar, now time.Time) []scheduling.Slot {
+	t.Helper()
+	req := scheduling.Request{UnitID: "paulista", ServiceID: "corte", From: now, To: now.Add(72 * time.Hour)}
+	slots, err := cal.FreeSlots(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return slots
+}
+
+func TestFreeSlots(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 10, 0, 0, saoPaulo) // segunda-feira
+	slots := freeSlots(t, newCalendar(t, &now), now)
+
+	// Seg: 10:30-11:30 e 13:00-17:30; Ter: feriado; Qua: dia inteiro; Qui: até 10:10
+	if len(slots) != 3+10+16+2 {
+		t.Fatalf("got %d slots", len(slots))
+	}
+	if got := slots[0].Start.Format("Mon 15:04"); got != "Mon 10:30" {
+		t.Errorf("first slot = %s", got)
+	}
+	for _, s := range slots {
+		if s.Start.Weekday() == time.Tuesday || s.Start.Hour() == 12 {
+			t.Errorf("slot on holiday or break: %s", s.Start)
+		}
+	}
+
+	if _, err := newCalendar(t, &now).FreeSlots(context.Background(), scheduling.Request{ServiceID: "barba"}); !errors.Is(err, scheduling.ErrUnknownService) {
+		t.Errorf("unknown service err = %v", err)
+	}
+}
+
+func TestLocks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 10, 10, 0, 0, saoPaulo)
+	cal := newCalendar(t, &now)
+	slot := freeSlots(t, cal, now)[0]
+
+	spec, _ := component.NewPaymentLink(nil).Spec(ctx, runtime.Context{})
+	ttl := scheduling.LockTTLFromSpec(spec)
+	if ttl != scheduling.DefaultLockTTL {
+		t.Fatalf("ttl = %s", ttl)
+	}
+
+	if _, err := cal.Lock(ctx, slot, "sessao-1", ttl); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cal.Lock(ctx, slot, "sessao-2", ttl); !errors.Is(err, scheduling.ErrSlotLocked) {
+		t.Fatalf("second owner err = %v", err)
+	}
+	if got := freeSlots(t, cal, now); got[0].Key() == slot.Key() {
+		t.Error("locked slot still offered")
+	}
+
+	now = now.Add(ttl + time.Second)
+	if _, err := cal.Lock(ctx, slot, "sessao-2", ttl); err != nil {
+		t.Fatalf("expired lock not taken over: %v", err)
+	}
+	if err := cal.Release(ctx, slot.Key(), "sessao-1"); !errors.Is(err, scheduling.ErrLockNotHeld) {
+		t.Errorf("release by former owner err = %v", err)
+	}
+	if err := cal.Book(ctx, slot, "sessao-2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cal.Lock(ctx, slot, "sessao-3", ttl); !errors.Is(err, scheduling.ErrSlotUnavailable) {
+		t.Errorf("lock of booked slot err = %v", err)
+	}
+}
+
+func TestOverlappingLocks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, saoPaulo) // segunda-feira
+	cal, err := scheduling.NewMemoryCalendar(
+		[]scheduling.Unit{{ID: "paulista"}},
+		[]scheduling.Service{{ID: "coloracao", DurationMin: 60}},
+		[]scheduling.Professional{{
+			ID:     "ana",
+			UnitID: "paulista",
+			Hours:  map[time.Weekday][]scheduling.TimeRange{time.Monday: {{Start: "09:00", End: "11:00"}}},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal = cal.WithClock(func() time.Time { return now }).WithStep(30 * time.Minute)
+	req := scheduling.Request{UnitID: "paulista", ServiceID: "coloracao", From: now, To: now.Add(12 * time.Hour)}
+	slots, _ := cal.FreeSlots(ctx, req)
+	if len(slots) != 3 { // 09:00, 09:30, 10:00
+		t.Fatalf("got %d slots", len(slots))
+	}
+	nine, nineThirty, ten := slots[0], slots[1], slots[2]
+
+	if _, err := cal.Lock(ctx, nine, "A", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	// 09:30 sobrepõe a trava de 09:00: não é oferecido nem pode ser travado ou agendado
+	if free, _ := cal.FreeSlots(ctx, req); len(free) != 1 || free[0].Key() != ten.Key() {
+		t.Errorf("free slots with 09:00 locked = %+v", free)
+	}
+	if _, err := cal.Lock(ctx, nineThirty, "B", time.Minute); !errors.Is(err, scheduling.ErrSlotLocked) {
+		t.Errorf("overlapping lock err = %v", err)
+	}
+	if err := cal.Book(ctx, nineThirty, "B"); !errors.Is(err, scheduling.ErrSlotUnavailable) {
+		t.Errorf("overlapping book err = %v", err)
+	}
+	if err := cal.Book(ctx, nine, "A"); err != nil {
+		t.Fatalf("owner book: %v", err)
+	}
+	if _, err := cal.Lock(ctx, nineThirty, "B", time.Minute); !errors.Is(err, scheduling.ErrSlotUnavailable) {
+		t.Errorf("lock overlapping booking err = %v", err)
+	}
+
+	// Trava expirada deixa de bloquear os slots sobrepostos
+	if _, err := cal.Lock(ctx, ten, "C", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	if err := cal.Book(ctx, ten, "D"); err != nil {
+		t.Errorf("book after lock expired: %v", err)
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 10, 0, 0, saoPaulo)
+	slots := freeSlots(t, newCalendar(t, &now), now)
+	p := scheduling.NewPaginator(9).WithCapabilities(whatsapp.New().Capabilities()).WithLocation(saoPaulo)
+
+	first := p.Paginate(slots, 1)
+	if len(first.Slots) != 8 || first.PageCount != 4 || first.HasPrev() || !first.HasNext() {
+		t.Fatalf("page 1 = %d slots of %d pages", len(first.Slots), first.PageCount)
+	}
+
+	second := p.Render(p.Paginate(slots, 2), scheduling.RenderOptions{Professionals: map[string]string{"ana": "Ana"}})
+	sections := second.Meta["sections"].([]component.SectionData)
+	if len(sections) != 3 || sections[0].Title != "Seg, 19/10" || sections[1].Title != "Qua, 21/10" {
+		t.Fatalf("sections = %+v", sections)
+	}
+	if item := sections[0].Items[0]; item.Title != "15:30" || item.Description != "até 16:00 · Ana" {
+		t.Errorf("item = %+v", item)
+	}
+	nav := sections[2].Items
+	if len(nav) != 2 || nav[0].ID != adapter.PagePrevPayload+":1" || nav[1].ID != adapter.PageNextPayload+":3" {
+		t.Errorf("nav = %+v", nav)
+	}
+
+	choice, err := scheduling.ParseChoice(nav[0].ID)
+	if err != nil || choice.Output != scheduling.OutputPrevPage || choice.Page != 1 {
+		t.Errorf("prev choice = %+v, %v", choice, err)
+	}
+	choice, err = scheduling.ParseChoice(sections[0].Items[0].ID)
+	if err != nil || choice.Output != scheduling.OutputChosen || !choice.Slot.Start.Equal(time.Date(2026, 10, 19, 15, 30, 0, 0, saoPaulo)) {
+		t.Errorf("slot choice = %+v, %v", choice, err)
+	}
+
+	if empty := p.Paginate(nil, 1); empty.Output != scheduling.OutputNoSlots {
+		t.Errorf("empty output = %q", empty.Output)
+	}
+}
@@ -0,0 +1,141 @@
+// Package scheduling implementa a lógica do componente slot_picker: consulta de
+// horários livres, trava temporária de slots (lock_slot_ttl_s do payment_link)
+// e paginação dos horários em listas agrupadas por dia
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/component"
+)
+
+// Erros de agenda
+var (
+	ErrSlotUnavailable = errors.New("scheduling: slot unavailable")
+	ErrSlotLocked      = errors.New("scheduling: slot locked by another session")
+	ErrLockNotHeld     = errors.New("scheduling: lock not held")
+	ErrInvalidSlotKey  = errors.New("scheduling: invalid slot key")
+	ErrUnknownService  = errors.New("scheduling: unknown service")
+)
+
+// Clock relógio injetável (time.Now por padrão)
+type Clock func() time.Time
+
+// DefaultLockTTL trava padrão do slot (mesmo default do component.PaymentLink)
+const DefaultLockTTL = 900 * time.Second
+
+// Slot horário livre de um profissional para um serviço
+type Slot struct {
+	UnitID         string    `json:"unit_id"`
+	ServiceID      string    `json:"service_id"`
+	ProfessionalID string    `json:"professional_id"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+}
+
+// Key identificador estável do slot, usado como payload na lista: unit|service|professional|unix
+func (s Slot) Key() string {
+	return strings.Join([]string{s.UnitID, s.ServiceID, s.ProfessionalID, strconv.FormatInt(s.Start.Unix(), 10)}, "|")
+}
+
+// SlotRef referência a um slot lida de uma chave (sem o horário de término)
+type SlotRef struct {
+	UnitID         string
+	ServiceID      string
+	ProfessionalID string
+	Start          time.Time
+}
+
+// ParseSlotKey interpreta a chave gerada por Slot.Key
+func ParseSlotKey(key string) (SlotRef, error) {
+	parts := strings.Split(key, "|")
+	if len(parts) != 4 {
+		return SlotRef{}, fmt.Errorf("%w: %q", ErrInvalidSlotKey, key)
+	}
+	unix, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return SlotRef{}, fmt.Errorf("%w: %q", ErrInvalidSlotKey, key)
+	}
+	return SlotRef{UnitID: parts[0], ServiceID: parts[1], ProfessionalID: parts[2], Start: time.Unix(unix, 0)}, nil
+}
+
+// Request consulta de horários livres
+type Request struct {
+	UnitID         string
+	ServiceID      string
+	ProfessionalID string // Vazio: qualquer profissional que atenda o serviço
+	From, To       time.Time
+
+	// PreferredProfessionalID com ProfessionalID vazio, retorna só os horários
+	// desse profissional quando ele tiver algum na janela (prefer_last_professional)
+	PreferredProfessionalID string
+}
+
+// LastChoice últimas escolhas do usuário, usadas pelas flags prefer_last_*
+type LastChoice struct {
+	ServiceID      string
+	ProfessionalID string
+}
+
+// RequestFromSpec monta a consulta a partir do spec do component.SlotPicker:
+// janela de window_h horas a partir de now; prefer_last_service completa o
+// serviço ausente e prefer_last_professional define o profissional preferido
+func RequestFromSpec(spec component.ComponentSpec, now time.Time, last LastChoice) Request {
+	str := func(key string) string { s, _ := spec.Meta[key].(string); return s }
+
+	req := Request{
+		UnitID:         str("unit_id"),
+		ServiceID:      str("service_id"),
+		ProfessionalID: str("professional_id"),
+		From:           now,
+		To:             now.Add(time.Duration(metaInt(spec.Meta, "window_h", 72)) * time.Hour),
+	}
+	if prefer, _ := spec.Meta["prefer_last_service"].(bool); prefer && req.ServiceID == "" {
+		req.ServiceID = last.ServiceID
+	}
+	if prefer, _ := spec.Meta["prefer_last_professional"].(bool); prefer && req.ProfessionalID == "" {
+		req.PreferredProfessionalID = last.ProfessionalID
+	}
+	return req
+}
+
+// LockTTLFromSpec trava do slot configurada no spec do component.PaymentLink (lock_slot_ttl_s)
+func LockTTLFromSpec(spec component.ComponentSpec) time.Duration {
+	return time.Duration(metaInt(spec.Meta, "lock_slot_ttl_s", int(DefaultLockTTL/time.Second))) * time.Second
+}
+
+// metaInt lê inteiro do meta (int no spec gerado, float64 quando decodificado de JSON)
+func metaInt(meta map[string]any, key string, def int) int {
+	switch v := meta[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return def
+}
+
+// Availability fonte de horários livres
+type Availability interface {
+	FreeSlots(ctx context.Context, req Request) ([]Slot, error)
+}
+
+// Lock trava temporária de um slot
+type Lock struct {
+	Key       string    `json:"key"`
+	Owner     string    `json:"owner"` // Sessão/usuário que segura a trava
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Locker trava slots enquanto o usuário conclui o pagamento
+// Lock renova a trava quando o mesmo owner já a possui e retorna ErrSlotLocked
+// quando outro owner tem uma trava válida
+type Locker interface {
+	Lock(ctx context.Context, slot Slot, owner string, ttl time.Duration) (Lock, error)
+	Release(ctx context.Context, key, owner string) error
+}
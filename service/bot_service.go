@@ -8,6 +8,7 @@ import (
 
 	"github.com/AgendoCerto/lib-bot/flow"
 	"github.com/AgendoCerto/lib-bot/io"
+	"github.com/AgendoCerto/lib-bot/templates"
 )
 
 // BotService é o serviço unificado que manipula designs diretamente (sem patches)
@@ -15,6 +16,7 @@ import (
 type BotService struct {
 	validation *ValidationService
 	store      *StoreService
+	templates  *templates.Library
 }
 
 // BotInfo representa informações básicas de um bot
@@ -47,9 +49,44 @@ func NewBotService() *BotService {
 	return &BotService{
 		validation: NewValidationService(),
 		store:      NewStoreService(),
+		templates:  templates.DefaultLibrary(),
 	}
 }
 
+// Templates retorna a biblioteca de blueprints (permite registrar templates customizados)
+func (sbs *BotService) Templates() *templates.Library {
+	return sbs.templates
+}
+
+// CreateBotFromTemplate cria um bot a partir de um blueprint parametrizado
+// O design gerado é validado; o bot só é salvo se não houver issues de severidade "error".
+// O resultado da validação (com as issues) é sempre retornado quando o template é instanciado.
+func (sbs *BotService) CreateBotFromTemplate(ctx context.Context, botID, templateName string, params map[string]string, adapterName string) (*ValidationResult, error) {
+	if adapterName == "" {
+		adapterName = "whatsapp"
+	}
+
+	design, err := sbs.templates.Instantiate(templateName, botID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := sbs.validation.ValidateDesign(ctx, design, adapterName)
+	if err != nil {
+		return result, fmt.Errorf("erro na validação: %w", err)
+	}
+
+	if !result.Valid {
+		return result, fmt.Errorf("template %s gerou design inválido: %d issues", templateName, len(result.Issues))
+	}
+
+	if _, err := sbs.store.Save(ctx, botID, design); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
 // CreateBot cria um novo bot
 func (sbs *BotService) CreateBot(ctx context.Context, botID, name, adapterName string) error {
 	if adapterName == "" {
@@ -43,6 +43,21 @@ func NewValidationService() *ValidationService {
 	return service
 }
 
+// ValidateChannels compila o design para todos os canais de bot.channels com os adapters registrados
+func (s *ValidationService) ValidateChannels(ctx context.Context, design io.DesignDoc) (*compile.Bundle, error) {
+	bundle, err := s.compiler.CompileAll(ctx, design, s.registry, adapterMap(s.adapters))
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// adapterMap expõe o mapa de adapters do serviço como adapter.Provider
+type adapterMap map[string]adapter.Adapter
+
+func (m adapterMap) Get(name string) (adapter.Adapter, bool) { a, ok := m[name]; return a, ok }
+func (m adapterMap) Register(a adapter.Adapter)              { m[a.Name()] = a }
+
 // RegisterAdapter registra um adapter no serviço
 func (s *ValidationService) RegisterAdapter(name string, adapter adapter.Adapter) {
 	s.adapters[name] = adapter
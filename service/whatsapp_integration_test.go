@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
+
+	"github.com/AgendoCerto/lib-bot/templates"
 )
 
 // TestWhatsAppValidation testa validações WhatsApp integradas
@@ -156,3 +159,52 @@ func TestWhatsAppValidation(t *testing.T) {
 		}
 	})
 }
+
+// TestCreateBotFromTemplate testa a criação de bots a partir dos blueprints
+func TestCreateBotFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	svc := NewBotService()
+
+	t.Run("Parâmetros inválidos não salvam o bot", func(t *testing.T) {
+		cases := []struct {
+			template string
+			params   map[string]string
+			want     error
+		}{
+			{"inexistente", nil, templates.ErrTemplateNotFound},
+			{"nps_survey", nil, templates.ErrMissingParam},
+			{"nps_survey", map[string]string{"business_name": "Acme", "cor": "azul"}, templates.ErrUnknownParam},
+		}
+		for _, c := range cases {
+			result, err := svc.CreateBotFromTemplate(ctx, "tpl-invalid", c.template, c.params, "")
+			if !errors.Is(err, c.want) || result != nil {
+				t.Errorf("%s %v: result %+v, err = %v, want %v", c.template, c.params, result, err, c.want)
+			}
+		}
+		if exists, _ := svc.store.Exists(ctx, "tpl-invalid"); exists {
+			t.Error("bot salvo apesar do erro")
+		}
+	})
+
+	t.Run("Valores com aspas e quebras de linha", func(t *testing.T) {
+		thanks := "Obrigado, \"cliente\"!\nAté ${logo}"
+		result, err := svc.CreateBotFromTemplate(ctx, "tpl-nps", "nps_survey", map[string]string{"business_name": `Acme "Centro"`, "thanks_text": thanks}, "")
+		if err != nil || result == nil || !result.Valid {
+			t.Fatalf("CreateBotFromTemplate: result %+v, err %v", result, err)
+		}
+		design, err := svc.store.Load(ctx, "tpl-nps")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if design.Bot.ID != "tpl-nps" {
+			t.Errorf("bot id = %q", design.Bot.ID)
+		}
+		for _, n := range design.Graph.Nodes {
+			if n.ID == "thanks" {
+				if text, _ := design.ResolveProps(n)["text"].(string); text != thanks {
+					t.Errorf("thanks text = %q, want %q", text, thanks)
+				}
+			}
+		}
+	})
+}
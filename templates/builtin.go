@@ -0,0 +1,124 @@
+package templates
+
+// AppointmentBooking blueprint de agendamento com escolha de horário (slot_picker) e pagamento (payment_link)
+func AppointmentBooking() Blueprint {
+	return Blueprint{
+		Name:        "appointment_booking",
+		Title:       "Agendamento com pagamento",
+		Description: "Usuário escolhe um horário disponível e paga o sinal para confirmar a reserva",
+		Params: []Param{
+			{Name: "business_name", Description: "Nome do estabelecimento", Required: true},
+			{Name: "unit_id", Description: "ID da unidade", Required: true},
+			{Name: "service_id", Description: "ID do serviço agendado", Required: true},
+			{Name: "amount", Description: "Valor do sinal (ex: 50.00)", Default: "0.00"},
+			{Name: "currency", Description: "Moeda do pagamento", Default: "BRL"},
+		},
+		Design: `{
+  "schema": "flowkit/1.0",
+  "bot": {"id": "appointment_booking", "channels": ["whatsapp"]},
+  "version": {"id": "v1.0.0", "status": "development"},
+  "entries": [{"kind": "global_start", "target": "welcome"}],
+  "variables": {"context": ["slot_id"], "state": ["user_name"], "global": {}},
+  "graph": {
+    "nodes": [
+      {"id": "welcome", "kind": "message", "title": "Boas-vindas",
+       "props": {"text": "Olá! Vamos agendar seu horário na ${business_name}."}, "outputs": ["complete"]},
+      {"id": "pick_slot", "kind": "slot_picker", "title": "Escolha do horário",
+       "props": {"unit_id": "${unit_id}", "service_id": "${service_id}", "window_h": 72, "page_size": 9},
+       "outputs": ["chosen", "no_slots"]},
+      {"id": "payment", "kind": "payment_link", "title": "Pagamento do sinal",
+       "props": {"amount": "${amount}", "currency": "${currency}", "expires_in_min": 15, "lock_slot_ttl_s": 900},
+       "outputs": ["paid", "expired", "failed"]},
+      {"id": "confirmed", "kind": "message", "title": "Confirmado", "final": true,
+       "props": {"text": "Pagamento confirmado! Seu horário na ${business_name} está reservado."}, "outputs": ["complete"]},
+      {"id": "no_slots", "kind": "message", "title": "Sem horários", "final": true,
+       "props": {"text": "Não encontramos horários disponíveis nos próximos dias. Tente novamente mais tarde."}, "outputs": ["complete"]},
+      {"id": "payment_failed", "kind": "message", "title": "Pagamento não concluído", "final": true,
+       "props": {"text": "Não conseguimos confirmar o pagamento e o horário foi liberado."}, "outputs": ["complete"]}
+    ],
+    "edges": [
+      {"from": "welcome", "to": "pick_slot", "label": "complete"},
+      {"from": "pick_slot", "to": "payment", "label": "chosen"},
+      {"from": "pick_slot", "to": "no_slots", "label": "no_slots", "priority": 1},
+      {"from": "payment", "to": "confirmed", "label": "paid"},
+      {"from": "payment", "to": "payment_failed", "label": "expired", "priority": 1},
+      {"from": "payment", "to": "payment_failed", "label": "failed", "priority": 2}
+    ]
+  },
+  "props": {}
+}`,
+	}
+}
+
+// NPSSurvey blueprint de pesquisa NPS usando o componente feedback
+func NPSSurvey() Blueprint {
+	return Blueprint{
+		Name:        "nps_survey",
+		Title:       "Pesquisa NPS",
+		Description: "Pergunta de 0 a 10 sobre a probabilidade de recomendação",
+		Params: []Param{
+			{Name: "business_name", Description: "Nome do estabelecimento", Required: true},
+			{Name: "thanks_text", Description: "Mensagem de agradecimento", Default: "Obrigado pela sua avaliação!"},
+		},
+		Design: `{
+  "schema": "flowkit/1.0",
+  "bot": {"id": "nps_survey", "channels": ["whatsapp"]},
+  "version": {"id": "v1.0.0", "status": "development"},
+  "entries": [{"kind": "global_start", "target": "ask_score"}],
+  "variables": {"context": [], "state": ["nps_score"], "global": {}},
+  "graph": {
+    "nodes": [
+      {"id": "ask_score", "kind": "feedback", "title": "Nota NPS",
+       "props": {"text": "De 0 a 10, quanto você recomendaria a ${business_name} para um amigo?", "scale": "0-10",
+                 "persistence": {"enabled": true, "scope": "state", "key": "nps_score"}},
+       "outputs": ["submitted"]},
+      {"id": "thanks", "kind": "message", "title": "Agradecimento", "final": true,
+       "props": {"text": "${thanks_text}"}, "outputs": ["complete"]}
+    ],
+    "edges": [
+      {"from": "ask_score", "to": "thanks", "label": "submitted"}
+    ]
+  },
+  "props": {}
+}`,
+	}
+}
+
+// LGPDConsent blueprint de consentimento LGPD usando terms_gate
+func LGPDConsent() Blueprint {
+	return Blueprint{
+		Name:        "lgpd_consent",
+		Title:       "Consentimento LGPD",
+		Description: "Solicita aceite dos termos de uso/privacidade antes de seguir o atendimento",
+		Params: []Param{
+			{Name: "terms_version", Description: "Versão dos termos (ex: v2)", Required: true},
+			{Name: "terms_url", Description: "URL da política de privacidade", Required: true},
+			{Name: "business_name", Description: "Nome do estabelecimento", Required: true},
+		},
+		Design: `{
+  "schema": "flowkit/1.0",
+  "bot": {"id": "lgpd_consent", "channels": ["whatsapp"]},
+  "version": {"id": "v1.0.0", "status": "development"},
+  "entries": [{"kind": "global_start", "target": "consent"}],
+  "variables": {"context": [], "state": [], "global": {}},
+  "graph": {
+    "nodes": [
+      {"id": "consent", "kind": "terms_gate", "title": "Termos LGPD",
+       "props": {"version_id": "${terms_version}",
+                 "text": "Para continuar, a ${business_name} precisa do seu aceite à política de privacidade: ${terms_url}"},
+       "outputs": ["accepted", "rejected", "skipped"]},
+      {"id": "welcome", "kind": "message", "title": "Atendimento", "final": true,
+       "props": {"text": "Obrigado! Como podemos ajudar?"}, "outputs": ["complete"]},
+      {"id": "rejected", "kind": "message", "title": "Recusado", "final": true,
+       "props": {"text": "Sem o aceite não podemos continuar o atendimento por aqui."}, "outputs": ["complete"]}
+    ],
+    "edges": [
+      {"from": "consent", "to": "welcome", "label": "accepted"},
+      {"from": "consent", "to": "welcome", "label": "skipped", "priority": 1},
+      {"from": "consent", "to": "rejected", "label": "rejected", "priority": 2}
+    ]
+  },
+  "props": {}
+}`,
+	}
+}
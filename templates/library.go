@@ -0,0 +1,143 @@
+// Package templates fornece blueprints parametrizados de io.DesignDoc para scaffolding de bots
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// Erros estáticos para tratamento pelo chamador
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrMissingParam     = errors.New("missing required template parameter")
+	ErrUnknownParam     = errors.New("unknown template parameter")
+	ErrUndeclaredParam  = errors.New("undeclared template placeholder")
+)
+
+// placeholderPattern identifica placeholders de parâmetros: ${nome_do_parametro}
+// (não conflita com Liquid, que usa {{ }} e {% %})
+var placeholderPattern = regexp.MustCompile(`\$\{([a-zA-Z0-9_]+)\}`)
+
+// Param declara um parâmetro substituído na criação do bot
+type Param struct {
+	Name        string `json:"name"`                  // Nome usado no placeholder ${name}
+	Description string `json:"description,omitempty"` // Descrição para o editor
+	Default     string `json:"default,omitempty"`     // Valor padrão (quando não obrigatório)
+	Required    bool   `json:"required,omitempty"`    // Se deve ser informado obrigatoriamente
+}
+
+// Blueprint representa um template nomeado de design
+// Design é o JSON do io.DesignDoc com placeholders ${param} em valores string
+type Blueprint struct {
+	Name        string  `json:"name"`        // Identificador do template (ex: "appointment_booking")
+	Title       string  `json:"title"`       // Título para exibição
+	Description string  `json:"description"` // Descrição do caso de uso
+	Params      []Param `json:"params"`      // Parâmetros aceitos
+	Design      string  `json:"design"`      // DesignDoc com placeholders
+}
+
+// Library registro de blueprints disponíveis
+type Library struct{ items map[string]Blueprint }
+
+// NewLibrary cria uma biblioteca vazia
+func NewLibrary() *Library { return &Library{items: map[string]Blueprint{}} }
+
+// DefaultLibrary cria uma biblioteca com os blueprints padrão
+func DefaultLibrary() *Library {
+	lib := NewLibrary()
+	lib.Register(AppointmentBooking())
+	lib.Register(NPSSurvey())
+	lib.Register(LGPDConsent())
+	return lib
+}
+
+// Register registra (ou substitui) um blueprint
+func (l *Library) Register(b Blueprint) { l.items[b.Name] = b }
+
+// Get busca blueprint por nome
+func (l *Library) Get(name string) (Blueprint, bool) { b, ok := l.items[name]; return b, ok }
+
+// List retorna os blueprints ordenados por nome
+func (l *Library) List() []Blueprint {
+	out := make([]Blueprint, 0, len(l.items))
+	for _, b := range l.items {
+		out = append(out, b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Instantiate gera um DesignDoc a partir do blueprint, substituindo parâmetros
+// botID sobrescreve o ID do bot do template
+func (l *Library) Instantiate(name, botID string, params map[string]string) (io.DesignDoc, error) {
+	b, ok := l.items[name]
+	if !ok {
+		return io.DesignDoc{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return b.Instantiate(botID, params)
+}
+
+// Instantiate gera um DesignDoc substituindo os parâmetros do blueprint
+func (b Blueprint) Instantiate(botID string, params map[string]string) (io.DesignDoc, error) {
+	values, err := b.resolveParams(params)
+	if err != nil {
+		return io.DesignDoc{}, err
+	}
+
+	// Placeholders sem parâmetro declarado são verificados no design original:
+	// valores informados podem conter "${...}" literal
+	for _, m := range placeholderPattern.FindAllStringSubmatch(b.Design, -1) {
+		if _, ok := values[m[1]]; !ok {
+			return io.DesignDoc{}, fmt.Errorf("template %s: %w: %s", b.Name, ErrUndeclaredParam, m[0])
+		}
+	}
+
+	// Substitui dentro do JSON com escape, para que valores com aspas/quebras não quebrem o documento
+	raw := placeholderPattern.ReplaceAllStringFunc(b.Design, func(m string) string {
+		escaped, _ := json.Marshal(values[placeholderPattern.FindStringSubmatch(m)[1]])
+		return string(escaped[1 : len(escaped)-1]) // remove as aspas externas
+	})
+
+	codec := io.JSONCodec{}
+	design, err := codec.DecodeDesign([]byte(raw))
+	if err != nil {
+		return io.DesignDoc{}, fmt.Errorf("template %s: invalid design: %w", b.Name, err)
+	}
+
+	if botID != "" {
+		design.Bot.ID = botID
+	}
+
+	return design, nil
+}
+
+// resolveParams combina valores informados com defaults e valida obrigatórios/desconhecidos
+func (b Blueprint) resolveParams(params map[string]string) (map[string]string, error) {
+	declared := make(map[string]bool, len(b.Params))
+	values := make(map[string]string, len(b.Params))
+
+	for _, p := range b.Params {
+		declared[p.Name] = true
+		if v, ok := params[p.Name]; ok && v != "" {
+			values[p.Name] = v
+			continue
+		}
+		if p.Required {
+			return nil, fmt.Errorf("%w: %s", ErrMissingParam, p.Name)
+		}
+		values[p.Name] = p.Default
+	}
+
+	for name := range params {
+		if !declared[name] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownParam, name)
+		}
+	}
+
+	return values, nil
+}
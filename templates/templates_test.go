@@ -0,0 +1,85 @@
+package templates_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/feedback"
+	"github.com/AgendoCerto/lib-bot/templates"
+)
+
+func TestNPSSurveyScale(t *testing.T) {
+	design, err := templates.NPSSurvey().Instantiate("acme_nps", map[string]string{"business_name": "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range design.Graph.Nodes {
+		if n.ID != "ask_score" {
+			continue
+		}
+		props := design.ResolveProps(n)
+		text, _ := props["text"].(string)
+		scale, _ := props["scale"].(string)
+		if !strings.HasPrefix(text, "De 0 a 10") || feedback.Scale(scale) != feedback.ScaleNPS {
+			t.Fatalf("ask_score text %q with scale %q", text, scale)
+		}
+		if a, err := feedback.Parse("0", feedback.Scale(scale)); err != nil || a.Score != 0 {
+			t.Errorf("Parse(0) = %+v, %v", a, err)
+		}
+		return
+	}
+	t.Fatal("ask_score node not found")
+}
+
+func TestInstantiateErrors(t *testing.T) {
+	lib := templates.DefaultLibrary()
+	lib.Register(templates.Blueprint{
+		Name:   "broken",
+		Params: []templates.Param{{Name: "name", Default: "x"}},
+		Design: `{"schema": "flowkit/1.0", "bot": {"id": "${name}"}, "props": {"text": "${other}"}}`,
+	})
+
+	cases := []struct {
+		name     string
+		template string
+		params   map[string]string
+		want     error
+	}{
+		{name: "not found", template: "nope", want: templates.ErrTemplateNotFound},
+		{name: "missing", template: "nps_survey", params: map[string]string{}, want: templates.ErrMissingParam},
+		{name: "empty required", template: "nps_survey", params: map[string]string{"business_name": ""}, want: templates.ErrMissingParam},
+		{name: "unknown", template: "nps_survey", params: map[string]string{"business_name": "Acme", "color": "red"}, want: templates.ErrUnknownParam},
+		{name: "undeclared", template: "broken", want: templates.ErrUndeclaredParam},
+	}
+	for _, c := range cases {
+		if _, err := lib.Instantiate(c.template, "bot", c.params); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestInstantiateEscapesValues(t *testing.T) {
+	thanks := "Valeu \"demais\"!\nVolte sempre ${business_name}"
+	design, err := templates.NPSSurvey().Instantiate("acme_nps", map[string]string{"business_name": `Acme "Centro"`, "thanks_text": thanks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if design.Bot.ID != "acme_nps" {
+		t.Errorf("bot id = %q", design.Bot.ID)
+	}
+	for _, n := range design.Graph.Nodes {
+		text, _ := design.ResolveProps(n)["text"].(string)
+		switch n.ID {
+		case "ask_score":
+			if !strings.Contains(text, `a Acme "Centro" para`) {
+				t.Errorf("ask_score text = %q", text)
+			}
+		case "thanks":
+			// "${...}" dentro de um valor é texto literal, não placeholder
+			if text != thanks {
+				t.Errorf("thanks text = %q, want %q", text, thanks)
+			}
+		}
+	}
+}
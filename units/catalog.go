@@ -0,0 +1,164 @@
+// Package units busca unidades próximas do usuário para o componente unit_finder:
+// catálogo em memória, ordenação por distância (haversine) ou nome, expansão de raio,
+// paginação e renderização da página como listpicker dentro dos limites do canal
+package units
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/geo"
+)
+
+// Unit unidade de atendimento do catálogo
+type Unit struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Address   string            `json:"address,omitempty"`
+	Lat       float64           `json:"lat"`
+	Lng       float64           `json:"lng"`
+	Preferred bool              `json:"preferred,omitempty"` // Destacada para todos os usuários (ex.: matriz)
+	Metadata  map[string]string `json:"metadata,omitempty"`  // Colunas extras do CSV / dados livres
+}
+
+// Coordinates posição da unidade
+func (u Unit) Coordinates() geo.Coordinates {
+	return geo.Coordinates{Lat: u.Lat, Lng: u.Lng}
+}
+
+// Catalog catálogo imutável de unidades carregado em memória
+type Catalog struct {
+	units []Unit
+	byID  map[string]int
+}
+
+// NewCatalog cria o catálogo; IDs devem ser únicos e não vazios
+func NewCatalog(units []Unit) (*Catalog, error) {
+	c := &Catalog{units: make([]Unit, 0, len(units)), byID: make(map[string]int, len(units))}
+	for i, u := range units {
+		if u.ID == "" {
+			return nil, fmt.Errorf("units: unit %d has no id", i)
+		}
+		if _, dup := c.byID[u.ID]; dup {
+			return nil, fmt.Errorf("units: duplicate unit id %q", u.ID)
+		}
+		c.byID[u.ID] = len(c.units)
+		c.units = append(c.units, u)
+	}
+	return c, nil
+}
+
+// LoadCatalog carrega o catálogo de um arquivo .json (array de Unit) ou .csv
+func LoadCatalog(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var list []Unit
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.NewDecoder(f).Decode(&list)
+	case ".csv":
+		list, err = ReadCSV(f)
+	default:
+		return nil, fmt.Errorf("units: unsupported catalog format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("units: load %s: %w", path, err)
+	}
+	return NewCatalog(list)
+}
+
+// ReadCSV lê unidades de um CSV com cabeçalho
+// Colunas obrigatórias: id, name, lat, lng; opcionais: address, preferred (true/false/1/0)
+// Demais colunas vão para Metadata
+func ReadCSV(r io.Reader) ([]Unit, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		columns[header[i]] = i
+	}
+	for _, required := range []string{"id", "name", "lat", "lng"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var list []Unit
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		u := Unit{Metadata: map[string]string{}}
+		for i, value := range record {
+			if i >= len(header) {
+				break
+			}
+			value = strings.TrimSpace(value)
+			switch header[i] {
+			case "id":
+				u.ID = value
+			case "name":
+				u.Name = value
+			case "address":
+				u.Address = value
+			case "lat":
+				if u.Lat, err = strconv.ParseFloat(value, 64); err != nil {
+					return nil, fmt.Errorf("line %d: lat: %w", line, err)
+				}
+			case "lng":
+				if u.Lng, err = strconv.ParseFloat(value, 64); err != nil {
+					return nil, fmt.Errorf("line %d: lng: %w", line, err)
+				}
+			case "preferred":
+				u.Preferred = value == "1" || strings.EqualFold(value, "true")
+			default:
+				if value != "" {
+					u.Metadata[header[i]] = value
+				}
+			}
+		}
+		if len(u.Metadata) == 0 {
+			u.Metadata = nil
+		}
+		list = append(list, u)
+	}
+}
+
+// Len quantidade de unidades
+func (c *Catalog) Len() int { return len(c.units) }
+
+// Units cópia das unidades na ordem do catálogo
+func (c *Catalog) Units() []Unit {
+	return append([]Unit(nil), c.units...)
+}
+
+// Get busca uma unidade pelo ID
+func (c *Catalog) Get(id string) (Unit, bool) {
+	i, ok := c.byID[id]
+	if !ok {
+		return Unit{}, false
+	}
+	return c.units[i], true
+}
@@ -0,0 +1,263 @@
+package units
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/geo"
+	"github.com/AgendoCerto/lib-bot/persistence"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Saídas do componente unit_finder
+const (
+	OutputSelected  = "selected"   // Usuário escolheu uma unidade
+	OutputNoResults = "no_results" // Nenhuma unidade no raio (mesmo após expandir)
+	OutputMore      = "more"       // Usuário pediu a próxima página
+)
+
+// Ordenações suportadas (prop sort)
+const (
+	SortDistance = "distance"
+	SortName     = "name"
+)
+
+// Config configuração de busca (mesmas props e defaults do component.UnitFinder)
+type Config struct {
+	RadiusKmDefault      float64
+	RadiusKmExpandOnFail float64 // 0 desativa a expansão
+	PageSize             int
+	Sort                 string // distance | name
+	ShowPreferredFirst   bool
+	AutoPersist          *persistence.Config // Onde gravar o ID da unidade escolhida
+}
+
+// DefaultConfig defaults do component.UnitFinder
+func DefaultConfig() Config {
+	return Config{
+		RadiusKmDefault:      8,
+		RadiusKmExpandOnFail: 20,
+		PageSize:             10,
+		Sort:                 SortDistance,
+		ShowPreferredFirst:   true,
+	}
+}
+
+// ConfigFromSpec lê a configuração do spec gerado pelo component.UnitFinder
+// Campos ausentes mantêm os defaults
+func ConfigFromSpec(spec component.ComponentSpec) Config {
+	cfg := DefaultConfig()
+	if v, ok := spec.Meta["radius_km_default"].(float64); ok {
+		cfg.RadiusKmDefault = v
+	}
+	if v, ok := spec.Meta["radius_km_expand_on_fail"].(float64); ok {
+		cfg.RadiusKmExpandOnFail = v
+	}
+	switch v := spec.Meta["page_size"].(type) {
+	case int:
+		cfg.PageSize = v
+	case float64: // spec decodificado de JSON
+		cfg.PageSize = int(v)
+	}
+	if v, ok := spec.Meta["sort"].(string); ok {
+		cfg.Sort = v
+	}
+	if v, ok := spec.Meta["show_preferred_first"].(bool); ok {
+		cfg.ShowPreferredFirst = v
+	}
+	if spec.Persistence != nil && spec.Persistence.Enabled {
+		cfg.AutoPersist = spec.Persistence
+	}
+	return cfg
+}
+
+// Query busca de um usuário
+type Query struct {
+	Origin       geo.Coordinates // Posição do usuário (location_capture/geo_resolve)
+	Page         int             // Página desejada (1..N; 0 = primeira)
+	PreferredIDs []string        // Unidades preferidas do usuário (ex.: última unidade usada)
+}
+
+// Result unidade encontrada com sua distância
+type Result struct {
+	Unit       Unit    `json:"unit"`
+	DistanceKm float64 `json:"distance_km"`
+	Preferred  bool    `json:"preferred,omitempty"`
+}
+
+// Page página de resultados
+type Page struct {
+	Results   []Result `json:"results"`
+	Number    int      `json:"number"`     // Página atual (1..PageCount)
+	PageCount int      `json:"page_count"` // Total de páginas
+	Total     int      `json:"total"`      // Total de unidades no raio
+	RadiusKm  float64  `json:"radius_km"`  // Raio efetivamente usado
+	Expanded  bool     `json:"expanded"`   // Raio foi expandido por falta de resultados
+	Output    string   `json:"output,omitempty"`
+}
+
+// HasNext indica se há próxima página
+func (p Page) HasNext() bool { return p.Number < p.PageCount }
+
+// Selection resultado da resposta do usuário à lista
+type Selection struct {
+	Output   string `json:"output"`
+	Unit     *Unit  `json:"unit,omitempty"`      // Unidade escolhida (selected)
+	NextPage int    `json:"next_page,omitempty"` // Página pedida (more)
+	Write    *Write `json:"write,omitempty"`     // Gravação do auto_persist
+	Error    string `json:"error,omitempty"`
+}
+
+// Write gravação do ID da unidade escolhida
+type Write struct {
+	Scope persistence.Scope `json:"scope"`
+	Key   string            `json:"key"`
+	Value string            `json:"value"`
+}
+
+// Finder serviço de busca sobre um catálogo
+type Finder struct {
+	catalog *Catalog
+	config  Config
+	caps    adapter.Capabilities
+	store   persistence.KeyWriter
+}
+
+// NewFinder cria o serviço com os limites de lista padrão do adapter (use WithCapabilities para o canal)
+func NewFinder(catalog *Catalog, config Config) *Finder {
+	return &Finder{catalog: catalog, config: config, caps: adapter.NewCaps()}
+}
+
+// WithConfig define a configuração de busca
+func (f *Finder) WithConfig(config Config) *Finder {
+	cp := *f
+	cp.config = config
+	return &cp
+}
+
+// WithCapabilities define os limites de lista do canal (itens, seções, tamanhos de texto)
+func (f *Finder) WithCapabilities(caps adapter.Capabilities) *Finder {
+	cp := *f
+	cp.caps = caps
+	return &cp
+}
+
+// WithKeyWriter grava a unidade escolhida no auto_persist configurado
+func (f *Finder) WithKeyWriter(store persistence.KeyWriter) *Finder {
+	cp := *f
+	cp.store = store
+	return &cp
+}
+
+// Search retorna a página pedida das unidades no raio padrão, expandindo o raio
+// quando nada é encontrado. Preferidas (catálogo ou do usuário) vêm primeiro
+// quando ShowPreferredFirst está ativo
+func (f *Finder) Search(q Query) Page {
+	radius := f.config.RadiusKmDefault
+	results := f.within(q, radius)
+	page := Page{RadiusKm: radius}
+	if len(results) == 0 && f.config.RadiusKmExpandOnFail > radius {
+		page.RadiusKm, page.Expanded = f.config.RadiusKmExpandOnFail, true
+		results = f.within(q, page.RadiusKm)
+	}
+
+	page.Total = len(results)
+	if page.Total == 0 {
+		page.Output = OutputNoResults
+		return page
+	}
+
+	f.sort(results)
+
+	perPage := f.perPage(page.Total)
+	page.PageCount = (page.Total + perPage - 1) / perPage
+	page.Number = min(max(q.Page, 1), page.PageCount)
+	start := (page.Number - 1) * perPage
+	page.Results = results[start:min(start+perPage, page.Total)]
+	return page
+}
+
+// within unidades dentro do raio, com distância calculada
+func (f *Finder) within(q Query, radiusKm float64) []Result {
+	var results []Result
+	for _, u := range f.catalog.units {
+		d := q.Origin.DistanceKm(u.Coordinates())
+		if d <= radiusKm {
+			results = append(results, Result{Unit: u, DistanceKm: d, Preferred: u.Preferred || slices.Contains(q.PreferredIDs, u.ID)})
+		}
+	}
+	return results
+}
+
+// sort ordena por distância (desempate pelo nome) ou por nome (collation pt-BR)
+func (f *Finder) sort(results []Result) {
+	names := collate.New(language.BrazilianPortuguese, collate.IgnoreCase, collate.IgnoreDiacritics)
+	byName := func(a, b Result) int { return names.CompareString(a.Unit.Name, b.Unit.Name) }
+
+	slices.SortStableFunc(results, func(a, b Result) int {
+		if f.config.ShowPreferredFirst && a.Preferred != b.Preferred {
+			if a.Preferred {
+				return -1
+			}
+			return 1
+		}
+		if f.config.Sort == SortName {
+			return byName(a, b)
+		}
+		if c := cmp.Compare(a.DistanceKm, b.DistanceKm); c != 0 {
+			return c
+		}
+		return byName(a, b)
+	})
+}
+
+// perPage itens por página limitados pelo canal; com mais de uma página,
+// reserva uma linha para o item "ver mais"
+func (f *Finder) perPage(total int) int {
+	perPage := f.config.PageSize
+	if perPage <= 0 {
+		perPage = DefaultConfig().PageSize
+	}
+	if limit := f.caps.MaxListItems; limit > 0 {
+		perPage = min(perPage, limit)
+		if total > perPage && limit > 1 {
+			perPage = min(perPage, limit-1)
+		}
+	}
+	return perPage
+}
+
+// Select interpreta o payload escolhido na lista: ID de unidade (selected, com
+// gravação do auto_persist) ou "ver mais" (more, com a próxima página)
+func (f *Finder) Select(ctx context.Context, payload string) (Selection, error) {
+	if next, ok := strings.CutPrefix(payload, adapter.PageNextPayload+":"); ok {
+		n, err := strconv.Atoi(next)
+		if err != nil || n < 1 {
+			return Selection{Error: "invalid page payload: " + payload}, fmt.Errorf("units: invalid page payload %q", payload)
+		}
+		return Selection{Output: OutputMore, NextPage: n}, nil
+	}
+
+	u, ok := f.catalog.Get(payload)
+	if !ok {
+		return Selection{Error: "unknown unit: " + payload}, fmt.Errorf("units: unknown unit %q", payload)
+	}
+
+	sel := Selection{Output: OutputSelected, Unit: &u}
+	if p := f.config.AutoPersist; p != nil && p.Enabled && p.Key != "" {
+		sel.Write = &Write{Scope: p.Scope, Key: p.Key, Value: u.ID}
+		if f.store != nil {
+			if err := f.store.Set(ctx, p.Scope, p.Key, u.ID); err != nil {
+				return sel, fmt.Errorf("persist unit %s: %w", p.Key, err)
+			}
+		}
+	}
+	return sel, nil
+}
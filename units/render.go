@@ -0,0 +1,118 @@
+package units
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/component"
+)
+
+// RenderOptions textos da lista renderizada
+type RenderOptions struct {
+	Text          string // Corpo da mensagem (padrão: "Escolha a unidade mais conveniente:")
+	ButtonText    string // Botão que abre a lista (padrão: "Ver unidades")
+	PreferredHint string // Título da seção de preferidas (padrão: "Suas unidades")
+	NearbyTitle   string // Título da seção das demais (padrão: "Mais próximas" ou "Unidades" na ordenação por nome)
+	MoreLabel     string // Item "ver mais" (padrão: "Ver mais unidades")
+	NavTitle      string // Título da seção do item "ver mais" (padrão: "Mais unidades")
+}
+
+// Render monta a página como spec de listpicker respeitando os limites do canal:
+// títulos de item/seção e botão em MaxButtonTitleLen, descrição em MaxDescriptionLen
+// e, quando há próxima página, um item "ver mais" com adapter.PageNextPayload numa
+// seção com título próprio
+func (f *Finder) Render(page Page, opts RenderOptions) component.ComponentSpec {
+	opts = f.renderDefaults(opts)
+	titleLen, descLen := f.caps.MaxButtonTitleLen, f.caps.MaxDescriptionLen
+
+	var sections []component.SectionData
+	for _, r := range page.Results {
+		title := opts.NearbyTitle
+		if f.config.ShowPreferredFirst && r.Preferred {
+			title = opts.PreferredHint
+		}
+		title = truncate(title, titleLen)
+		if n := len(sections); n == 0 || sections[n-1].Title != title {
+			sections = append(sections, component.SectionData{Title: title})
+		}
+		last := &sections[len(sections)-1]
+		last.Items = append(last.Items, component.ItemData{
+			ID:          r.Unit.ID,
+			Title:       truncate(r.Unit.Name, titleLen),
+			Description: truncate(description(r), descLen),
+		})
+	}
+	if page.HasNext() {
+		sections = append(sections, component.SectionData{
+			Title: truncate(opts.NavTitle, titleLen),
+			Items: []component.ItemData{{ID: fmt.Sprintf("%s:%d", adapter.PageNextPayload, page.Number+1), Title: truncate(opts.MoreLabel, titleLen)}},
+		})
+	}
+
+	return component.ComponentSpec{
+		Kind: "listpicker",
+		Text: &component.TextValue{Raw: opts.Text},
+		Meta: map[string]any{
+			"output_mode": "single",
+			"button_text": component.TextValue{Raw: truncate(opts.ButtonText, titleLen)},
+			"sections":    sections,
+			"page":        page.Number,
+			"page_count":  page.PageCount,
+			"radius_km":   page.RadiusKm,
+			"expanded":    page.Expanded,
+		},
+	}
+}
+
+func (f *Finder) renderDefaults(opts RenderOptions) RenderOptions {
+	if opts.Text == "" {
+		opts.Text = "Escolha a unidade mais conveniente:"
+	}
+	if opts.ButtonText == "" {
+		opts.ButtonText = "Ver unidades"
+	}
+	if opts.PreferredHint == "" {
+		opts.PreferredHint = "Suas unidades"
+	}
+	if opts.NearbyTitle == "" {
+		opts.NearbyTitle = "Mais próximas"
+		if f.config.Sort == SortName {
+			opts.NearbyTitle = "Unidades"
+		}
+	}
+	if opts.MoreLabel == "" {
+		opts.MoreLabel = "Ver mais unidades"
+	}
+	if opts.NavTitle == "" {
+		opts.NavTitle = "Mais unidades"
+	}
+	return opts
+}
+
+// description distância e endereço: "1,2 km · Av. Paulista, 1000"
+func description(r Result) string {
+	if r.Unit.Address == "" {
+		return FormatDistance(r.DistanceKm)
+	}
+	return FormatDistance(r.DistanceKm) + " · " + r.Unit.Address
+}
+
+// FormatDistance distância em pt-BR: "850 m" abaixo de 1 km, "1,2 km" acima
+func FormatDistance(km float64) string {
+	if km < 1 {
+		return strconv.Itoa(int(km*1000+0.5)) + " m"
+	}
+	return strings.Replace(strconv.FormatFloat(km, 'f', 1, 64), ".", ",", 1) + " km"
+}
+
+// truncate corta em limit caracteres (runas) terminando com "…"; limit <= 0 não corta
+func truncate(s string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
@@ -0,0 +1,143 @@
+package units_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/adapter"
+	"github.com/AgendoCerto/lib-bot/adapter/whatsapp"
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/geo"
+	"github.com/AgendoCerto/lib-bot/persistence"
+	"github.com/AgendoCerto/lib-bot/runtime"
+	"github.com/AgendoCerto/lib-bot/units"
+)
+
+var paulista = geo.Coordinates{Lat: -23.5613, Lng: -46.6565}
+
+func loadFinder(t *testing.T, cfg units.Config) *units.Finder {
+	t.Helper()
+	catalog, err := units.LoadCatalog("testdata/units.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return units.NewFinder(catalog, cfg).WithCapabilities(whatsapp.New().Capabilities())
+}
+
+func ids(page units.Page) string {
+	var out []string
+	for _, r := range page.Results {
+		out = append(out, r.Unit.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestSearch(t *testing.T) {
+	cfg := units.DefaultConfig()
+	cfg.RadiusKmDefault = 5
+	finder := loadFinder(t, cfg)
+
+	page := finder.Search(units.Query{Origin: paulista})
+	if got := ids(page); got != "paulista,augusta,se,pinheiros" || page.Expanded {
+		t.Fatalf("distance order = %s (expanded %v)", got, page.Expanded)
+	}
+
+	// Preferida do catálogo fica fora do raio padrão; a do usuário vem primeiro
+	page = finder.Search(units.Query{Origin: paulista, PreferredIDs: []string{"se"}})
+	if got := ids(page); got != "se,paulista,augusta,pinheiros" {
+		t.Fatalf("preferred order = %s", got)
+	}
+
+	cfg.Sort = units.SortName
+	if got := ids(finder.WithConfig(cfg).Search(units.Query{Origin: paulista})); got != "augusta,paulista,pinheiros,se" {
+		t.Fatalf("name order = %s", got)
+	}
+
+	// Nenhuma unidade a 1 km do ponto: expande para 20 km
+	cfg = units.DefaultConfig()
+	cfg.RadiusKmDefault, cfg.RadiusKmExpandOnFail = 1, 20
+	page = finder.WithConfig(cfg).Search(units.Query{Origin: geo.Coordinates{Lat: -23.5850, Lng: -46.6700}})
+	if !page.Expanded || page.Total == 0 {
+		t.Fatalf("expected expanded radius, got %+v", page)
+	}
+
+	cfg.RadiusKmExpandOnFail = 0
+	if page := finder.WithConfig(cfg).Search(units.Query{Origin: geo.Coordinates{Lat: -3.7, Lng: -38.5}}); page.Output != units.OutputNoResults {
+		t.Fatalf("expected no_results, got %+v", page)
+	}
+}
+
+func TestPaginationAndRender(t *testing.T) {
+	cfg := units.DefaultConfig()
+	cfg.RadiusKmDefault = 100
+	caps := whatsapp.New().Capabilities()
+	caps.MaxListItems = 4
+	finder := loadFinder(t, cfg).WithCapabilities(caps)
+
+	page := finder.Search(units.Query{Origin: paulista})
+	if page.Total != 6 || page.PageCount != 2 || len(page.Results) != 3 || page.Results[0].Unit.ID != "matriz" {
+		t.Fatalf("page 1 = %+v", page)
+	}
+
+	spec := finder.Render(page, units.RenderOptions{})
+	sections := spec.Meta["sections"].([]component.SectionData)
+	rows := 0
+	for _, s := range sections {
+		rows += len(s.Items)
+		if s.Title == "" {
+			t.Errorf("untitled section: %+v", s)
+		}
+		for _, item := range s.Items {
+			if len([]rune(item.Title)) > caps.MaxButtonTitleLen || len([]rune(item.Description)) > caps.MaxDescriptionLen {
+				t.Errorf("item exceeds channel limits: %+v", item)
+			}
+		}
+	}
+	last := sections[len(sections)-1].Items[0]
+	if rows != caps.MaxListItems || last.ID != adapter.PageNextPayload+":2" {
+		t.Fatalf("rendered %d rows, last item %+v", rows, last)
+	}
+
+	sel, err := finder.Select(context.Background(), last.ID)
+	if err != nil || sel.Output != units.OutputMore || sel.NextPage != 2 {
+		t.Fatalf("more selection = %+v, %v", sel, err)
+	}
+	if page := finder.Search(units.Query{Origin: paulista, Page: sel.NextPage}); page.Number != 2 || page.HasNext() || len(page.Results) != 3 {
+		t.Fatalf("page 2 = %+v", page)
+	}
+}
+
+type memoryStore map[string]string
+
+func (m memoryStore) Set(_ context.Context, scope persistence.Scope, key, value string) error {
+	m[string(scope)+"."+key] = value
+	return nil
+}
+
+func TestSelectPersistsUnit(t *testing.T) {
+	uf, err := component.NewUnitFinderFactory(nil).New("unit_finder", map[string]any{
+		"page_size":    float64(5),
+		"auto_persist": map[string]any{"scope": "state", "key": "unit_id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := uf.Spec(context.Background(), runtime.Context{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := units.ConfigFromSpec(spec)
+	if cfg.PageSize != 5 || cfg.AutoPersist == nil {
+		t.Fatalf("config from spec = %+v", cfg)
+	}
+
+	store := memoryStore{}
+	sel, err := loadFinder(t, cfg).WithKeyWriter(store).Select(context.Background(), "augusta")
+	if err != nil || sel.Output != units.OutputSelected || store["state.unit_id"] != "augusta" {
+		t.Fatalf("selection = %+v, %v, store %v", sel, err, store)
+	}
+	if _, err := loadFinder(t, cfg).Select(context.Background(), "unknown"); err == nil {
+		t.Fatal("expected error for unknown unit")
+	}
+}
@@ -25,6 +25,11 @@ func NewDesignValidationPipeline() *DesignValidationPipeline {
 			NewLiquidLengthStep(),      // CRÍTICO: Validação de limites considerando templates Liquid
 			NewProfileContextStep(),    // NOVO: Validação de profile context
 			NewWhatsAppLimitsStep(),    // NOVO: Validação de limites WhatsApp Business API
+			NewSubflowStep(),           // Interface e recursão de subflows
+			NewI18nStep(),              // Traduções faltantes e locales
+			NewIntentStep(),            // Intents referenciados pelo modo intent
+			NewBusinessHoursStep(),     // Calendário e off_hours_message do human_handoff
+			NewServiceWindowStep(),     // Mensagens livres após temporizadores longos (janela de 24h do WhatsApp)
 		},
 	}
 }
@@ -0,0 +1,53 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/handoff"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// BusinessHoursStep valida o business_hours do design e exige off_hours_message
+// nos human_handoff quando o calendário tem períodos fechados
+type BusinessHoursStep struct{}
+
+// NewBusinessHoursStep cria novo validador de horário de atendimento
+func NewBusinessHoursStep() *BusinessHoursStep {
+	return &BusinessHoursStep{}
+}
+
+// ValidateDesign valida o calendário e as mensagens fora do horário
+func (s *BusinessHoursStep) ValidateDesign(design io.DesignDoc) []Issue {
+	if design.BusinessHours == nil {
+		return nil
+	}
+
+	calendar, err := handoff.NewCalendar(*design.BusinessHours)
+	if err != nil {
+		return []Issue{{
+			Code: "business_hours.invalid", Severity: Err,
+			Path: "business_hours",
+			Msg:  err.Error(),
+		}}
+	}
+	if !calendar.HasClosedPeriods() {
+		return nil
+	}
+
+	var issues []Issue
+	for i, n := range design.Graph.Nodes {
+		if n.Kind != "human_handoff" {
+			continue
+		}
+		props := design.ResolveProps(n)
+		if msg, _ := props["off_hours_message"].(string); strings.TrimSpace(msg) == "" {
+			issues = append(issues, Issue{
+				Code: "handoff.off_hours_message.missing", Severity: Err,
+				Path: fmt.Sprintf("graph.nodes[%d].props.off_hours_message", i),
+				Msg:  fmt.Sprintf("human_handoff '%s' needs off_hours_message: business_hours has closed periods", n.ID),
+			})
+		}
+	}
+	return issues
+}
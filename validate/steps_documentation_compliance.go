@@ -256,6 +256,17 @@ func (s *DocumentationComplianceStep) validateNode(node flow.Node, path string)
 		"terminal":     true,
 		"action":       true,
 		"global_start": true,
+		"subflow":      true,
+		// Componentes spec v2.2
+		"terms_gate":       true,
+		"hsm_trigger":      true,
+		"location_capture": true,
+		"geo_resolve":      true,
+		"unit_finder":      true,
+		"slot_picker":      true,
+		"payment_link":     true,
+		"order_cart":       true,
+		"human_handoff":    true,
 	}
 
 	if node.Kind != "" && !knownKinds[node.Kind] {
@@ -0,0 +1,120 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/hsm"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// HSMRegistryStep confere hsm_trigger, hsm_ref e message.hsm contra o registro
+// de templates aprovados: template desconhecido, idioma, número de variáveis e
+// tipos de botão
+// Não faz parte do pipeline padrão (depende do registro exportado): adicionar
+// com DesignValidationPipeline.AddValidator
+type HSMRegistryStep struct {
+	registry *hsm.Registry
+}
+
+// NewHSMRegistryStep cria validador sobre o registro de templates
+func NewHSMRegistryStep(registry *hsm.Registry) *HSMRegistryStep {
+	return &HSMRegistryStep{registry: registry}
+}
+
+// hsmIssueCodes código do issue por erro do registro
+var hsmIssueCodes = []struct {
+	err  error
+	code string
+}{
+	{hsm.ErrUnknownTemplate, "hsm.template.unknown"},
+	{hsm.ErrWrongLanguage, "hsm.template.language"},
+	{hsm.ErrParamCount, "hsm.template.params"},
+	{hsm.ErrUnsupportedButton, "hsm.template.button"},
+}
+
+// hsmButtonTypes tipos de component.Button para tipos de botão do template
+var hsmButtonTypes = map[string]string{
+	"reply": hsm.ButtonQuickReply,
+	"url":   hsm.ButtonURL,
+	"call":  hsm.ButtonPhone,
+}
+
+// ValidateDesign confere os usos de templates de todos os nós
+func (s *HSMRegistryStep) ValidateDesign(design io.DesignDoc) []Issue {
+	if s.registry == nil {
+		return nil
+	}
+
+	var issues []Issue
+	for i, n := range design.Graph.Nodes {
+		props := design.ResolveProps(n)
+		base := fmt.Sprintf("graph.nodes[%d].props", i)
+
+		if n.Kind == "hsm_trigger" {
+			if name, _ := props["template_id"].(string); name != "" {
+				language, _ := props["language"].(string)
+				vars, _ := props["variables"].(map[string]any)
+				issues = append(issues, s.check(base+".template_id", hsm.Usage{
+					Name:     name,
+					Language: templateLanguage(language),
+					Params:   len(vars),
+				})...)
+			}
+		}
+
+		if ref, ok := props["hsm_ref"].(map[string]any); ok {
+			if name, _ := ref["id"].(string); name != "" {
+				locale, _ := ref["locale"].(string)
+				params, _ := ref["params"].([]any)
+				u := hsm.Usage{Name: name, Language: templateLanguage(locale), Params: len(params)}
+				buttons, _ := ref["buttons"].([]any)
+				for _, raw := range buttons {
+					b, _ := raw.(map[string]any)
+					kind, _ := b["kind"].(string)
+					if t, ok := hsmButtonTypes[kind]; ok {
+						u.Buttons = append(u.Buttons, t)
+					} else {
+						u.Buttons = append(u.Buttons, strings.ToUpper(kind))
+					}
+				}
+				issues = append(issues, s.check(base+".hsm_ref", u)...)
+			}
+		}
+
+		// message.hsm informa apenas o nome
+		if h, ok := props["hsm"].(map[string]any); ok {
+			if name, _ := h["name"].(string); name != "" {
+				issues = append(issues, s.check(base+".hsm.name", hsm.Usage{Name: name, Params: -1})...)
+			}
+		}
+	}
+	return issues
+}
+
+// check converte os erros do registro em issues
+func (s *HSMRegistryStep) check(path string, u hsm.Usage) []Issue {
+	var issues []Issue
+	for _, err := range s.registry.Check(u) {
+		code := "hsm.template.invalid"
+		for _, c := range hsmIssueCodes {
+			if errors.Is(err, c.err) {
+				code = c.code
+				break
+			}
+		}
+		issues = append(issues, Issue{Code: code, Severity: Err, Path: path, Msg: err.Error()})
+	}
+	return issues
+}
+
+// templateLanguage idioma no formato do registro (pt-BR → pt_BR); vazio ou
+// "auto" é resolvido na sessão e não é conferido
+func templateLanguage(language string) string {
+	if language == component.SessionLocale {
+		return ""
+	}
+	return strings.ReplaceAll(language, "-", "_")
+}
@@ -0,0 +1,124 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// I18nStep valida a tabela de traduções: locales, chaves e traduções faltantes
+type I18nStep struct{}
+
+// NewI18nStep cria novo validador de internacionalização
+func NewI18nStep() *I18nStep {
+	return &I18nStep{}
+}
+
+// ValidateDesign valida a configuração i18n do design
+func (s *I18nStep) ValidateDesign(design io.DesignDoc) []Issue {
+	t := design.I18n
+	if t == nil {
+		return nil
+	}
+
+	var issues []Issue
+
+	def := io.NormalizeLocale(t.DefaultLocale)
+	if def == "" {
+		issues = append(issues, Issue{
+			Code: "i18n.default_locale.missing", Severity: Err,
+			Path: "i18n.default_locale",
+			Msg:  "i18n requires a default_locale (locale of the texts in node props)",
+		})
+	}
+
+	declared := make(map[string]bool, len(t.Locales))
+	for _, l := range t.Locales {
+		declared[io.NormalizeLocale(l)] = true
+	}
+
+	fallbackKeys := make([]string, 0, len(t.Fallbacks))
+	for locale := range t.Fallbacks {
+		fallbackKeys = append(fallbackKeys, locale)
+	}
+	sort.Strings(fallbackKeys)
+
+	for _, locale := range fallbackKeys {
+		for _, f := range t.Fallbacks[locale] {
+			if n := io.NormalizeLocale(f); n != def && !declared[n] {
+				issues = append(issues, Issue{
+					Code: "i18n.fallback.unknown_locale", Severity: Warn,
+					Path: "i18n.fallbacks." + locale,
+					Msg:  fmt.Sprintf("fallback locale '%s' is not declared in i18n.locales", f),
+				})
+			}
+		}
+	}
+
+	texts := design.LocalizableTexts()
+	known := make(map[string]bool, len(texts))
+	for _, text := range texts {
+		known[text.Key] = true
+	}
+
+	keys := make([]string, 0, len(t.Translations))
+	for key := range t.Translations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !known[key] {
+			issues = append(issues, Issue{
+				Code: "i18n.key.unknown", Severity: Warn,
+				Path: "i18n.translations." + key,
+				Msg:  fmt.Sprintf("translation key '%s' does not match any localizable node text", key),
+			})
+			continue
+		}
+		for locale := range t.Translations[key] {
+			if n := io.NormalizeLocale(locale); n != def && !declared[n] {
+				issues = append(issues, Issue{
+					Code: "i18n.locale.undeclared", Severity: Warn,
+					Path: "i18n.translations." + key + "." + locale,
+					Msg:  fmt.Sprintf("locale '%s' is not declared in i18n.locales", locale),
+				})
+			}
+		}
+	}
+
+	// Traduções faltantes por locale: Warn quando cai no texto padrão, Info quando resolve por fallback
+	locales := make([]string, 0, len(declared))
+	for l := range declared {
+		if l != def {
+			locales = append(locales, l)
+		}
+	}
+	sort.Strings(locales)
+
+	for _, text := range texts {
+		variants := t.Variants(text.Key)
+		for _, locale := range locales {
+			if _, ok := variants[locale]; ok {
+				continue
+			}
+			path := "i18n.translations." + text.Key + "." + locale
+			if _, used, ok := t.Lookup(text.Key, locale); ok {
+				issues = append(issues, Issue{
+					Code: "i18n.translation.fallback", Severity: Info,
+					Path: path,
+					Msg:  fmt.Sprintf("missing '%s' translation for '%s' - falls back to '%s'", locale, text.Key, used),
+				})
+				continue
+			}
+			issues = append(issues, Issue{
+				Code: "i18n.translation.missing", Severity: Warn,
+				Path: path,
+				Msg:  fmt.Sprintf("missing '%s' translation for '%s' - default locale text will be sent", locale, text.Key),
+			})
+		}
+	}
+
+	return issues
+}
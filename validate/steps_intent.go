@@ -0,0 +1,130 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// IntentStep valida rotas do validator com modo intent contra os intents do design
+type IntentStep struct{}
+
+// NewIntentStep cria novo validador do modo intent
+func NewIntentStep() *IntentStep {
+	return &IntentStep{}
+}
+
+// ValidateDesign valida exemplos de intents e referências nas rotas
+func (s *IntentStep) ValidateDesign(design io.DesignDoc) []Issue {
+	var issues []Issue
+
+	names := make([]string, 0, len(design.Intents))
+	for name := range design.Intents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(design.Intents[name]) < 2 {
+			issues = append(issues, Issue{
+				Code: "intent.examples.few", Severity: Warn,
+				Path: "intents." + name,
+				Msg:  fmt.Sprintf("intent '%s' has %d example(s) - at least 2 are recommended for classification", name, len(design.Intents[name])),
+			})
+		}
+	}
+
+	for i, n := range design.Graph.Nodes {
+		props := design.ResolveProps(n)
+		cfg, base := validatorConfig(props)
+		if cfg == nil {
+			continue
+		}
+
+		routes, _ := cfg["routes"].([]any)
+		for r, raw := range routes {
+			route, _ := raw.(map[string]any)
+			modes, _ := route["modes"].(map[string]any)
+			intent, ok := modes["intent"].(map[string]any)
+			if !ok {
+				continue
+			}
+			path := fmt.Sprintf("graph.nodes[%d].props.%s.routes[%d].modes.intent", i, base, r)
+
+			if len(design.Intents) == 0 {
+				issues = append(issues, Issue{
+					Code: "intent.examples.missing", Severity: Err,
+					Path: path,
+					Msg:  "intent mode requires design-level 'intents' with example utterances",
+				})
+				continue
+			}
+
+			accepted, _ := intent["intents"].([]any)
+			if len(accepted) == 0 {
+				issues = append(issues, Issue{
+					Code: "intent.route.empty", Severity: Err,
+					Path: path + ".intents",
+					Msg:  "intent route must list at least one intent",
+				})
+			}
+			for _, a := range accepted {
+				name, _ := a.(string)
+				if _, ok := design.Intents[name]; !ok {
+					issues = append(issues, Issue{
+						Code: "intent.unknown", Severity: Err,
+						Path: path + ".intents",
+						Msg:  fmt.Sprintf("intent '%s' is not defined in design intents", name),
+					})
+				}
+			}
+
+			if conf, ok := intent["min_confidence"].(float64); ok && (conf < 0 || conf > 1) {
+				issues = append(issues, Issue{
+					Code: "intent.min_confidence.invalid", Severity: Err,
+					Path: path + ".min_confidence",
+					Msg:  fmt.Sprintf("min_confidence must be between 0 and 1 (got %v)", conf),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// validatorConfig localiza a configuração do validator nas props (props.validator ou props.behavior.validator)
+func validatorConfig(props map[string]any) (map[string]any, string) {
+	if cfg, ok := props["validator"].(map[string]any); ok {
+		return cfg, "validator"
+	}
+	if behavior, ok := props["behavior"].(map[string]any); ok {
+		if cfg, ok := behavior["validator"].(map[string]any); ok {
+			return cfg, "behavior.validator"
+		}
+	}
+	return nil, ""
+}
+
+// entityPersistenceKeys lista as chaves ("state.cpf") gravadas pelo modo entity das rotas do validator
+func entityPersistenceKeys(design io.DesignDoc) []string {
+	var keys []string
+	for _, n := range design.Graph.Nodes {
+		cfg, _ := validatorConfig(design.ResolveProps(n))
+		routes, _ := cfg["routes"].([]any)
+		for _, raw := range routes {
+			route, _ := raw.(map[string]any)
+			modes, _ := route["modes"].(map[string]any)
+			entities, _ := modes["entity"].([]any)
+			for _, e := range entities {
+				entity, _ := e.(map[string]any)
+				p, _ := entity["persistence"].(map[string]any)
+				scope, _ := p["scope"].(string)
+				key, _ := p["key"].(string)
+				if enabled, _ := p["enabled"].(bool); enabled && scope != "" && key != "" {
+					keys = append(keys, scope+"."+key)
+				}
+			}
+		}
+	}
+	return keys
+}
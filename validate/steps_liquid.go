@@ -5,6 +5,7 @@ import (
 
 	"github.com/AgendoCerto/lib-bot/adapter"
 	"github.com/AgendoCerto/lib-bot/component"
+	"github.com/AgendoCerto/lib-bot/geo"
 	"github.com/AgendoCerto/lib-bot/io"
 	"github.com/AgendoCerto/lib-bot/liquid"
 )
@@ -41,7 +42,7 @@ func NewLiquidStep() *LiquidStep {
 				// 🌍 Formatação internacional
 				"phone": true, "currency": true, "money": true,
 				// 🇧🇷 Documentos brasileiros
-				"cpf": true, "cnpj": true, "cep": true, "rg": true,
+				"cpf": true, "cnpj": true, "cep": true, "rg": true, "pis": true,
 				// 📅 Data/hora avançados
 				"date_tz": true, "time_ago": true, "duration": true, "timestamp": true, "from_now": true,
 				// 🔐 Hash/encode
@@ -100,6 +101,23 @@ func (s LiquidStep) Check(spec component.ComponentSpec, _ adapter.Capabilities,
 		for key := range s.designDoc.Variables.Global {
 			availableKeys["global."+key] = true
 		}
+
+		// Resultado do modo intent do validator (preenchido pelo runtime)
+		if len(s.designDoc.Intents) > 0 {
+			availableKeys["context.intent"] = true
+			availableKeys["context.intent_confidence"] = true
+			availableKeys["context.intents"] = true
+		}
+
+		// Entidades gravadas pelo modo entity do validator
+		for _, key := range entityPersistenceKeys(*s.designDoc) {
+			availableKeys[key] = true
+		}
+
+		// Endereços gravados por location_capture/geo_resolve (chave + subchaves por campo)
+		for _, key := range geoPersistenceKeys(*s.designDoc) {
+			availableKeys[key] = true
+		}
 	}
 
 	if spec.Persistence != nil && spec.Persistence.Enabled {
@@ -182,3 +200,21 @@ func toSev(s string) Severity {
 		return Info
 	}
 }
+
+// geoPersistenceKeys chaves gravadas pelos componentes de endereço (ver geo.PersistenceKeys)
+func geoPersistenceKeys(design io.DesignDoc) []string {
+	var keys []string
+	for _, n := range design.Graph.Nodes {
+		if n.Kind != "location_capture" && n.Kind != "geo_resolve" {
+			continue
+		}
+		p, err := component.ParsePersistence(design.ResolveProps(n))
+		if err != nil || p == nil || !p.Enabled || p.Key == "" {
+			continue
+		}
+		for _, key := range geo.PersistenceKeys(p.Key) {
+			keys = append(keys, string(p.Scope)+"."+key)
+		}
+	}
+	return keys
+}
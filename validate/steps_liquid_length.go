@@ -2,6 +2,8 @@ package validate
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/AgendoCerto/lib-bot/flow"
@@ -25,6 +27,51 @@ func (s *LiquidLengthStep) ValidateDesign(design io.DesignDoc) []Issue {
 		issues = append(issues, s.validateNodeTextLimits(node, path, design.Props)...)
 	}
 
+	issues = append(issues, s.validateTranslationLimits(design)...)
+
+	return issues
+}
+
+// indexPattern remove índices de caminhos de props (buttons[2].label -> buttons[].label)
+var indexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// translatedTextLimits limites por tipo de nó e caminho de prop (mesmos de validateNodeTextLimits)
+var translatedTextLimits = map[string]map[string]int{
+	"message":    {"text": 1024},
+	"text":       {"text": 1024},
+	"buttons":    {"text": 1024, "buttons[].label": 20},
+	"listpicker": {"text": 1024, "sections[].items[].title": 24, "sections[].items[].description": 72},
+	"media":      {"caption": 1024},
+	"carousel":   {"text": 1024, "cards[].title": 80, "cards[].description": 300},
+	"confirm":    {"title": 60, "description": 72},
+}
+
+// validateTranslationLimits aplica os limites de caracteres em cada tradução (por locale)
+func (s *LiquidLengthStep) validateTranslationLimits(design io.DesignDoc) []Issue {
+	if design.I18n == nil || len(design.I18n.Translations) == 0 {
+		return nil
+	}
+
+	var issues []Issue
+	for _, t := range design.LocalizableTexts() {
+		maxLen, ok := translatedTextLimits[t.Kind][indexPattern.ReplaceAllString(t.Prop, "[]")]
+		if !ok {
+			continue
+		}
+
+		variants := design.I18n.Translations[t.Key]
+		locales := make([]string, 0, len(variants))
+		for locale := range variants {
+			locales = append(locales, locale)
+		}
+		sort.Strings(locales)
+
+		for _, locale := range locales {
+			path := fmt.Sprintf("i18n.translations.%s.%s", t.Key, locale)
+			issues = append(issues, s.validateTextLength(variants[locale], path, maxLen, design.Props)...)
+		}
+	}
+
 	return issues
 }
 
@@ -46,7 +46,13 @@ func (s *OutputMappingStep) validateNodeOutputs(node flow.Node, path string) []I
 		issues = append(issues, s.validateFeedbackOutputs(node, path)...)
 	case "global_start":
 		issues = append(issues, s.validateGlobalStartOutputs(node, path)...)
+	case "subflow":
+		// Outputs de subflow dependem da interface declarada (ver SubflowStep)
 	default:
+		if _, ok := specV22Outputs[node.Kind]; ok {
+			issues = append(issues, s.validateSpecV22Outputs(node, path)...)
+			break
+		}
 		issues = append(issues, Issue{
 			Code: "output.unknown_component", Severity: Err,
 			Path: path + ".kind",
@@ -583,3 +589,44 @@ func outputsMatch(actual, expected []string) bool {
 
 	return true
 }
+
+// specV22Outputs outputs documentados dos componentes spec v2.2 (ver package component)
+var specV22Outputs = map[string][]string{
+	"terms_gate":       {"accepted", "rejected", "skipped"},
+	"hsm_trigger":      {"sent", "failed", "cooldown"},
+	"location_capture": {"captured", "invalid", "timeout"},
+	"geo_resolve":      {"resolved", "no_match", "error"},
+	"unit_finder":      {"selected", "no_results", "more"},
+	"slot_picker":      {"chosen", "no_slots", "next_page", "prev_page"},
+	"payment_link":     {"paid", "expired", "failed", "cancelled", "abandoned"},
+	"order_cart":       {"added", "removed", "cleared", "error", "viewed", "checkout_ready", "empty"},
+	"human_handoff":    {"queued", "agent_joined", "closed_by_agent", "timeout_to_bot"},
+}
+
+// validateSpecV22Outputs valida que outputs de componentes spec v2.2 são os documentados
+func (s *OutputMappingStep) validateSpecV22Outputs(node flow.Node, path string) []Issue {
+	var issues []Issue
+
+	standardOutputs := []string{"timeout", "invalid", "fallback"}
+	validOutputs := append(append([]string{}, specV22Outputs[node.Kind]...), standardOutputs...)
+
+	if len(node.Outputs) == 0 {
+		issues = append(issues, Issue{
+			Code: fmt.Sprintf("output.%s.no_outputs", node.Kind), Severity: Warn,
+			Path: path + ".outputs",
+			Msg:  fmt.Sprintf("%s component declares no outputs - expected some of %v", node.Kind, specV22Outputs[node.Kind]),
+		})
+	}
+
+	for _, output := range node.Outputs {
+		if !contains(validOutputs, output) {
+			issues = append(issues, Issue{
+				Code: fmt.Sprintf("output.%s.invalid_output", node.Kind), Severity: Err,
+				Path: path + ".outputs",
+				Msg:  fmt.Sprintf("CRITICAL: %s component has unknown output '%s' - valid outputs: %v", node.Kind, output, validOutputs),
+			})
+		}
+	}
+
+	return issues
+}
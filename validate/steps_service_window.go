@@ -0,0 +1,196 @@
+package validate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/adapter/whatsapp"
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// ServiceWindowStep alerta quando um nó alcançável após um temporizador longo
+// (delay, timeout ou validator.timeout_seconds de 24h ou mais) envia mensagem
+// livre: no WhatsApp a janela de atendimento já fechou e o envio exige template
+type ServiceWindowStep struct {
+	threshold time.Duration
+}
+
+// NewServiceWindowStep cria novo validador da janela de 24h
+func NewServiceWindowStep() *ServiceWindowStep {
+	return &ServiceWindowStep{threshold: whatsapp.ServiceWindow}
+}
+
+// interactiveKinds componentes que aguardam resposta (a resposta reabre a janela)
+var interactiveKinds = map[string]bool{
+	"buttons": true, "listpicker": true, "menu": true, "carousel": true, "confirm": true,
+	"terms": true, "terms_gate": true, "feedback": true, "location_capture": true, "slot_picker": true,
+}
+
+// delayUnits unidades aceitas pelo componente delay
+var delayUnits = map[string]time.Duration{
+	"": time.Millisecond, "ms": time.Millisecond, "milliseconds": time.Millisecond,
+	"s": time.Second, "seconds": time.Second, "m": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hours": time.Hour, "d": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// ValidateDesign percorre o grafo a partir de cada temporizador longo
+func (s *ServiceWindowStep) ValidateDesign(design io.DesignDoc) []Issue {
+	if !targetsWhatsApp(design.Bot.Channels) {
+		return nil
+	}
+
+	index := make(map[flow.ID]int, len(design.Graph.Nodes))
+	for i, n := range design.Graph.Nodes {
+		index[n.ID] = i
+	}
+	outgoing := map[flow.ID][]flow.Edge{}
+	for _, e := range design.Graph.Edges {
+		outgoing[e.From] = append(outgoing[e.From], e)
+	}
+
+	var issues []Issue
+	reported := map[flow.ID]bool{}
+	for _, n := range design.Graph.Nodes {
+		props := design.ResolveProps(n)
+		timer, labels := longTimer(n.Kind, props)
+		if timer < s.threshold {
+			continue
+		}
+
+		// Busca em largura a partir das saídas do temporizador; para nos nós que
+		// aguardam resposta (a resposta do usuário reabre a janela)
+		var queue []flow.ID
+		for _, e := range outgoing[n.ID] {
+			if labels == nil || labels[e.Label] {
+				queue = append(queue, e.To)
+			}
+		}
+		visited := map[flow.ID]bool{n.ID: true}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			i, ok := index[id]
+			if !ok || visited[id] {
+				continue
+			}
+			visited[id] = true
+
+			target := design.Graph.Nodes[i]
+			targetProps := design.ResolveProps(target)
+			if sendsFreeForm(target.Kind, targetProps) && !reported[id] {
+				reported[id] = true
+				issues = append(issues, Issue{
+					Code: "whatsapp.service_window.free_form", Severity: Warn,
+					Path: fmt.Sprintf("graph.nodes[%d]", i),
+					Msg: fmt.Sprintf("node '%s' sends free-form content and is reachable after the %s timer of '%s' - outside WhatsApp's 24h window only templates (HSM) are delivered",
+						id, timer, n.ID),
+				})
+			}
+			if awaitsReply(target.Kind, targetProps) {
+				continue
+			}
+			for _, e := range outgoing[id] {
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	return issues
+}
+
+// targetsWhatsApp indica se o bot tem canal WhatsApp (sem canais declarados, assume que sim)
+func targetsWhatsApp(channels []string) bool {
+	if len(channels) == 0 {
+		return true
+	}
+	for _, c := range channels {
+		if c == "whatsapp" || strings.HasPrefix(c, "whatsapp:") {
+			return true
+		}
+	}
+	return false
+}
+
+// longTimer maior temporizador do nó e as saídas que ele aciona (nil = todas)
+func longTimer(kind string, props map[string]any) (time.Duration, map[string]bool) {
+	if kind == "delay" {
+		unit, _ := props["unit"].(string)
+		return time.Duration(propInt(props["duration"])) * delayUnits[strings.ToLower(unit)], nil
+	}
+
+	var timer time.Duration
+	labels := map[string]bool{}
+	if timeout := behaviorSection(props, "timeout"); timeout != nil {
+		if d := time.Duration(propInt(timeout["duration"])) * time.Second; d > timer {
+			timer = d
+		}
+		labels["timeout"] = true
+	}
+	if cfg, _ := validatorConfig(props); cfg != nil {
+		if d := time.Duration(propInt(cfg["timeout_seconds"])) * time.Second; d > timer {
+			timer = d
+		}
+		output, _ := cfg["timeout_output"].(string)
+		if output == "" {
+			output, _ = cfg["default_output"].(string)
+		}
+		labels["timeout"] = true
+		if output != "" {
+			labels[output] = true
+		}
+	}
+	return timer, labels
+}
+
+// sendsFreeForm indica se o nó envia mensagem que não é template
+func sendsFreeForm(kind string, props map[string]any) bool {
+	if kind == "delay" {
+		return whatsapp.DelaySendsMessage(props["message"])
+	}
+	if whatsapp.SilentKind(kind) || kind == "hsm_trigger" {
+		return false
+	}
+	if _, ok := props["hsm"].(map[string]any); ok {
+		return false
+	}
+	if _, ok := props["hsm_ref"].(map[string]any); ok {
+		return false
+	}
+	return true
+}
+
+// awaitsReply indica se o nó aguarda resposta do usuário
+func awaitsReply(kind string, props map[string]any) bool {
+	if interactiveKinds[kind] {
+		return true
+	}
+	cfg, _ := validatorConfig(props)
+	enabled, _ := cfg["enabled"].(bool)
+	return enabled
+}
+
+// behaviorSection seção de behavior nas props (no topo ou em props.behavior)
+func behaviorSection(props map[string]any, key string) map[string]any {
+	if section, ok := props[key].(map[string]any); ok {
+		return section
+	}
+	behavior, _ := props["behavior"].(map[string]any)
+	section, _ := behavior[key].(map[string]any)
+	return section
+}
+
+// propInt inteiro das props (float64 no JSON, int ou string numérica)
+func propInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(strings.TrimSpace(n))
+		return i
+	}
+	return 0
+}
@@ -0,0 +1,285 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/io"
+)
+
+// SubflowStep valida subflows: definição, compatibilidade de interface e recursão
+type SubflowStep struct{}
+
+// NewSubflowStep cria novo validador de subflows
+func NewSubflowStep() *SubflowStep {
+	return &SubflowStep{}
+}
+
+// ValidateDesign valida definições de subflows e os nós que os invocam
+func (s *SubflowStep) ValidateDesign(design io.DesignDoc) []Issue {
+	var issues []Issue
+
+	// Ordena IDs para manter a saída determinística
+	ids := make([]string, 0, len(design.Subflows))
+	for id := range design.Subflows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		sf := design.Subflows[id]
+		path := "subflows." + id
+		issues = append(issues, s.validateDefinition(sf, path)...)
+		issues = append(issues, s.validateCalls(sf.Graph, sf.ResolveProps, design.Subflows, path+".graph")...)
+	}
+
+	issues = append(issues, s.validateCalls(design.Graph, design.ResolveProps, design.Subflows, "graph")...)
+	issues = append(issues, s.validateRecursion(design.Subflows, ids)...)
+
+	return issues
+}
+
+// validateDefinition valida a estrutura interna de um subflow
+func (s *SubflowStep) validateDefinition(sf io.Subflow, path string) []Issue {
+	var issues []Issue
+
+	nodeIDs := make(map[flow.ID]bool, len(sf.Graph.Nodes))
+	for _, n := range sf.Graph.Nodes {
+		nodeIDs[n.ID] = true
+	}
+
+	if sf.Entry == "" {
+		issues = append(issues, Issue{
+			Code: "subflow.entry.missing", Severity: Err,
+			Path: path + ".entry",
+			Msg:  "subflow must declare an entry node",
+		})
+	} else if !nodeIDs[sf.Entry] {
+		issues = append(issues, Issue{
+			Code: "subflow.entry.not_found", Severity: Err,
+			Path: path + ".entry",
+			Msg:  fmt.Sprintf("subflow entry '%s' does not exist in subflow graph", sf.Entry),
+		})
+	}
+
+	if len(sf.Outputs) == 0 {
+		issues = append(issues, Issue{
+			Code: "subflow.outputs.missing", Severity: Warn,
+			Path: path + ".outputs",
+			Msg:  "subflow declares no outputs - parent flow cannot continue after it",
+		})
+	}
+
+	exits := make(map[string]bool)
+	for i, e := range sf.Graph.Edges {
+		edgePath := fmt.Sprintf("%s.graph.edges[%d]", path, i)
+		if !nodeIDs[e.From] {
+			issues = append(issues, Issue{
+				Code: "subflow.edge.invalid_from", Severity: Err,
+				Path: edgePath + ".from",
+				Msg:  fmt.Sprintf("edge references non-existent node '%s'", e.From),
+			})
+		}
+
+		if output, ok := strings.CutPrefix(string(e.To), io.SubflowExitPrefix); ok {
+			if !sf.HasOutput(output) {
+				issues = append(issues, Issue{
+					Code: "subflow.exit.undeclared", Severity: Err,
+					Path: edgePath + ".to",
+					Msg:  fmt.Sprintf("edge exits through undeclared output '%s'", output),
+				})
+			}
+			exits[output] = true
+			continue
+		}
+
+		if !nodeIDs[e.To] {
+			issues = append(issues, Issue{
+				Code: "subflow.edge.invalid_to", Severity: Err,
+				Path: edgePath + ".to",
+				Msg:  fmt.Sprintf("edge references non-existent node '%s'", e.To),
+			})
+		}
+	}
+
+	for _, output := range sf.Outputs {
+		if !exits[output] {
+			issues = append(issues, Issue{
+				Code: "subflow.output.unreachable", Severity: Warn,
+				Path: path + ".outputs",
+				Msg:  fmt.Sprintf("declared output '%s' has no exit edge ('%s%s')", output, io.SubflowExitPrefix, output),
+			})
+		}
+	}
+
+	return issues
+}
+
+// validateCalls valida a interface dos nós subflow de um grafo contra os subflows referenciados
+func (s *SubflowStep) validateCalls(g io.Graph, resolve func(flow.Node) map[string]any, subflows map[string]io.Subflow, basePath string) []Issue {
+	var issues []Issue
+
+	for i, n := range g.Nodes {
+		if n.Kind != "subflow" {
+			continue
+		}
+		path := fmt.Sprintf("%s.nodes[%d]", basePath, i)
+		props := resolve(n)
+
+		ref, _ := props["ref"].(string)
+		botID, _ := props["bot_id"].(string)
+
+		switch {
+		case ref == "" && botID == "":
+			issues = append(issues, Issue{
+				Code: "subflow.ref.missing", Severity: Err,
+				Path: path + ".props.ref",
+				Msg:  "subflow node requires 'ref' (local subflow) or 'bot_id' (external bot)",
+			})
+			continue
+		case ref != "" && botID != "":
+			issues = append(issues, Issue{
+				Code: "subflow.ref.ambiguous", Severity: Warn,
+				Path: path + ".props",
+				Msg:  "subflow node has both 'ref' and 'bot_id' - local 'ref' takes precedence",
+			})
+		case ref == "":
+			// Interface de subflows externos só é conhecida na compilação (via resolver)
+			issues = append(issues, Issue{
+				Code: "subflow.external.unchecked", Severity: Info,
+				Path: path + ".props.bot_id",
+				Msg:  fmt.Sprintf("interface of external subflow '%s' is checked at compile time", botID),
+			})
+			continue
+		}
+
+		sf, ok := subflows[ref]
+		if !ok {
+			issues = append(issues, Issue{
+				Code: "subflow.ref.not_found", Severity: Err,
+				Path: path + ".props.ref",
+				Msg:  fmt.Sprintf("subflow '%s' is not defined in design subflows", ref),
+			})
+			continue
+		}
+
+		issues = append(issues, s.validateInterface(n, props, sf, g.Edges, path)...)
+	}
+
+	return issues
+}
+
+// validateInterface verifica inputs/outputs do nó contra os declarados no subflow
+func (s *SubflowStep) validateInterface(n flow.Node, props map[string]any, sf io.Subflow, edges []flow.Edge, path string) []Issue {
+	var issues []Issue
+
+	inputs, _ := props["inputs"].(map[string]any)
+	for _, name := range sf.Inputs {
+		if _, ok := inputs[name]; !ok {
+			issues = append(issues, Issue{
+				Code: "subflow.input.missing", Severity: Err,
+				Path: path + ".props.inputs",
+				Msg:  fmt.Sprintf("missing input '%s' required by subflow '%s'", name, sf.ID),
+			})
+		}
+	}
+	for name := range inputs {
+		if !contains(sf.Inputs, name) {
+			issues = append(issues, Issue{
+				Code: "subflow.input.unknown", Severity: Warn,
+				Path: path + ".props.inputs." + name,
+				Msg:  fmt.Sprintf("input '%s' is not declared by subflow '%s'", name, sf.ID),
+			})
+		}
+	}
+
+	for _, output := range n.Outputs {
+		if !sf.HasOutput(output) {
+			issues = append(issues, Issue{
+				Code: "subflow.output.unknown", Severity: Err,
+				Path: path + ".outputs",
+				Msg:  fmt.Sprintf("output '%s' is not declared by subflow '%s'", output, sf.ID),
+			})
+		}
+	}
+
+	connected := make(map[string]bool)
+	for _, e := range edges {
+		if e.From != n.ID {
+			continue
+		}
+		connected[e.Label] = true
+		if !sf.HasOutput(e.Label) {
+			issues = append(issues, Issue{
+				Code: "subflow.edge.unknown_output", Severity: Err,
+				Path: path,
+				Msg:  fmt.Sprintf("edge '%s' -> '%s' uses label '%s' which is not an output of subflow '%s'", e.From, e.To, e.Label, sf.ID),
+			})
+		}
+	}
+	for _, output := range sf.Outputs {
+		if !connected[output] {
+			issues = append(issues, Issue{
+				Code: "subflow.output.unconnected", Severity: Warn,
+				Path: path,
+				Msg:  fmt.Sprintf("subflow output '%s' is not connected in parent flow", output),
+			})
+		}
+	}
+
+	return issues
+}
+
+// validateRecursion detecta ciclos entre subflows locais (A invoca B que invoca A)
+func (s *SubflowStep) validateRecursion(subflows map[string]io.Subflow, ids []string) []Issue {
+	var issues []Issue
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(subflows))
+	reported := make(map[string]bool)
+
+	var visit func(id string, stack []string)
+	visit = func(id string, stack []string) {
+		state[id] = visiting
+		stack = append(stack, id)
+
+		sf := subflows[id]
+		for _, n := range sf.Graph.Nodes {
+			if n.Kind != "subflow" {
+				continue
+			}
+			ref, _ := sf.ResolveProps(n)["ref"].(string)
+			if _, ok := subflows[ref]; !ok {
+				continue
+			}
+			switch state[ref] {
+			case visiting:
+				if !reported[ref] {
+					reported[ref] = true
+					issues = append(issues, Issue{
+						Code: "subflow.recursion", Severity: Err,
+						Path: "subflows." + ref,
+						Msg:  fmt.Sprintf("recursive subflow reference: %s -> %s", strings.Join(stack, " -> "), ref),
+					})
+				}
+			case unvisited:
+				visit(ref, stack)
+			}
+		}
+		state[id] = done
+	}
+
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id, nil)
+		}
+	}
+
+	return issues
+}
@@ -140,12 +140,13 @@ func (w *WhatsAppLimitsStep) validateListPicker(basePath string, props map[strin
 
 				// Itens da seção: máximo 10 por seção
 				if items, ok := sectionMap["items"].([]any); ok {
+					// Listas maiores são paginadas pelo adapter (regra listpicker_paginate)
 					if len(items) > 10 {
 						issues = append(issues, Issue{
 							Code:     "whatsapp.section.items.max_count",
-							Severity: Err,
+							Severity: Info,
 							Path:     sectionPath + ".items",
-							Msg:      fmt.Sprintf("Máximo 10 itens por seção (encontrado: %d)", len(items)),
+							Msg:      fmt.Sprintf("Máximo 10 itens por seção (encontrado: %d) - a lista será paginada", len(items)),
 						})
 					}
 
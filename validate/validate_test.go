@@ -0,0 +1,329 @@
+package validate_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/AgendoCerto/lib-bot/flow"
+	"github.com/AgendoCerto/lib-bot/hsm"
+	"github.com/AgendoCerto/lib-bot/io"
+	"github.com/AgendoCerto/lib-bot/validate"
+)
+
+// issueCodes conta as issues por código
+func issueCodes(issues []validate.Issue) map[string]int {
+	codes := map[string]int{}
+	for _, is := range issues {
+		codes[is.Code]++
+	}
+	return codes
+}
+
+// subflowCalling subflow de um nó que invoca ref e sai por "done"
+func subflowCalling(id, ref string) io.Subflow {
+	return io.Subflow{
+		ID: id, Entry: "call", Outputs: []string{"done"},
+		Graph: io.Graph{
+			Nodes: []flow.Node{{ID: "call", Kind: "subflow", Props: map[string]any{"ref": ref}}},
+			Edges: []flow.Edge{{From: "call", To: "@done", Label: "done"}},
+		},
+	}
+}
+
+func TestSubflowStepRecursion(t *testing.T) {
+	design := io.DesignDoc{
+		Subflows: map[string]io.Subflow{
+			"a":    subflowCalling("a", "b"),
+			"b":    subflowCalling("b", "c"),
+			"c":    subflowCalling("c", "a"),
+			"d":    subflowCalling("d", "self"),
+			"self": subflowCalling("self", "self"),
+		},
+		Graph: io.Graph{
+			Nodes: []flow.Node{
+				{ID: "start", Kind: "subflow", Props: map[string]any{"ref": "a"}},
+				{ID: "end", Kind: "message", Props: map[string]any{"text": "fim"}},
+			},
+			Edges: []flow.Edge{{From: "start", To: "end", Label: "done"}},
+		},
+	}
+
+	issues := validate.NewSubflowStep().ValidateDesign(design)
+	var recursion []validate.Issue
+	for _, is := range issues {
+		if is.Code == "subflow.recursion" {
+			recursion = append(recursion, is)
+		}
+	}
+	if len(recursion) != 2 || recursion[0].Path != "subflows.a" || recursion[0].Severity != validate.Err ||
+		recursion[0].Msg != "recursive subflow reference: a -> b -> c -> a" ||
+		recursion[1].Path != "subflows.self" {
+		t.Errorf("recursion issues = %+v", recursion)
+	}
+
+	// Sem ciclo: nenhuma issue de recursão
+	delete(design.Subflows, "self")
+	design.Subflows["c"] = io.Subflow{
+		ID: "c", Entry: "msg", Outputs: []string{"done"},
+		Graph: io.Graph{
+			Nodes: []flow.Node{{ID: "msg", Kind: "message", Props: map[string]any{"text": "oi"}}},
+			Edges: []flow.Edge{{From: "msg", To: "@done", Label: "complete"}},
+		},
+	}
+	design.Subflows["d"] = subflowCalling("d", "a")
+	if codes := issueCodes(validate.NewSubflowStep().ValidateDesign(design)); codes["subflow.recursion"] != 0 || codes["subflow.ref.not_found"] != 0 {
+		t.Errorf("acyclic issues = %v", codes)
+	}
+}
+
+func TestSubflowStepInterface(t *testing.T) {
+	design := io.DesignDoc{
+		Subflows: map[string]io.Subflow{
+			"confirm": {
+				ID: "confirm", Entry: "ask", Inputs: []string{"question"}, Outputs: []string{"yes", "no"},
+				Graph: io.Graph{
+					Nodes: []flow.Node{{ID: "ask", Kind: "confirm"}},
+					Edges: []flow.Edge{
+						{From: "ask", To: "@yes", Label: "confirmed"},
+						{From: "ask", To: "@maybe", Label: "cancelled"},
+					},
+				},
+			},
+		},
+		Graph: io.Graph{
+			Nodes: []flow.Node{
+				{ID: "call", Kind: "subflow", Props: map[string]any{"ref": "confirm", "inputs": map[string]any{"other": "x"}}},
+				{ID: "ghost", Kind: "subflow", Props: map[string]any{"ref": "ghost"}},
+				{ID: "end", Kind: "message"},
+			},
+			Edges: []flow.Edge{{From: "call", To: "end", Label: "later"}},
+		},
+	}
+
+	codes := issueCodes(validate.NewSubflowStep().ValidateDesign(design))
+	for _, code := range []string{
+		"subflow.exit.undeclared",     // @maybe não está em outputs
+		"subflow.input.missing",       // question não foi passado
+		"subflow.input.unknown",       // other não é input declarado
+		"subflow.edge.unknown_output", // "later" não é output do subflow
+		"subflow.ref.not_found",       // ghost
+	} {
+		if codes[code] == 0 {
+			t.Errorf("missing %s in %v", code, codes)
+		}
+	}
+}
+
+// allDay jornada de 24h em todos os dias da semana
+func allDay() map[string][]io.HoursRange {
+	weekly := map[string][]io.HoursRange{}
+	for _, day := range []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"} {
+		weekly[day] = []io.HoursRange{{Start: "00:00", End: "24:00"}}
+	}
+	return weekly
+}
+
+func TestBusinessHoursStep(t *testing.T) {
+	handoff := func(props map[string]any) io.Graph {
+		return io.Graph{Nodes: []flow.Node{{ID: "human", Kind: "human_handoff", Props: props}}}
+	}
+	office := &io.BusinessHours{Weekly: map[string][]io.HoursRange{"mon": {{Start: "09:00", End: "18:00"}}}}
+
+	cases := []struct {
+		name  string
+		hours *io.BusinessHours
+		props map[string]any
+		want  string
+	}{
+		{name: "no calendar", props: map[string]any{}},
+		{name: "always open", hours: &io.BusinessHours{Weekly: allDay()}, props: map[string]any{}},
+		{name: "holiday closes", hours: &io.BusinessHours{Weekly: allDay(), Holidays: []string{"2026-12-25"}}, props: map[string]any{}, want: "handoff.off_hours_message.missing"},
+		{name: "missing message", hours: office, props: map[string]any{}, want: "handoff.off_hours_message.missing"},
+		{name: "blank message", hours: office, props: map[string]any{"off_hours_message": "  "}, want: "handoff.off_hours_message.missing"},
+		{name: "with message", hours: office, props: map[string]any{"off_hours_message": "Voltamos amanhã às 9h"}},
+		{name: "invalid calendar", hours: &io.BusinessHours{Weekly: map[string][]io.HoursRange{"mon": {{Start: "18:00", End: "09:00"}}}}, props: map[string]any{}, want: "business_hours.invalid"},
+		{name: "unknown weekday", hours: &io.BusinessHours{Weekly: map[string][]io.HoursRange{"seg": {{Start: "09:00", End: "18:00"}}}}, props: map[string]any{}, want: "business_hours.invalid"},
+	}
+	for _, c := range cases {
+		design := io.DesignDoc{BusinessHours: c.hours, Graph: handoff(c.props)}
+		issues := validate.NewBusinessHoursStep().ValidateDesign(design)
+		if c.want == "" {
+			if len(issues) != 0 {
+				t.Errorf("%s: issues = %+v", c.name, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 || issues[0].Code != c.want || issues[0].Severity != validate.Err {
+			t.Errorf("%s: issues = %+v, want %s", c.name, issues, c.want)
+		}
+	}
+}
+
+func TestHSMRegistryStep(t *testing.T) {
+	registry, err := hsm.NewRegistry(hsm.Template{
+		Name: "appointment_reminder", Language: "pt_BR",
+		Components: []hsm.TemplateComponent{
+			{Type: "BODY", Text: "Olá {{1}}, seu horário é {{2}}"},
+			{Type: "BUTTONS", Buttons: []hsm.TemplateButton{{Type: hsm.ButtonQuickReply, Text: "Confirmar"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := validate.NewHSMRegistryStep(registry)
+	vars := map[string]any{"1": "{{state.name}}", "2": "{{context.slot}}"}
+
+	cases := []struct {
+		name string
+		node flow.Node
+		want string
+	}{
+		{name: "trigger ok", node: flow.Node{Kind: "hsm_trigger", Props: map[string]any{"template_id": "appointment_reminder", "language": "pt-BR", "variables": vars}}},
+		{name: "session language", node: flow.Node{Kind: "hsm_trigger", Props: map[string]any{"template_id": "appointment_reminder", "language": "auto", "variables": vars}}},
+		{name: "unknown template", node: flow.Node{Kind: "hsm_trigger", Props: map[string]any{"template_id": "promo", "language": "pt_BR"}}, want: "hsm.template.unknown"},
+		{name: "wrong language", node: flow.Node{Kind: "hsm_trigger", Props: map[string]any{"template_id": "appointment_reminder", "language": "en_US", "variables": vars}}, want: "hsm.template.language"},
+		{name: "variable count", node: flow.Node{Kind: "hsm_trigger", Props: map[string]any{"template_id": "appointment_reminder", "language": "pt_BR", "variables": map[string]any{"1": "x"}}}, want: "hsm.template.params"},
+		{name: "ref ok", node: flow.Node{Kind: "message", Props: map[string]any{"hsm_ref": map[string]any{
+			"id": "appointment_reminder", "locale": "pt_BR", "params": []any{"a", "b"}, "buttons": []any{map[string]any{"kind": "reply"}},
+		}}}},
+		{name: "ref button type", node: flow.Node{Kind: "message", Props: map[string]any{"hsm_ref": map[string]any{
+			"id": "appointment_reminder", "locale": "pt_BR", "params": []any{"a", "b"}, "buttons": []any{map[string]any{"kind": "url"}},
+		}}}, want: "hsm.template.button"},
+		{name: "ref extra button", node: flow.Node{Kind: "message", Props: map[string]any{"hsm_ref": map[string]any{
+			"id": "appointment_reminder", "locale": "pt_BR", "params": []any{"a", "b"}, "buttons": []any{map[string]any{"kind": "reply"}, map[string]any{"kind": "reply"}},
+		}}}, want: "hsm.template.button"},
+		{name: "message hsm name only", node: flow.Node{Kind: "message", Props: map[string]any{"hsm": map[string]any{"name": "appointment_reminder"}}}},
+		{name: "message hsm unknown", node: flow.Node{Kind: "message", Props: map[string]any{"hsm": map[string]any{"name": "promo"}}}, want: "hsm.template.unknown"},
+	}
+	for _, c := range cases {
+		c.node.ID = "n"
+		issues := step.ValidateDesign(io.DesignDoc{Graph: io.Graph{Nodes: []flow.Node{c.node}}})
+		if c.want == "" {
+			if len(issues) != 0 {
+				t.Errorf("%s: issues = %+v", c.name, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 || issues[0].Code != c.want || issues[0].Severity != validate.Err {
+			t.Errorf("%s: issues = %+v, want %s", c.name, issues, c.want)
+		}
+	}
+
+	if issues := validate.NewHSMRegistryStep(nil).ValidateDesign(io.DesignDoc{Graph: io.Graph{Nodes: []flow.Node{cases[2].node}}}); issues != nil {
+		t.Errorf("nil registry issues = %+v", issues)
+	}
+}
+
+func TestServiceWindowStep(t *testing.T) {
+	node := func(id flow.ID, kind string, props map[string]any) flow.Node {
+		return flow.Node{ID: id, Kind: kind, Props: props}
+	}
+	delay := func(duration any, unit string) flow.Node {
+		return node("wait", "delay", map[string]any{"duration": duration, "unit": unit})
+	}
+	text := map[string]any{"text": "Ainda está aí?"}
+
+	cases := []struct {
+		name     string
+		channels []string
+		nodes    []flow.Node
+		edges    []flow.Edge
+		want     []string // Nós alertados
+	}{
+		{
+			name:  "free-form after 24h delay",
+			nodes: []flow.Node{delay(24, "hours"), node("nudge", "message", text)},
+			edges: []flow.Edge{{From: "wait", To: "nudge", Label: "complete"}},
+			want:  []string{"nudge"},
+		},
+		{
+			name:  "delay in days, string duration",
+			nodes: []flow.Node{delay("2", "d"), node("nudge", "message", text)},
+			edges: []flow.Edge{{From: "wait", To: "nudge", Label: "complete"}},
+			want:  []string{"nudge"},
+		},
+		{
+			name:  "short delay",
+			nodes: []flow.Node{delay(23, "hours"), node("nudge", "message", text)},
+			edges: []flow.Edge{{From: "wait", To: "nudge", Label: "complete"}},
+		},
+		{
+			name:  "template after delay",
+			nodes: []flow.Node{delay(1, "days"), node("nudge", "hsm_trigger", map[string]any{"template_id": "reminder"}), node("ref", "message", map[string]any{"hsm_ref": map[string]any{"id": "reminder"}})},
+			edges: []flow.Edge{{From: "wait", To: "nudge", Label: "complete"}, {From: "nudge", To: "ref", Label: "sent"}},
+		},
+		{
+			name: "free-form behind interactive node",
+			nodes: []flow.Node{
+				delay(1, "days"),
+				node("ask", "buttons", map[string]any{"text": "Podemos continuar?"}),
+				node("after", "message", text),
+			},
+			edges: []flow.Edge{{From: "wait", To: "ask", Label: "complete"}, {From: "ask", To: "after", Label: "yes"}},
+			want:  []string{"ask"},
+		},
+		{
+			name: "through silent nodes",
+			nodes: []flow.Node{
+				delay(1, "days"),
+				node("pause", "delay", map[string]any{"duration": 5, "unit": "s"}),
+				node("nudge", "message", text),
+			},
+			edges: []flow.Edge{{From: "wait", To: "pause", Label: "complete"}, {From: "pause", To: "nudge", Label: "complete"}},
+			want:  []string{"nudge"},
+		},
+		{
+			name: "delay with message",
+			nodes: []flow.Node{
+				delay(1, "days"),
+				node("pause", "delay", map[string]any{"duration": 5, "unit": "s", "message": "Verificando sua agenda..."}),
+				node("nudge", "hsm_trigger", map[string]any{"template_id": "reminder"}),
+			},
+			edges: []flow.Edge{{From: "wait", To: "pause", Label: "complete"}, {From: "pause", To: "nudge", Label: "complete"}},
+			want:  []string{"pause"},
+		},
+		{
+			name: "timeout output only",
+			nodes: []flow.Node{
+				node("ask", "buttons", map[string]any{"text": "Confirma?", "timeout": map[string]any{"duration": 86400}}),
+				node("late", "message", text),
+				node("answered", "message", text),
+			},
+			edges: []flow.Edge{{From: "ask", To: "late", Label: "timeout"}, {From: "ask", To: "answered", Label: "yes"}},
+			want:  []string{"late"},
+		},
+		{
+			name: "short timeout",
+			nodes: []flow.Node{
+				node("ask", "buttons", map[string]any{"text": "Confirma?", "behavior": map[string]any{"timeout": map[string]any{"duration": 3600}}}),
+				node("late", "message", text),
+			},
+			edges: []flow.Edge{{From: "ask", To: "late", Label: "timeout"}},
+		},
+		{
+			name:     "other channel",
+			channels: []string{"telegram"},
+			nodes:    []flow.Node{delay(24, "hours"), node("nudge", "message", text)},
+			edges:    []flow.Edge{{From: "wait", To: "nudge", Label: "complete"}},
+		},
+	}
+	for _, c := range cases {
+		design := io.DesignDoc{Bot: io.Bot{Channels: c.channels}, Graph: io.Graph{Nodes: c.nodes, Edges: c.edges}}
+		issues := validate.NewServiceWindowStep().ValidateDesign(design)
+		var got []string
+		for _, is := range issues {
+			if is.Code != "whatsapp.service_window.free_form" || is.Severity != validate.Warn {
+				t.Errorf("%s: unexpected issue %+v", c.name, is)
+			}
+			for i, n := range c.nodes {
+				if is.Path == fmt.Sprintf("graph.nodes[%d]", i) {
+					got = append(got, string(n.ID))
+				}
+			}
+		}
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: warned %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Clock fonte de tempo do cache (injetável em testes)
+type Clock func() time.Time
+
+// DefaultHookCacheCapacity capacidade do cache compartilhado padrão
+const DefaultHookCacheCapacity = 1024
+
+var defaultHookCache = NewHookCache(DefaultHookCacheCapacity, nil)
+
+// DefaultHookCache retorna o cache compartilhado usado por NewValidator
+func DefaultHookCache() *HookCache { return defaultHookCache }
+
+// HookCacheStats métricas do cache de respostas de hook
+type HookCacheStats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`   // Removidos por capacidade (LRU)
+	Expirations uint64 `json:"expirations"` // Removidos por TTL
+	Size        int    `json:"size"`
+	Capacity    int    `json:"capacity"`
+}
+
+// HookCache cache LRU com TTL de respostas de hook, seguro para uso concorrente
+// Pode ser compartilhado entre várias instâncias de Validator (ver WithHookCache)
+type HookCache struct {
+	mu       sync.Mutex
+	capacity int
+	now      Clock
+	order    *list.List // frente = mais recente
+	items    map[string]*list.Element
+	stats    HookCacheStats
+}
+
+type hookCacheItem struct {
+	key       string
+	response  HookResponse
+	expiresAt time.Time
+}
+
+// NewHookCache cria cache com capacidade máxima de entradas (<=0 usa o padrão) e relógio (nil usa time.Now)
+func NewHookCache(capacity int, clock Clock) *HookCache {
+	if capacity <= 0 {
+		capacity = DefaultHookCacheCapacity
+	}
+	if clock == nil {
+		clock = time.Now
+	}
+	return &HookCache{
+		capacity: capacity,
+		now:      clock,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// HookCacheKey monta a chave a partir de método, URL e payload enviado (variáveis selecionadas)
+func HookCacheKey(method, url string, payload []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(url))
+	h.Write([]byte{'\n'})
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get busca uma resposta válida (não expirada)
+func (c *HookCache) Get(key string) (HookResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return HookResponse{}, false
+	}
+
+	item := el.Value.(*hookCacheItem)
+	if !c.now().Before(item.expiresAt) {
+		c.removeElement(el)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return HookResponse{}, false
+	}
+
+	c.order.MoveToFront(el)
+	c.stats.Hits++
+	return item.response, true
+}
+
+// Set armazena uma resposta por ttl; remove a entrada menos usada quando cheio
+func (c *HookCache) Set(key string, resp HookResponse, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		item := el.Value.(*hookCacheItem)
+		item.response = resp
+		item.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&hookCacheItem{key: key, response: resp, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Purge remove todas as entradas (métricas são mantidas)
+func (c *HookCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// Stats retorna um snapshot das métricas
+func (c *HookCache) Stats() HookCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Size = c.order.Len()
+	s.Capacity = c.capacity
+	return s
+}
+
+func (c *HookCache) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*hookCacheItem).key)
+}
@@ -0,0 +1,253 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Decision resultado estruturado da validação, com o rastro de avaliação das rotas
+// Permite diagnosticar por que uma entrada ("sim") não bateu em nenhuma rota
+type Decision struct {
+	Output     string         `json:"output"`                // Output escolhido
+	RouteIndex int            `json:"route_index"`           // Índice da rota escolhida (-1 = default_output)
+	Mode       string         `json:"mode,omitempty"`        // Modos que decidiram (ex: "regex+tags", "hook")
+	Input      string         `json:"input,omitempty"`       // Primeira entrada avaliada, já normalizada
+	FuzzyScore float64        `json:"fuzzy_score,omitempty"` // Maior score observado em tags com strategy fuzzy
+	Message    string         `json:"message,omitempty"`     // Mensagem retornada por hook
+	Intents    []IntentScore  `json:"intents,omitempty"`     // Top-N intents da primeira classificação
+	Context    map[string]any `json:"context,omitempty"`     // Variáveis para mesclar no context da sessão (intent, intent_confidence, intents)
+	Entities   []Entity       `json:"entities,omitempty"`    // Entidades extraídas pela rota escolhida
+	Writes     []EntityWrite  `json:"writes,omitempty"`      // Gravações de persistência das entidades (aplicadas pelo KeyWriter, se houver)
+	Default    bool           `json:"default"`               // true quando nenhuma rota bateu (ou validator desabilitado)
+	Routes     []RouteTrace   `json:"routes,omitempty"`      // Avaliação de cada rota (na ordem)
+}
+
+// RouteTrace avaliação de uma rota
+type RouteTrace struct {
+	Index    int         `json:"index"`
+	Go       string      `json:"go"`
+	Matched  bool        `json:"matched"`
+	Modes    []ModeTrace `json:"modes"`              // Modos avaliados até o primeiro que falhou
+	Redirect string      `json:"redirect,omitempty"` // Output alternativo decidido pela rota (ex: entity.invalid_output)
+	Error    string      `json:"error,omitempty"`
+
+	writes []EntityWrite // Gravações das entidades da rota
+}
+
+// ModeTrace avaliação de um modo dentro da rota
+type ModeTrace struct {
+	Mode    string        `json:"mode"`              // regex|tags|rules|expr|hook|intent|entity
+	Field   string        `json:"field,omitempty"`   // Campo avaliado
+	Input   string        `json:"input,omitempty"`   // Valor normalizado do campo
+	Pattern string        `json:"pattern,omitempty"` // Regex, estratégia de tags, lógica das rules ou URL do hook
+	Term    string        `json:"term,omitempty"`    // Termo de tags que bateu (ou o mais próximo, no fuzzy)
+	Score   float64       `json:"score,omitempty"`   // Similaridade (tags fuzzy) ou confiança do intent principal
+	Intents []IntentScore `json:"intents,omitempty"` // Top-N intents (modo intent)
+	Entity  *Entity       `json:"entity,omitempty"`  // Entidade extraída (modo entity)
+	Matched bool          `json:"matched"`
+	Detail  string        `json:"detail,omitempty"` // Informações adicionais (ex: resposta do hook)
+}
+
+// Decide executa a validação e retorna a decisão com o rastro completo
+func (v *Validator) Decide(ctx context.Context) (Decision, error) {
+	d := Decision{RouteIndex: -1}
+	if !v.config.Enabled {
+		d.Output, d.Default = v.config.DefaultOutput, true
+		return d, nil
+	}
+
+	defer func() {
+		v.last.mu.Lock()
+		v.last.text = d.Message
+		v.last.mu.Unlock()
+	}()
+
+	// Avaliar rotas na ordem
+	for i, route := range v.config.Routes {
+		trace, hook, err := v.evaluateRoute(ctx, i, route)
+		d.Routes = append(d.Routes, trace)
+		d.observe(trace)
+		if err != nil {
+			return d, fmt.Errorf("error evaluating route %s: %w", route.Go, err)
+		}
+
+		if hook != nil && hook.Message != "" {
+			d.Message = hook.Message
+		}
+		if hook != nil && hook.Route != "" {
+			d.Output, d.RouteIndex, d.Mode = hook.Route, i, "hook"
+			return d, nil
+		}
+		if trace.Redirect != "" {
+			d.Output, d.RouteIndex, d.Mode = trace.Redirect, i, trace.modeNames()
+			return d, nil
+		}
+		if trace.Matched {
+			d.Output, d.RouteIndex, d.Mode = route.Go, i, trace.modeNames()
+			return d, v.persist(ctx, &d, trace)
+		}
+	}
+
+	d.Output, d.Default = v.config.DefaultOutput, true
+	return d, nil
+}
+
+// persist registra as entidades da rota escolhida e grava no KeyWriter configurado
+func (v *Validator) persist(ctx context.Context, d *Decision, t RouteTrace) error {
+	for _, m := range t.Modes {
+		if m.Entity != nil {
+			d.Entities = append(d.Entities, *m.Entity)
+		}
+	}
+	d.Writes = t.writes
+
+	if v.store == nil {
+		return nil
+	}
+	for _, w := range t.writes {
+		if err := v.store.Set(ctx, w.Scope, w.Key, w.Value); err != nil {
+			return fmt.Errorf("persist entity %s: %w", w.Key, err)
+		}
+	}
+	return nil
+}
+
+// observe registra a primeira entrada normalizada e o maior score das tags fuzzy
+func (d *Decision) observe(t RouteTrace) {
+	for _, m := range t.Modes {
+		if d.Input == "" && m.Input != "" {
+			d.Input = m.Input
+		}
+		if m.Mode == "tags" && m.Pattern == "fuzzy" && m.Score > d.FuzzyScore {
+			d.FuzzyScore = m.Score
+		}
+		if m.Mode == "intent" && d.Context == nil {
+			d.Intents = m.Intents
+			d.Context = intentContext(m.Intents)
+		}
+	}
+}
+
+// modeNames junta os modos avaliados da rota (ex: "regex+tags")
+func (t RouteTrace) modeNames() string {
+	names := make([]string, 0, len(t.Modes))
+	for _, m := range t.Modes {
+		if len(names) == 0 || names[len(names)-1] != m.Mode {
+			names = append(names, m.Mode)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// evaluateRoute avalia uma rota específica (todos os modos devem bater)
+// hook é a resposta do hook da rota (Route/Message), quando avaliado
+func (v *Validator) evaluateRoute(ctx context.Context, index int, route Route) (RouteTrace, *HookResponse, error) {
+	modes := route.Modes
+	trace := RouteTrace{Index: index, Go: route.Go}
+	fail := func(err error) (RouteTrace, *HookResponse, error) {
+		if err != nil {
+			trace.Error = err.Error()
+		}
+		return trace, nil, err
+	}
+
+	// Avaliar regex
+	for _, regex := range modes.Regex {
+		input, _ := v.fieldText(regex.Field)
+		matched, err := v.evaluateRegex(regex)
+		trace.Modes = append(trace.Modes, ModeTrace{Mode: "regex", Field: regex.Field, Input: input, Pattern: regex.Pattern, Matched: matched})
+		if err != nil || !matched {
+			return fail(err)
+		}
+	}
+
+	// Avaliar tags
+	for _, tags := range modes.Tags {
+		input, _ := v.fieldText(tags.Field)
+		matched, term, score := v.evaluateTags(tags)
+		trace.Modes = append(trace.Modes, ModeTrace{Mode: "tags", Field: tags.Field, Input: input, Pattern: tags.Strategy, Term: term, Score: score, Matched: matched})
+		if !matched {
+			return fail(nil)
+		}
+	}
+
+	// Avaliar rules
+	if modes.Rules != nil {
+		matched, err := v.evaluateRules(modes.Rules)
+		trace.Modes = append(trace.Modes, ModeTrace{Mode: "rules", Pattern: modes.Rules.Logic, Matched: matched, Detail: fmt.Sprintf("%d rule(s)", len(modes.Rules.All))})
+		if err != nil || !matched {
+			return fail(err)
+		}
+	}
+
+	// Avaliar expr
+	if modes.Expr != "" {
+		matched, err := v.evaluateExpr(modes.Expr)
+		trace.Modes = append(trace.Modes, ModeTrace{Mode: "expr", Pattern: modes.Expr, Matched: matched})
+		if err != nil || !matched {
+			return fail(err)
+		}
+	}
+
+	// Avaliar intent
+	if modes.Intent != nil {
+		input, _ := v.fieldText(modes.Intent.Field)
+		matched, scores, err := v.evaluateIntent(ctx, modes.Intent)
+		mt := ModeTrace{Mode: "intent", Field: modes.Intent.Field, Input: input, Pattern: strings.Join(modes.Intent.Intents, "|"), Intents: scores, Matched: matched}
+		if len(scores) > 0 {
+			mt.Term, mt.Score = scores[0].Intent, scores[0].Confidence
+		}
+		trace.Modes = append(trace.Modes, mt)
+		if err != nil || !matched {
+			return fail(err)
+		}
+	}
+
+	// Avaliar entity
+	for _, mode := range modes.Entity {
+		input, _ := v.fieldText(mode.Field)
+		entity, write, err := v.evaluateEntity(mode)
+		mt := ModeTrace{Mode: "entity", Field: mode.Field, Input: input, Pattern: string(mode.Type), Entity: entity, Matched: err == nil}
+		if err != nil {
+			mt.Detail = err.Error()
+		}
+		trace.Modes = append(trace.Modes, mt)
+
+		switch {
+		case errors.Is(err, ErrEntityInvalid):
+			trace.Redirect = mode.InvalidOutput
+			return fail(nil)
+		case errors.Is(err, ErrEntityNotFound):
+			return fail(nil)
+		case err != nil:
+			return fail(err)
+		}
+		if write != nil {
+			trace.writes = append(trace.writes, *write)
+		}
+	}
+
+	// Avaliar hook
+	var hook *HookResponse
+	if modes.Hook != nil {
+		resp, err := v.evaluateHook(ctx, modes.Hook)
+		mt := ModeTrace{Mode: "hook", Pattern: modes.Hook.URL}
+		if err != nil {
+			trace.Modes = append(trace.Modes, mt)
+			return fail(err)
+		}
+		mt.Matched = resp.Valid
+		mt.Detail = fmt.Sprintf("valid=%t route=%q", resp.Valid, resp.Route)
+		trace.Modes = append(trace.Modes, mt)
+
+		hook = resp
+		if resp.Route != "" || !resp.Valid {
+			return trace, hook, nil
+		}
+	}
+
+	trace.Matched = true
+	return trace, hook, nil
+}
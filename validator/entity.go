@@ -0,0 +1,629 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/AgendoCerto/lib-bot/persistence"
+	"github.com/AgendoCerto/lib-bot/sanitize"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// EntityType tipo de entidade extraída pelo modo entity
+type EntityType string
+
+const (
+	EntityDate     EntityType = "date"     // "amanhã", "sexta", "15/10", "15 de outubro"
+	EntityTime     EntityType = "time"     // "15h", "15:30", "3 da tarde", "meio-dia"
+	EntityDateTime EntityType = "datetime" // "amanhã às 15h" (sem data assume hoje, ou amanhã se o horário já passou)
+	EntityNumber   EntityType = "number"   // "42", "1.234,5", "vinte e cinco"
+	EntityMoney    EntityType = "money"    // "R$ 120,50", "120 reais", "cem reais e cinquenta centavos"
+	EntityCPF      EntityType = "cpf"      // Com verificação dos dígitos
+	EntityCNPJ     EntityType = "cnpj"     // Numérico ou alfanumérico, com verificação dos dígitos
+	EntityPhone    EntityType = "phone"    // Telefone em E.164; sem DDI usa a região do modo (padrão BR)
+)
+
+// DefaultEntityTimezone fuso usado para datas relativas quando o modo não define Timezone
+const DefaultEntityTimezone = "America/Sao_Paulo"
+
+// Erros de extração (a rota usa InvalidOutput quando a entrada tem a entidade, mas ela é inválida)
+var (
+	ErrEntityNotFound = errors.New("entity not found")
+	ErrEntityInvalid  = errors.New("invalid entity")
+)
+
+// EntityMode valida extraindo um valor tipado do texto
+// A rota bate quando a entidade é extraída; se a entrada contém a entidade mas ela é inválida
+// (ex: CPF com dígito verificador errado, 31/02) e InvalidOutput está definido, decide por InvalidOutput
+type EntityMode struct {
+	Field         string              `json:"field"`                    // Campo com o texto (ex: "context.user_text")
+	Type          EntityType          `json:"type"`                     // Tipo da entidade
+	Timezone      string              `json:"timezone,omitempty"`       // Fuso para datas relativas (padrão America/Sao_Paulo)
+	Region        string              `json:"region,omitempty"`         // Região dos telefones sem DDI (ISO, ex: "US"; padrão BR)
+	RegionField   string              `json:"region_field,omitempty"`   // Campo da sessão com a região (ex: "context.region"); tem prioridade sobre Region
+	InvalidOutput string              `json:"invalid_output,omitempty"` // Output quando a entidade é inválida
+	Persistence   *persistence.Config `json:"persistence,omitempty"`    // Onde gravar o valor extraído
+}
+
+// Entity valor extraído da entrada
+type Entity struct {
+	Type      EntityType `json:"type"`
+	Text      string     `json:"text"`      // Trecho reconhecido na entrada
+	Value     any        `json:"value"`     // Valor tipado: date "2006-01-02", time "15:04", datetime RFC3339, number/money float64, documentos e telefone só dígitos
+	Formatted string     `json:"formatted"` // Valor formatado (gravado na persistência quando não há sanitização)
+}
+
+// EntityWrite gravação de uma entidade na persistência
+type EntityWrite struct {
+	Scope persistence.Scope `json:"scope"`
+	Key   string            `json:"key"`
+	Value string            `json:"value"`
+
+	Sanitization []persistence.SanitizationStep `json:"sanitization,omitempty"` // Trace das etapas de sanitização aplicadas
+}
+
+// entitySanitizer engine compartilhado de sanitize (formatters de cpf, cnpj, phone, monetary_brl e sanitização da persistência)
+var entitySanitizer = sanitize.Default()
+
+// evaluateEntity extrai a entidade do campo e prepara a gravação configurada
+func (v *Validator) evaluateEntity(mode EntityMode) (*Entity, *EntityWrite, error) {
+	raw, ok := v.getFieldValue(mode.Field).(string)
+	if !ok || strings.TrimSpace(raw) == "" {
+		return nil, nil, ErrEntityNotFound
+	}
+
+	tz := mode.Timezone
+	if tz == "" {
+		tz = DefaultEntityTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, nil, fmt.Errorf("entity timezone: %w", err)
+	}
+
+	entity, err := extractEntity(mode.Type, raw, v.clock().In(loc), v.entityRegion(mode))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := mode.Persistence
+	if p == nil || !p.Enabled {
+		return &entity, nil, nil
+	}
+
+	write := &EntityWrite{Scope: p.Scope, Key: p.Key, Value: entity.Formatted}
+	if steps := p.SanitizationSteps(); len(steps) > 0 {
+		sanitized, trace, err := entitySanitizer.SanitizePipeline(entity.Text, steps)
+		write.Sanitization = trace
+		if err != nil {
+			return &entity, nil, fmt.Errorf("%w: %v", ErrEntityInvalid, err)
+		}
+		// Sem nenhuma etapa aplicada, mantém o valor formatado da entidade
+		if slices.ContainsFunc(trace, func(step persistence.SanitizationStep) bool { return !step.Skipped }) {
+			write.Value = sanitized
+		}
+	}
+	return &entity, write, nil
+}
+
+// entityRegion região dos telefones: campo da sessão, depois Region do modo, depois sanitize.DefaultPhoneRegion
+func (v *Validator) entityRegion(mode EntityMode) string {
+	if mode.RegionField != "" {
+		if region, ok := v.getFieldValue(mode.RegionField).(string); ok && strings.TrimSpace(region) != "" {
+			return strings.TrimSpace(region)
+		}
+	}
+	if mode.Region != "" {
+		return mode.Region
+	}
+	return sanitize.DefaultPhoneRegion
+}
+
+// ExtractEntity extrai uma entidade do texto; now define a referência das datas relativas (e o fuso)
+// Telefones sem DDI são lidos na região sanitize.DefaultPhoneRegion
+// Retorna ErrEntityNotFound quando não há candidato e ErrEntityInvalid quando o candidato é inválido
+func ExtractEntity(kind EntityType, text string, now time.Time) (Entity, error) {
+	return extractEntity(kind, text, now, sanitize.DefaultPhoneRegion)
+}
+
+func extractEntity(kind EntityType, text string, now time.Time, region string) (Entity, error) {
+	folded := foldText(text)
+	switch kind {
+	case EntityDate:
+		return extractDate(folded, now)
+	case EntityTime:
+		return extractTime(folded, now)
+	case EntityDateTime:
+		return extractDateTime(folded, now)
+	case EntityNumber:
+		return extractNumber(folded)
+	case EntityMoney:
+		return extractMoney(folded)
+	case EntityCPF:
+		return extractDocument(text, EntityCPF, cpfPattern, onlyDigits, persistence.SanitizeCPF)
+	case EntityCNPJ:
+		return extractDocument(text, EntityCNPJ, cnpjPattern, sanitize.NormalizeCNPJ, persistence.SanitizeCNPJ)
+	case EntityPhone:
+		return extractPhone(text, region)
+	default:
+		return Entity{}, fmt.Errorf("unsupported entity type: %s", kind)
+	}
+}
+
+// foldText minúsculas sem acentos, preservando posições de palavras (ex: "Amanhã às 15h" -> "amanha as 15h")
+func foldText(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, _ := transform.String(t, strings.ToLower(text))
+	return folded
+}
+
+// Datas
+
+var (
+	monthNames = map[string]time.Month{
+		"janeiro": time.January, "fevereiro": time.February, "marco": time.March, "abril": time.April,
+		"maio": time.May, "junho": time.June, "julho": time.July, "agosto": time.August,
+		"setembro": time.September, "outubro": time.October, "novembro": time.November, "dezembro": time.December,
+	}
+	weekdayNames = map[string]time.Weekday{
+		"domingo": time.Sunday, "segunda": time.Monday, "terca": time.Tuesday, "quarta": time.Wednesday,
+		"quinta": time.Thursday, "sexta": time.Friday, "sabado": time.Saturday,
+	}
+
+	isoDatePattern      = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	numericDatePattern  = regexp.MustCompile(`\b(\d{1,2})[/.-](\d{1,2})(?:[/.-](\d{2}|\d{4}))?\b`)
+	writtenDatePattern  = regexp.MustCompile(`\b(\d{1,2})\s+de\s+(janeiro|fevereiro|marco|abril|maio|junho|julho|agosto|setembro|outubro|novembro|dezembro)(?:\s+de\s+(\d{4}))?\b`)
+	dayOfMonthPattern   = regexp.MustCompile(`\bdia\s+(\d{1,2})\b`)
+	weekdayPattern      = regexp.MustCompile(`\b(?:(proxim[oa])\s+)?(domingo|segunda|terca|quarta|quinta|sexta|sabado)(?:[- ]feira)?(\s+que\s+vem)?\b`)
+	relativeDayPattern  = regexp.MustCompile(`\b(depois\s+de\s+amanha|amanha|hoje|ontem)\b`)
+	relativeSpanPattern = regexp.MustCompile(`\b(?:daqui\s+a|em|dentro\s+de)\s+(\d+|[a-z]+)\s+(dias?|semanas?|mes|meses)\b`)
+)
+
+// extractDate reconhece datas explícitas e relativas em pt-BR
+// Datas sem ano que já passaram são levadas para o próximo ano
+func extractDate(text string, now time.Time) (Entity, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date, match, err := findDate(text, today)
+	if err != nil {
+		return Entity{}, err
+	}
+	iso := date.Format("2006-01-02")
+	return Entity{Type: EntityDate, Text: match, Value: iso, Formatted: iso}, nil
+}
+
+func findDate(text string, today time.Time) (time.Time, string, error) {
+	if m := isoDatePattern.FindStringSubmatch(text); m != nil {
+		return buildDate(m[0], atoi(m[1]), atoi(m[2]), atoi(m[3]), today)
+	}
+
+	if m := writtenDatePattern.FindStringSubmatch(text); m != nil {
+		year := 0
+		if m[3] != "" {
+			year = atoi(m[3])
+		}
+		return buildDate(m[0], year, int(monthNames[m[2]]), atoi(m[1]), today)
+	}
+
+	if m := numericDatePattern.FindStringSubmatch(text); m != nil {
+		year := 0
+		switch len(m[3]) {
+		case 2:
+			year = 2000 + atoi(m[3])
+		case 4:
+			year = atoi(m[3])
+		}
+		return buildDate(m[0], year, atoi(m[2]), atoi(m[1]), today)
+	}
+
+	if m := relativeDayPattern.FindStringSubmatch(text); m != nil {
+		offset := map[string]int{"ontem": -1, "hoje": 0, "amanha": 1}[m[1]]
+		if strings.HasPrefix(m[1], "depois") {
+			offset = 2
+		}
+		return today.AddDate(0, 0, offset), m[0], nil
+	}
+
+	if m := relativeSpanPattern.FindStringSubmatch(text); m != nil {
+		n, ok := countValue(m[1])
+		if !ok {
+			return time.Time{}, "", ErrEntityNotFound
+		}
+		switch {
+		case strings.HasPrefix(m[2], "dia"):
+			return today.AddDate(0, 0, n), m[0], nil
+		case strings.HasPrefix(m[2], "semana"):
+			return today.AddDate(0, 0, 7*n), m[0], nil
+		default:
+			return today.AddDate(0, n, 0), m[0], nil
+		}
+	}
+
+	if m := weekdayPattern.FindStringSubmatch(text); m != nil {
+		days := (int(weekdayNames[m[2]]) - int(today.Weekday()) + 7) % 7
+		if days == 0 && (m[1] != "" || m[3] != "") {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), m[0], nil
+	}
+
+	if m := dayOfMonthPattern.FindStringSubmatch(text); m != nil {
+		day := atoi(m[1])
+		date, match, err := buildDate(m[0], today.Year(), int(today.Month()), day, today)
+		if err == nil && date.Before(today) {
+			next := today.AddDate(0, 1, 0)
+			return buildDate(m[0], next.Year(), int(next.Month()), day, today)
+		}
+		return date, match, err
+	}
+
+	return time.Time{}, "", ErrEntityNotFound
+}
+
+// buildDate valida a data (ex: 31/02 é inválida); year 0 usa o ano corrente ou o próximo se a data já passou
+func buildDate(match string, year, month, day int, today time.Time) (time.Time, string, error) {
+	inferYear := year == 0
+	if inferYear {
+		year = today.Year()
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, today.Location())
+	if month < 1 || month > 12 || date.Day() != day || date.Month() != time.Month(month) {
+		return time.Time{}, "", fmt.Errorf("%w: date %q", ErrEntityInvalid, match)
+	}
+	if inferYear && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, match, nil
+}
+
+// Horários
+
+var (
+	noonPattern     = regexp.MustCompile(`\bmeio[- ]dia(\s+e\s+meia)?\b`)
+	midnightPattern = regexp.MustCompile(`\bmeia[- ]noite(\s+e\s+meia)?\b`)
+	// "daqui a 2 horas", "em uma hora e meia": duração a partir de agora, não horário do relógio
+	durationPattern = regexp.MustCompile(`\b(?:daqui\s+a|dentro\s+de|em|a)\s+(\d{1,2}|uma|duas|tres|quatro|cinco|seis|sete|oito|nove|dez|onze|doze)\s+horas?\b(\s+e\s+meia)?`)
+	timePattern     = regexp.MustCompile(`(?:\b(as|a)\s+)?\b(\d{1,2}|uma|duas|tres|quatro|cinco|seis|sete|oito|nove|dez|onze|doze)(?:\s*(?::|h)\s*(\d{2})\b|\s*(h|hs|hrs|horas?)\b)?(\s+e\s+meia)?(?:\s+da\s+(manha|tarde|noite|madrugada))?`)
+)
+
+// extractTime reconhece horários ("15h", "15:30", "às 3 da tarde", "meio-dia e meia")
+// Durações ("daqui a 2 horas") resultam no horário de now somado à duração
+func extractTime(text string, now time.Time) (Entity, error) {
+	if at, match, ok := findDuration(text, now); ok {
+		value := at.Format("15:04")
+		return Entity{Type: EntityTime, Text: match, Value: value, Formatted: value}, nil
+	}
+	hour, minute, match, err := findTime(text)
+	if err != nil {
+		return Entity{}, err
+	}
+	value := fmt.Sprintf("%02d:%02d", hour, minute)
+	return Entity{Type: EntityTime, Text: match, Value: value, Formatted: value}, nil
+}
+
+// findDuration reconhece "daqui a/em/a N horas (e meia)" e retorna now somado à duração (em minutos cheios)
+func findDuration(text string, now time.Time) (time.Time, string, bool) {
+	m := durationPattern.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, "", false
+	}
+	hours, ok := countValue(m[1])
+	if !ok {
+		return time.Time{}, "", false
+	}
+	d := time.Duration(hours)*time.Hour + time.Duration(halfHour(m[2]))*time.Minute
+	return now.Add(d).Truncate(time.Minute), m[0], true
+}
+
+func findTime(text string) (int, int, string, error) {
+	if m := noonPattern.FindStringSubmatch(text); m != nil {
+		return 12, halfHour(m[1]), m[0], nil
+	}
+	if m := midnightPattern.FindStringSubmatch(text); m != nil {
+		return 0, halfHour(m[1]), m[0], nil
+	}
+
+	for _, m := range timePattern.FindAllStringSubmatch(text, -1) {
+		number, minutes, suffix, half, period := m[2], m[3], m[4], m[5], m[6]
+		// Um número sozinho (mesmo após "a"/"às") não é horário: exige minutos,
+		// "h"/"horas", "e meia" ou período do dia ("a 3 dias", "a 1 semana" são ignorados)
+		if minutes == "" && suffix == "" && half == "" && period == "" {
+			continue
+		}
+
+		hour, ok := countValue(number)
+		if !ok {
+			continue
+		}
+		minute := halfHour(half)
+		if minutes != "" {
+			minute = atoi(minutes)
+		}
+		if hour > 23 || minute > 59 {
+			return 0, 0, "", fmt.Errorf("%w: time %q", ErrEntityInvalid, strings.TrimSpace(m[0]))
+		}
+
+		switch period {
+		case "tarde", "noite":
+			if hour < 12 {
+				hour += 12
+			} else if hour == 12 && period == "noite" {
+				hour = 0
+			}
+		case "manha", "madrugada":
+			if hour == 12 {
+				hour = 0
+			}
+		}
+		return hour, minute, strings.TrimSpace(m[0]), nil
+	}
+
+	return 0, 0, "", ErrEntityNotFound
+}
+
+func halfHour(group string) int {
+	if group != "" {
+		return 30
+	}
+	return 0
+}
+
+// extractDateTime combina data e horário; sem data usa hoje (ou amanhã se o horário já passou)
+// Durações ("daqui a 2 horas") resultam em now somado à duração
+func extractDateTime(text string, now time.Time) (Entity, error) {
+	if at, match, ok := findDuration(text, now); ok {
+		value := at.Format(time.RFC3339)
+		return Entity{Type: EntityDateTime, Text: match, Value: value, Formatted: value}, nil
+	}
+
+	hour, minute, timeMatch, err := findTime(text)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date, dateMatch, err := findDate(text, today)
+	switch {
+	case errors.Is(err, ErrEntityNotFound):
+		date, dateMatch = today, ""
+		if time.Date(today.Year(), today.Month(), today.Day(), hour, minute, 0, 0, today.Location()).Before(now) {
+			date = today.AddDate(0, 0, 1)
+		}
+	case err != nil:
+		return Entity{}, err
+	}
+
+	at := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location())
+	value := at.Format(time.RFC3339)
+	return Entity{Type: EntityDateTime, Text: strings.TrimSpace(dateMatch + " " + timeMatch), Value: value, Formatted: value}, nil
+}
+
+// Números
+
+var (
+	numberWords = map[string]int{
+		"zero": 0, "um": 1, "uma": 1, "dois": 2, "duas": 2, "tres": 3, "quatro": 4, "cinco": 5,
+		"seis": 6, "sete": 7, "oito": 8, "nove": 9, "dez": 10, "onze": 11, "doze": 12, "treze": 13,
+		"catorze": 14, "quatorze": 14, "quinze": 15, "dezesseis": 16, "dezessete": 17, "dezoito": 18,
+		"dezenove": 19, "vinte": 20, "trinta": 30, "quarenta": 40, "cinquenta": 50, "sessenta": 60,
+		"setenta": 70, "oitenta": 80, "noventa": 90, "cem": 100, "cento": 100, "duzentos": 200,
+		"duzentas": 200, "trezentos": 300, "trezentas": 300, "quatrocentos": 400, "quatrocentas": 400,
+		"quinhentos": 500, "quinhentas": 500, "seiscentos": 600, "seiscentas": 600, "setecentos": 700,
+		"setecentas": 700, "oitocentos": 800, "oitocentas": 800, "novecentos": 900, "novecentas": 900,
+	}
+	numberScales = map[string]float64{"mil": 1e3, "milhao": 1e6, "milhoes": 1e6, "bilhao": 1e9, "bilhoes": 1e9}
+
+	digitNumberPattern = regexp.MustCompile(`-?\d{1,3}(?:\.\d{3})+(?:,\d+)?|-?\d+(?:[.,]\d+)?`)
+	thousandsPattern   = regexp.MustCompile(`\.\d{3}$`)
+	wordPattern        = regexp.MustCompile(`[a-z]+`)
+)
+
+// extractNumber reconhece números com dígitos (formato pt-BR) ou por extenso
+func extractNumber(text string) (Entity, error) {
+	value, match, ok := findNumber(text)
+	if !ok {
+		return Entity{}, ErrEntityNotFound
+	}
+	return Entity{Type: EntityNumber, Text: match, Value: value, Formatted: strconv.FormatFloat(value, 'f', -1, 64)}, nil
+}
+
+func findNumber(text string) (float64, string, bool) {
+	if match := digitNumberPattern.FindString(text); match != "" {
+		if value, ok := parseDigits(match); ok {
+			return value, match, true
+		}
+	}
+	return parseWords(text)
+}
+
+// parseDigits interpreta "1.234,56" (pt-BR), "1234.5" e "1234,5"
+func parseDigits(s string) (float64, bool) {
+	if strings.Contains(s, ",") || strings.Count(s, ".") > 1 || thousandsPattern.MatchString(s) {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	return value, err == nil
+}
+
+// parseWords interpreta a primeira sequência de números por extenso ("mil duzentos e trinta e quatro")
+func parseWords(text string) (float64, string, bool) {
+	locs := wordPattern.FindAllStringIndex(text, -1)
+	start := -1
+	for i, loc := range locs {
+		if isNumberWord(text[loc[0]:loc[1]]) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return 0, "", false
+	}
+
+	var total, current float64
+	end := start
+	for i := start; i < len(locs); i++ {
+		word := text[locs[i][0]:locs[i][1]]
+		if word == "e" && i+1 < len(locs) && isNumberWord(text[locs[i+1][0]:locs[i+1][1]]) {
+			continue
+		}
+		if n, ok := numberWords[word]; ok {
+			current += float64(n)
+		} else if scale, ok := numberScales[word]; ok {
+			if current == 0 {
+				current = 1
+			}
+			total += current * scale
+			current = 0
+		} else {
+			break
+		}
+		end = i
+	}
+	return total + current, text[locs[start][0]:locs[end][1]], true
+}
+
+func isNumberWord(word string) bool {
+	_, n := numberWords[word]
+	_, s := numberScales[word]
+	return n || s
+}
+
+// countValue interpreta contagens curtas ("3", "tres", "uma")
+func countValue(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	n, ok := numberWords[s]
+	return n, ok
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// Dinheiro
+
+var (
+	currencyPattern = regexp.MustCompile(`r\$\s*(-?[\d.,]*\d)|(-?[\d.,]*\d)\s*(?:reais|real|brl)\b`)
+	centsPattern    = regexp.MustCompile(`\b(?:reais|real)\s+e\s+([a-z ]+?|\d{1,2})\s+centavos?\b`)
+)
+
+// extractMoney reconhece valores em reais ("R$ 120,50", "120 reais", "cem reais e cinquenta centavos")
+// Um número sem moeda também é aceito como valor em reais
+func extractMoney(text string) (Entity, error) {
+	value, match, ok := findMoney(text)
+	if !ok {
+		return Entity{}, ErrEntityNotFound
+	}
+	if value < 0 {
+		return Entity{}, fmt.Errorf("%w: negative amount %q", ErrEntityInvalid, match)
+	}
+
+	value = float64(int64(value*100+0.5)) / 100
+	formatted, err := entitySanitizer.Sanitize(strings.ReplaceAll(strconv.FormatFloat(value, 'f', 2, 64), ".", ","), persistence.SanitizationConfig{Type: persistence.SanitizeBRL})
+	if err != nil {
+		return Entity{}, fmt.Errorf("%w: %v", ErrEntityInvalid, err)
+	}
+	return Entity{Type: EntityMoney, Text: match, Value: value, Formatted: formatted}, nil
+}
+
+func findMoney(text string) (float64, string, bool) {
+	if m := currencyPattern.FindStringSubmatch(text); m != nil {
+		digits := m[1] + m[2]
+		if value, ok := parseDigits(digits); ok {
+			return value + cents(text), m[0], true
+		}
+	}
+
+	for _, unit := range []string{"reais", "real"} {
+		if i := strings.Index(text, unit); i > 0 {
+			if value, match, ok := parseWords(trailingNumberWords(text[:i])); ok {
+				return value + cents(text), match + " " + unit, true
+			}
+		}
+	}
+
+	return findNumber(text)
+}
+
+// trailingNumberWords retorna o trecho final composto só de números por extenso ("pagar cem e vinte " -> "cem e vinte")
+func trailingNumberWords(text string) string {
+	locs := wordPattern.FindAllStringIndex(text, -1)
+	start := len(text)
+	for i := len(locs) - 1; i >= 0; i-- {
+		word := text[locs[i][0]:locs[i][1]]
+		if !isNumberWord(word) && word != "e" {
+			break
+		}
+		start = locs[i][0]
+	}
+	return text[start:]
+}
+
+// cents soma "e cinquenta centavos" ao valor em reais
+func cents(text string) float64 {
+	m := centsPattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	if n, err := strconv.Atoi(m[1]); err == nil {
+		return float64(n) / 100
+	}
+	if n, _, ok := parseWords(m[1]); ok && n < 100 {
+		return n / 100
+	}
+	return 0
+}
+
+// Documentos e telefone
+
+var (
+	cpfPattern   = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}[-.\s]?\d{2}\b`)
+	cnpjPattern  = regexp.MustCompile(`(?i)\b[0-9a-z]{2}\.?[0-9a-z]{3}\.?[0-9a-z]{3}/?[0-9a-z]{4}[-.\s]?\d{2}\b`)
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().-]{7,}\d`)
+	nonDigit     = regexp.MustCompile(`\D`)
+)
+
+// extractDocument reconhece CPF/CNPJ com ou sem pontuação e verifica os dígitos com o formatter de sanitize (modo estrito)
+// Dígitos sem o formato esperado (ou com verificador errado) resultam em ErrEntityInvalid
+func extractDocument(text string, kind EntityType, pattern *regexp.Regexp, clean func(string) string, format persistence.SanitizationType) (Entity, error) {
+	match := pattern.FindString(text)
+	if match == "" {
+		if nonDigit.ReplaceAllString(text, "") == "" {
+			return Entity{}, ErrEntityNotFound
+		}
+		match = strings.TrimSpace(text)
+	}
+
+	formatted, err := entitySanitizer.Sanitize(match, persistence.SanitizationConfig{Type: format, StrictMode: true})
+	if err != nil {
+		return Entity{}, fmt.Errorf("%w: %s %q: %v", ErrEntityInvalid, kind, match, err)
+	}
+	return Entity{Type: kind, Text: match, Value: clean(match), Formatted: formatted}, nil
+}
+
+func onlyDigits(s string) string { return nonDigit.ReplaceAllString(s, "") }
+
+// extractPhone reconhece telefones e normaliza para E.164 com sanitize.ParsePhone
+// Números sem DDI são lidos em region; Value é o número nacional e Formatted o E.164
+func extractPhone(text, region string) (Entity, error) {
+	match := strings.TrimSpace(phonePattern.FindString(text))
+	if match == "" {
+		return Entity{}, ErrEntityNotFound
+	}
+
+	phone, err := sanitize.ParsePhone(match, region)
+	if err != nil {
+		return Entity{}, fmt.Errorf("%w: phone %q: %v", ErrEntityInvalid, match, err)
+	}
+	return Entity{Type: EntityPhone, Text: match, Value: phone.National, Formatted: phone.E164}, nil
+}
@@ -0,0 +1,247 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cabeçalhos padrão da assinatura HMAC
+const (
+	DefaultSignatureHeader = "X-Signature"
+	DefaultTimestampHeader = "X-Signature-Timestamp"
+)
+
+// HookSigning configura a assinatura HMAC-SHA256 das requisições do hook
+// Assinatura: "sha256=" + hex(HMAC(secret, timestamp + "." + body))
+type HookSigning struct {
+	Secret          string `json:"secret"`                     // Nome do segredo (resolvido por SecretResolver, nunca o valor)
+	Header          string `json:"header,omitempty"`           // Cabeçalho da assinatura (padrão X-Signature)
+	TimestampHeader string `json:"timestamp_header,omitempty"` // Cabeçalho do timestamp unix (padrão X-Signature-Timestamp)
+}
+
+// HookRetry configura novas tentativas em falhas de transporte e respostas 5xx
+type HookRetry struct {
+	MaxAttempts  int `json:"max_attempts"`             // Total de tentativas (inclui a primeira)
+	BackoffMs    int `json:"backoff_ms,omitempty"`     // Espera inicial (dobra a cada tentativa; padrão 100ms)
+	MaxBackoffMs int `json:"max_backoff_ms,omitempty"` // Teto da espera (padrão 2000ms)
+}
+
+// HookRequest requisição montada pelo validator para o transporte
+type HookRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// HookReply resposta bruta do transporte
+type HookReply struct {
+	Status int
+	Body   []byte
+}
+
+// HookTransport envia requisições de hook (substituível em testes por um fake em processo)
+type HookTransport interface {
+	Send(ctx context.Context, req HookRequest) (HookReply, error)
+}
+
+// SecretResolver resolve o nome de um segredo para o seu valor
+type SecretResolver func(name string) (string, bool)
+
+// HTTPHookTransport transporte HTTP padrão
+type HTTPHookTransport struct{ client *http.Client }
+
+// NewHTTPHookTransport cria transporte HTTP (client nil usa timeout de 5s)
+func NewHTTPHookTransport(client *http.Client) *HTTPHookTransport {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &HTTPHookTransport{client: client}
+}
+
+// NewMTLSHookTransport cria transporte HTTP com certificado de cliente (mTLS)
+// caFile opcional: CA para validar o servidor do hook (vazio usa as CAs do sistema)
+func NewMTLSHookTransport(certFile, keyFile, caFile string) (*HTTPHookTransport, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("hook mtls: load client certificate: %w", err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("hook mtls: read ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("hook mtls: invalid ca certificate")
+		}
+		cfg.RootCAs = pool
+	}
+
+	return NewHTTPHookTransport(&http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: cfg},
+	}), nil
+}
+
+// Send executa a requisição HTTP
+func (t *HTTPHookTransport) Send(ctx context.Context, r HookRequest) (HookReply, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return HookReply{}, err
+	}
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return HookReply{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return HookReply{}, err
+	}
+	return HookReply{Status: resp.StatusCode, Body: body}, nil
+}
+
+// SignHookPayload calcula a assinatura HMAC de um payload (exposto para o lado receptor verificar)
+func SignHookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// callHook assina e envia a requisição com retry; retorna a resposta decodificada
+func (v *Validator) callHook(ctx context.Context, mode *HookMode, method string, payload []byte) (*HookResponse, error) {
+	headers := map[string]string{"Content-Type": "application/json"}
+	for k, val := range mode.Headers {
+		headers[k] = val
+	}
+
+	attempts, backoff, maxBackoff := 1, 100*time.Millisecond, 2*time.Second
+	if r := mode.Retry; r != nil {
+		if r.MaxAttempts > 1 {
+			attempts = r.MaxAttempts
+		}
+		if r.BackoffMs > 0 {
+			backoff = time.Duration(r.BackoffMs) * time.Millisecond
+		}
+		if r.MaxBackoffMs > 0 {
+			maxBackoff = time.Duration(r.MaxBackoffMs) * time.Millisecond
+		}
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("hook retry aborted: %w (last error: %v)", ctx.Err(), lastErr)
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+
+		// Assinatura é recalculada a cada tentativa (timestamp atual)
+		if mode.Signing != nil {
+			if err := v.signHook(mode.Signing, headers, payload); err != nil {
+				return nil, err
+			}
+		}
+
+		reply, err := v.transport.Send(ctx, HookRequest{Method: method, URL: mode.URL, Headers: headers, Body: payload})
+		switch {
+		case err != nil:
+			lastErr = fmt.Errorf("hook request failed: %w", err)
+			continue
+		case reply.Status >= 500:
+			lastErr = fmt.Errorf("hook returned status %d", reply.Status)
+			continue
+		case reply.Status < 200 || reply.Status > 299:
+			return nil, fmt.Errorf("hook returned status %d", reply.Status) // 4xx não é retentado
+		}
+
+		var hookResp HookResponse
+		if err := json.Unmarshal(reply.Body, &hookResp); err != nil {
+			return nil, fmt.Errorf("invalid hook response: %w", err)
+		}
+		return &hookResp, nil
+	}
+
+	return nil, lastErr
+}
+
+// signHook adiciona os cabeçalhos de assinatura HMAC
+func (v *Validator) signHook(s *HookSigning, headers map[string]string, payload []byte) error {
+	if v.secrets == nil {
+		return errors.New("hook signing requires a secret resolver")
+	}
+	secret, ok := v.secrets(s.Secret)
+	if !ok || secret == "" {
+		return fmt.Errorf("hook signing secret not found: %s", s.Secret)
+	}
+
+	header, tsHeader := s.Header, s.TimestampHeader
+	if header == "" {
+		header = DefaultSignatureHeader
+	}
+	if tsHeader == "" {
+		tsHeader = DefaultTimestampHeader
+	}
+
+	ts := v.clock().Unix()
+	headers[tsHeader] = strconv.FormatInt(ts, 10)
+	headers[header] = SignHookPayload(secret, ts, payload)
+	return nil
+}
+
+// hookVariables filtra as variáveis enviadas ao hook pela allow-list (caminhos com ponto)
+// Sem allow-list, envia todas as variáveis (compatibilidade)
+func (v *Validator) hookVariables(allow []string) map[string]interface{} {
+	if len(allow) == 0 {
+		return v.variables
+	}
+
+	out := make(map[string]interface{})
+	for _, path := range allow {
+		value := v.getFieldValue(path)
+		if value == nil {
+			continue
+		}
+		parts := strings.Split(path, ".")
+		current := out
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = value
+	}
+	return out
+}
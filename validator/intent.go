@@ -0,0 +1,228 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Variáveis de contexto preenchidas pelo modo intent (Decision.Context)
+const (
+	ContextIntent           = "intent"            // Intent de maior confiança
+	ContextIntentConfidence = "intent_confidence" // Confiança do intent principal (0-1)
+	ContextIntents          = "intents"           // Top-N: [{intent, confidence}]
+)
+
+// DefaultIntentTopN quantidade padrão de intents expostos no contexto
+const DefaultIntentTopN = 3
+
+// ErrNoClassifier retornado quando uma rota usa intent sem classificador configurado
+var ErrNoClassifier = errors.New("intent mode requires a classifier (see WithClassifier)")
+
+// IntentMode valida classificando o texto em intents
+// A rota bate quando o intent de maior confiança está em Intents e atinge MinConfidence
+type IntentMode struct {
+	Field         string   `json:"field"`           // Campo com o texto (ex: "context.user_text")
+	Intents       []string `json:"intents"`         // Intents aceitos pela rota
+	MinConfidence float64  `json:"min_confidence"`  // Confiança mínima (0-1)
+	TopN          int      `json:"top_n,omitempty"` // Intents expostos no contexto (padrão 3)
+}
+
+// IntentScore intent com a confiança atribuída pelo classificador
+type IntentScore struct {
+	Intent     string  `json:"intent"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Classifier classifica um texto em intents, em ordem decrescente de confiança
+type Classifier interface {
+	Classify(ctx context.Context, text string) ([]IntentScore, error)
+}
+
+// TFIDFClassifier classificador local treinado com frases de exemplo por intent (ex: DesignDoc.Intents)
+// Usa similaridade de cosseno entre vetores TF-IDF; frase idêntica a um exemplo tem confiança 1
+type TFIDFClassifier struct {
+	idf      map[string]float64
+	vectors  map[string]map[string]float64 // intent -> vetor normalizado
+	examples map[string]string             // exemplo normalizado -> intent
+}
+
+// NewTFIDFClassifier treina o classificador com exemplos por intent
+func NewTFIDFClassifier(examples map[string][]string) *TFIDFClassifier {
+	c := &TFIDFClassifier{
+		idf:      make(map[string]float64),
+		vectors:  make(map[string]map[string]float64, len(examples)),
+		examples: make(map[string]string),
+	}
+
+	// Um "documento" por intent com todos os exemplos
+	docs := make(map[string]map[string]float64, len(examples))
+	df := make(map[string]int)
+	for intent, phrases := range examples {
+		tf := make(map[string]float64)
+		for _, p := range phrases {
+			tokens := tokenize(p)
+			c.examples[strings.Join(tokens, " ")] = intent
+			for _, t := range tokens {
+				tf[t]++
+			}
+		}
+		for t := range tf {
+			df[t]++
+		}
+		docs[intent] = tf
+	}
+
+	n := float64(len(examples))
+	for t, d := range df {
+		c.idf[t] = math.Log((1+n)/(1+float64(d))) + 1
+	}
+	for intent, tf := range docs {
+		c.vectors[intent] = c.weigh(tf)
+	}
+
+	return c
+}
+
+// Classify retorna todos os intents com confiança > 0, ordenados
+func (c *TFIDFClassifier) Classify(_ context.Context, text string) ([]IntentScore, error) {
+	tokens := tokenize(text)
+	if intent, ok := c.examples[strings.Join(tokens, " ")]; ok && len(tokens) > 0 {
+		scores := []IntentScore{{Intent: intent, Confidence: 1}}
+		for other, vec := range c.vectors {
+			if other != intent {
+				if s := cosine(c.query(tokens), vec); s > 0 {
+					scores = append(scores, IntentScore{Intent: other, Confidence: s})
+				}
+			}
+		}
+		sortScores(scores)
+		return scores, nil
+	}
+
+	q := c.query(tokens)
+	scores := make([]IntentScore, 0, len(c.vectors))
+	for intent, vec := range c.vectors {
+		if s := cosine(q, vec); s > 0 {
+			scores = append(scores, IntentScore{Intent: intent, Confidence: s})
+		}
+	}
+	sortScores(scores)
+	return scores, nil
+}
+
+// query monta o vetor TF-IDF de uma frase (termos desconhecidos são ignorados)
+func (c *TFIDFClassifier) query(tokens []string) map[string]float64 {
+	tf := make(map[string]float64, len(tokens))
+	for _, t := range tokens {
+		if _, known := c.idf[t]; known {
+			tf[t]++
+		}
+	}
+	return c.weigh(tf)
+}
+
+// weigh aplica IDF e normaliza o vetor (norma L2 = 1)
+func (c *TFIDFClassifier) weigh(tf map[string]float64) map[string]float64 {
+	vec := make(map[string]float64, len(tf))
+	var norm2 float64
+	for t, f := range tf {
+		w := f * c.idf[t]
+		vec[t] = w
+		norm2 += w * w
+	}
+	if norm2 == 0 {
+		return vec
+	}
+	n := math.Sqrt(norm2)
+	for t := range vec {
+		vec[t] /= n
+	}
+	return vec
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot float64
+	for t, w := range a {
+		dot += w * b[t]
+	}
+	return math.Min(dot, 1)
+}
+
+func sortScores(scores []IntentScore) {
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Confidence != scores[j].Confidence {
+			return scores[i].Confidence > scores[j].Confidence
+		}
+		return scores[i].Intent < scores[j].Intent
+	})
+}
+
+// intentStopwords palavras sem valor para classificação (pt/es/en)
+var intentStopwords = map[string]bool{
+	"a": true, "o": true, "e": true, "de": true, "do": true, "da": true, "um": true, "uma": true,
+	"que": true, "para": true, "por": true, "com": true, "em": true, "no": true, "na": true,
+	"eu": true, "me": true, "meu": true, "minha": true, "el": true, "la": true, "y": true,
+	"the": true, "to": true, "i": true, "my": true, "an": true, "of": true,
+}
+
+// tokenize normaliza (minúsculas, sem acentos) e separa palavras, removendo stopwords
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(foldText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if !intentStopwords[f] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// evaluateIntent classifica o campo e verifica se o intent principal é aceito pela rota
+func (v *Validator) evaluateIntent(ctx context.Context, mode *IntentMode) (bool, []IntentScore, error) {
+	if v.classifier == nil {
+		return false, nil, ErrNoClassifier
+	}
+	text, ok := v.fieldText(mode.Field)
+	if !ok {
+		return false, nil, nil
+	}
+
+	scores, err := v.classifier.Classify(ctx, text)
+	if err != nil {
+		return false, nil, err
+	}
+
+	topN := mode.TopN
+	if topN <= 0 {
+		topN = DefaultIntentTopN
+	}
+	if len(scores) > topN {
+		scores = scores[:topN]
+	}
+
+	if len(scores) == 0 || scores[0].Confidence < mode.MinConfidence {
+		return false, scores, nil
+	}
+	for _, accepted := range mode.Intents {
+		if accepted == scores[0].Intent {
+			return true, scores, nil
+		}
+	}
+	return false, scores, nil
+}
+
+// intentContext monta as variáveis de contexto expostas ao fluxo
+func intentContext(scores []IntentScore) map[string]any {
+	ctx := map[string]any{ContextIntents: scores, ContextIntent: "", ContextIntentConfidence: 0.0}
+	if len(scores) > 0 {
+		ctx[ContextIntent] = scores[0].Intent
+		ctx[ContextIntentConfidence] = scores[0].Confidence
+	}
+	return ctx
+}
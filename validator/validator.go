@@ -4,13 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
+	"github.com/AgendoCerto/lib-bot/persistence"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
@@ -42,16 +43,18 @@ type Normalize struct {
 // Route representa uma rota de validação com múltiplos modos
 type Route struct {
 	Go    string `json:"go"`    // Nome do output quando esta rota bater
-	Modes Modes  `json:"modes"` // Modos de validação (regex, tags, rules, expr, hook)
+	Modes Modes  `json:"modes"` // Modos de validação (regex, tags, rules, expr, hook, intent, entity)
 }
 
 // Modes agrupa os diferentes modos de validação
 type Modes struct {
-	Regex []RegexMode `json:"regex,omitempty"`
-	Tags  []TagsMode  `json:"tags,omitempty"`
-	Rules *RulesMode  `json:"rules,omitempty"`
-	Expr  string      `json:"expr,omitempty"`
-	Hook  *HookMode   `json:"hook,omitempty"`
+	Regex  []RegexMode  `json:"regex,omitempty"`
+	Tags   []TagsMode   `json:"tags,omitempty"`
+	Rules  *RulesMode   `json:"rules,omitempty"`
+	Expr   string       `json:"expr,omitempty"`
+	Hook   *HookMode    `json:"hook,omitempty"`
+	Intent *IntentMode  `json:"intent,omitempty"`
+	Entity []EntityMode `json:"entity,omitempty"`
 }
 
 // RegexMode valida usando expressões regulares
@@ -89,9 +92,14 @@ type HookMode struct {
 	Headers   map[string]string `json:"headers,omitempty"`
 	TimeoutMs int               `json:"timeout_ms"`
 	CacheTTLs int               `json:"cache_ttl_s,omitempty"`
+	Variables []string          `json:"variables,omitempty"` // Allow-list de variáveis enviadas (ex: "context.user_text"); vazio envia todas
+	Signing   *HookSigning      `json:"signing,omitempty"`   // Assinatura HMAC com timestamp
+	Retry     *HookRetry        `json:"retry,omitempty"`     // Retry com backoff em 5xx/falhas de transporte
 }
 
 // HookResponse resposta esperada do hook
+// Route (opcional) sobrescreve o output da rota, mesmo com valid=false
+// Message (opcional) fica disponível em Validator.Message para o runtime enviar ao usuário
 type HookResponse struct {
 	Valid   bool   `json:"valid"`
 	Message string `json:"message,omitempty"`
@@ -100,15 +108,21 @@ type HookResponse struct {
 
 // Validator implementa a lógica de validação 2.0
 type Validator struct {
-	config    *Config
-	variables map[string]interface{}
-	client    *http.Client
-	cache     map[string]*cacheEntry
+	config     *Config
+	variables  map[string]interface{}
+	transport  HookTransport
+	secrets    SecretResolver
+	cache      *HookCache            // Cache compartilhado de respostas de hook
+	classifier Classifier            // Classificador do modo intent
+	store      persistence.KeyWriter // Destino das entidades extraídas (modo entity)
+	now        Clock                 // Referência para datas relativas e timestamp das assinaturas (nil usa time.Now)
+	last       *lastMessage          // Mensagem retornada pelo último hook avaliado
 }
 
-type cacheEntry struct {
-	response  *HookResponse
-	expiresAt time.Time
+// lastMessage guarda a mensagem do último hook com proteção para chamadas concorrentes
+type lastMessage struct {
+	mu   sync.Mutex
+	text string
 }
 
 // NewValidator cria novo validador
@@ -116,113 +130,94 @@ func NewValidator(config *Config, variables map[string]interface{}) *Validator {
 	return &Validator{
 		config:    config,
 		variables: variables,
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
-		cache: make(map[string]*cacheEntry),
+		transport: NewHTTPHookTransport(nil),
+		cache:     DefaultHookCache(),
+		last:      &lastMessage{},
 	}
 }
 
-// Validate executa a validação e retorna o output apropriado
-func (v *Validator) Validate(ctx context.Context) (string, error) {
-	if !v.config.Enabled {
-		return v.config.DefaultOutput, nil
-	}
-
-	// Avaliar rotas na ordem
-	for _, route := range v.config.Routes {
-		matched, err := v.evaluateRoute(ctx, route)
-		if err != nil {
-			return "", fmt.Errorf("error evaluating route %s: %w", route.Go, err)
-		}
-		if matched {
-			return route.Go, nil
-		}
-	}
+// WithClassifier define o classificador usado pelo modo intent
+func (v *Validator) WithClassifier(c Classifier) *Validator {
+	cp := *v
+	cp.classifier = c
+	cp.last = &lastMessage{}
+	return &cp
+}
 
-	return v.config.DefaultOutput, nil
+// WithKeyWriter define onde gravar as entidades extraídas com persistence habilitado
+// Sem writer, as gravações ficam apenas em Decision.Writes para o runtime aplicar
+func (v *Validator) WithKeyWriter(w persistence.KeyWriter) *Validator {
+	cp := *v
+	cp.store = w
+	cp.last = &lastMessage{}
+	return &cp
 }
 
-// evaluateRoute avalia uma rota específica
-func (v *Validator) evaluateRoute(ctx context.Context, route Route) (bool, error) {
-	modes := route.Modes
+// WithClock define a referência de tempo do modo entity ("amanhã", "sexta")
+// e do timestamp das assinaturas HMAC dos hooks
+func (v *Validator) WithClock(c Clock) *Validator {
+	cp := *v
+	cp.now = c
+	cp.last = &lastMessage{}
+	return &cp
+}
 
-	// Avaliar regex
-	if len(modes.Regex) > 0 {
-		for _, regex := range modes.Regex {
-			matched, err := v.evaluateRegex(regex)
-			if err != nil {
-				return false, err
-			}
-			if !matched {
-				return false, nil
-			}
-		}
+// clock horário atual pelo relógio configurado (time.Now por padrão)
+func (v *Validator) clock() time.Time {
+	if v.now != nil {
+		return v.now()
 	}
+	return time.Now()
+}
 
-	// Avaliar tags
-	if len(modes.Tags) > 0 {
-		for _, tags := range modes.Tags {
-			matched := v.evaluateTags(tags)
-			if !matched {
-				return false, nil
-			}
-		}
-	}
+// WithHookCache define o cache de respostas de hook (compartilhável entre validators)
+func (v *Validator) WithHookCache(c *HookCache) *Validator {
+	cp := *v
+	cp.cache = c
+	cp.last = &lastMessage{}
+	return &cp
+}
 
-	// Avaliar rules
-	if modes.Rules != nil {
-		matched, err := v.evaluateRules(modes.Rules)
-		if err != nil {
-			return false, err
-		}
-		if !matched {
-			return false, nil
-		}
-	}
+// WithHookTransport substitui o transporte dos hooks (ex: mTLS ou fake em testes)
+func (v *Validator) WithHookTransport(t HookTransport) *Validator {
+	cp := *v
+	cp.transport = t
+	cp.last = &lastMessage{}
+	return &cp
+}
 
-	// Avaliar expr
-	if modes.Expr != "" {
-		matched, err := v.evaluateExpr(modes.Expr)
-		if err != nil {
-			return false, err
-		}
-		if !matched {
-			return false, nil
-		}
-	}
+// WithSecretResolver define como resolver os segredos de assinatura dos hooks
+func (v *Validator) WithSecretResolver(r SecretResolver) *Validator {
+	cp := *v
+	cp.secrets = r
+	cp.last = &lastMessage{}
+	return &cp
+}
 
-	// Avaliar hook
-	if modes.Hook != nil {
-		matched, err := v.evaluateHook(ctx, modes.Hook)
-		if err != nil {
-			return false, err
-		}
-		if !matched {
-			return false, nil
-		}
-	}
+// Message retorna a mensagem do último hook avaliado (vazia se não houver)
+func (v *Validator) Message() string {
+	v.last.mu.Lock()
+	defer v.last.mu.Unlock()
+	return v.last.text
+}
 
-	return true, nil
+// Validate executa a validação e retorna o output apropriado
+// Wrapper de Decide para quem só precisa do nome do output
+func (v *Validator) Validate(ctx context.Context) (string, error) {
+	d, err := v.Decide(ctx)
+	if err != nil {
+		return "", err
+	}
+	return d.Output, nil
 }
 
 // evaluateRegex avalia modo regex
 func (v *Validator) evaluateRegex(mode RegexMode) (bool, error) {
-	value := v.getFieldValue(mode.Field)
-	if value == nil {
-		return false, nil
-	}
-
-	text, ok := value.(string)
+	text, ok := v.fieldText(mode.Field)
 	if !ok {
 		return false, nil
 	}
 
-	// Normalizar se configurado
-	if v.config.Normalize != nil {
-		text = v.normalize(text)
-	}
-
 	// Compilar regex com flags
 	pattern := mode.Pattern
 	if strings.Contains(mode.Flags, "i") {
@@ -260,20 +255,12 @@ func (v *Validator) evaluateRegex(mode RegexMode) (bool, error) {
 }
 
 // evaluateTags avalia modo tags
-func (v *Validator) evaluateTags(mode TagsMode) bool {
-	value := v.getFieldValue(mode.Field)
-	if value == nil {
-		return false
-	}
-
-	text, ok := value.(string)
+// Retorna o termo que bateu (ou o mais próximo, no fuzzy) e o score de similaridade
+// (só no fuzzy; as demais estratégias retornam score 0)
+func (v *Validator) evaluateTags(mode TagsMode) (bool, string, float64) {
+	text, ok := v.fieldText(mode.Field)
 	if !ok {
-		return false
-	}
-
-	// Normalizar
-	if v.config.Normalize != nil {
-		text = v.normalize(text)
+		return false, "", 0
 	}
 
 	// Normalizar matches também
@@ -287,6 +274,7 @@ func (v *Validator) evaluateTags(mode TagsMode) bool {
 	}
 
 	// Aplicar estratégia
+	bestTerm, bestScore := "", 0.0
 	for _, match := range normalizedMatches {
 		matched := false
 		switch mode.Strategy {
@@ -300,17 +288,24 @@ func (v *Validator) evaluateTags(mode TagsMode) bool {
 			matched = strings.HasSuffix(text, match)
 		case "fuzzy":
 			similarity := v.fuzzyMatch(text, match)
+			if similarity > bestScore {
+				bestTerm, bestScore = match, similarity
+			}
 			matched = similarity >= mode.Threshold
+			if matched {
+				return true, match, similarity
+			}
+			continue
 		default:
 			matched = strings.Contains(text, match)
 		}
 
 		if matched {
-			return true
+			return true, match, 0
 		}
 	}
 
-	return false
+	return false, bestTerm, bestScore
 }
 
 // evaluateRules avalia modo rules
@@ -400,17 +395,26 @@ func (v *Validator) evaluateExpr(expr string) (bool, error) {
 }
 
 // evaluateHook avalia chamando hook externo
-func (v *Validator) evaluateHook(ctx context.Context, mode *HookMode) (bool, error) {
-	// Verificar cache
-	cacheKey := mode.URL
-	if entry, found := v.cache[cacheKey]; found {
-		if time.Now().Before(entry.expiresAt) {
-			return entry.response.Valid, nil
+// O cache é indexado por método + URL + payload enviado, evitando reaproveitar o veredito de outro usuário
+func (v *Validator) evaluateHook(ctx context.Context, mode *HookMode) (*HookResponse, error) {
+	payload, err := json.Marshal(v.hookVariables(mode.Variables))
+	if err != nil {
+		return nil, err
+	}
+
+	method := mode.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	cacheKey := HookCacheKey(method, mode.URL, payload)
+	if mode.CacheTTLs > 0 && v.cache != nil {
+		if resp, found := v.cache.Get(cacheKey); found {
+			return &resp, nil
 		}
-		delete(v.cache, cacheKey)
 	}
 
-	// Preparar request
+	// Timeout cobre todas as tentativas
 	timeout := time.Duration(mode.TimeoutMs) * time.Millisecond
 	if timeout == 0 {
 		timeout = 1800 * time.Millisecond
@@ -419,53 +423,17 @@ func (v *Validator) evaluateHook(ctx context.Context, mode *HookMode) (bool, err
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Criar payload com variáveis
-	payload, err := json.Marshal(v.variables)
+	hookResp, err := v.callHook(ctx, mode, method, payload)
 	if err != nil {
-		return false, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, mode.Method, mode.URL, strings.NewReader(string(payload)))
-	if err != nil {
-		return false, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	for k, v := range mode.Headers {
-		req.Header.Set(k, v)
-	}
-
-	// Executar request
-	resp, err := v.client.Do(req)
-	if err != nil {
-		return false, fmt.Errorf("hook request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("hook returned status %d", resp.StatusCode)
-	}
-
-	// Parse response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, err
-	}
-
-	var hookResp HookResponse
-	if err := json.Unmarshal(body, &hookResp); err != nil {
-		return false, err
+		return nil, err
 	}
 
 	// Cachear se configurado
-	if mode.CacheTTLs > 0 {
-		v.cache[cacheKey] = &cacheEntry{
-			response:  &hookResp,
-			expiresAt: time.Now().Add(time.Duration(mode.CacheTTLs) * time.Second),
-		}
+	if mode.CacheTTLs > 0 && v.cache != nil {
+		v.cache.Set(cacheKey, *hookResp, time.Duration(mode.CacheTTLs)*time.Second)
 	}
 
-	return hookResp.Valid, nil
+	return hookResp, nil
 }
 
 // Helper functions
@@ -497,6 +465,18 @@ func (v *Validator) normalize(text string) string {
 	return result
 }
 
+// fieldText retorna o valor texto (normalizado) de um campo
+func (v *Validator) fieldText(field string) (string, bool) {
+	text, ok := v.getFieldValue(field).(string)
+	if !ok {
+		return "", false
+	}
+	if v.config.Normalize != nil {
+		text = v.normalize(text)
+	}
+	return text, true
+}
+
 func (v *Validator) getFieldValue(field string) interface{} {
 	parts := strings.Split(field, ".")
 	if len(parts) == 0 {
@@ -0,0 +1,581 @@
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AgendoCerto/lib-bot/validator"
+)
+
+// entityNow domingo, 18/10/2026 às 10h em São Paulo
+func entityNow(t *testing.T) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation(validator.DefaultEntityTimezone)
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	return time.Date(2026, 10, 18, 10, 0, 0, 0, loc)
+}
+
+func TestExtractEntity(t *testing.T) {
+	now := entityNow(t)
+	cases := []struct {
+		kind      validator.EntityType
+		text      string
+		value     any
+		formatted string
+		err       error
+	}{
+		// Datas
+		{kind: validator.EntityDate, text: "amanhã", value: "2026-10-19"},
+		{kind: validator.EntityDate, text: "depois de amanhã", value: "2026-10-20"},
+		{kind: validator.EntityDate, text: "pode ser sexta-feira", value: "2026-10-23"},
+		{kind: validator.EntityDate, text: "domingo que vem", value: "2026-10-25"},
+		{kind: validator.EntityDate, text: "15/10", value: "2027-10-15"},
+		{kind: validator.EntityDate, text: "20/11/26", value: "2026-11-20"},
+		{kind: validator.EntityDate, text: "25 de dezembro", value: "2026-12-25"},
+		{kind: validator.EntityDate, text: "2027-01-05", value: "2027-01-05"},
+		{kind: validator.EntityDate, text: "daqui a 3 dias", value: "2026-10-21"},
+		{kind: validator.EntityDate, text: "em duas semanas", value: "2026-11-01"},
+		{kind: validator.EntityDate, text: "dia 5", value: "2026-11-05"},
+		{kind: validator.EntityDate, text: "31/02", err: validator.ErrEntityInvalid},
+		{kind: validator.EntityDate, text: "quando der", err: validator.ErrEntityNotFound},
+
+		// Horários
+		{kind: validator.EntityTime, text: "15h", value: "15:00"},
+		{kind: validator.EntityTime, text: "às 15:30", value: "15:30"},
+		{kind: validator.EntityTime, text: "10h30", value: "10:30"},
+		{kind: validator.EntityTime, text: "3 da tarde", value: "15:00"},
+		{kind: validator.EntityTime, text: "às oito e meia", value: "08:30"},
+		{kind: validator.EntityTime, text: "9 horas", value: "09:00"},
+		{kind: validator.EntityTime, text: "meio-dia e meia", value: "12:30"},
+		{kind: validator.EntityTime, text: "meia-noite", value: "00:00"},
+		{kind: validator.EntityTime, text: "daqui a 3 dias às 15h", value: "15:00"},
+		{kind: validator.EntityTime, text: "marca para a 1 semana as 14h", value: "14:00"},
+		{kind: validator.EntityTime, text: "daqui a 3 dias", err: validator.ErrEntityNotFound},
+		{kind: validator.EntityTime, text: "às 15", err: validator.ErrEntityNotFound},
+		{kind: validator.EntityTime, text: "25h", err: validator.ErrEntityInvalid},
+		{kind: validator.EntityTime, text: "daqui a 2 horas", value: "12:00"},
+		{kind: validator.EntityTime, text: "em uma hora e meia", value: "11:30"},
+
+		// Data e horário
+		{kind: validator.EntityDateTime, text: "amanhã às 15h", value: "2026-10-19T15:00:00-03:00"},
+		{kind: validator.EntityDateTime, text: "daqui a 3 dias às 15h", value: "2026-10-21T15:00:00-03:00"},
+		{kind: validator.EntityDateTime, text: "marca para a 1 semana as 14h", value: "2026-10-18T14:00:00-03:00"},
+		{kind: validator.EntityDateTime, text: "às 9h", value: "2026-10-19T09:00:00-03:00"},
+		{kind: validator.EntityDateTime, text: "sexta às 3 da tarde", value: "2026-10-23T15:00:00-03:00"},
+		{kind: validator.EntityDateTime, text: "amanhã", err: validator.ErrEntityNotFound},
+		{kind: validator.EntityDateTime, text: "daqui a 2 horas", value: "2026-10-18T12:00:00-03:00"},
+		{kind: validator.EntityDateTime, text: "a 15 horas", value: "2026-10-19T01:00:00-03:00"},
+
+		// Números
+		{kind: validator.EntityNumber, text: "quero 42", value: 42.0, formatted: "42"},
+		{kind: validator.EntityNumber, text: "1.234,5", value: 1234.5, formatted: "1234.5"},
+		{kind: validator.EntityNumber, text: "vinte e cinco", value: 25.0, formatted: "25"},
+		{kind: validator.EntityNumber, text: "mil duzentos e trinta e quatro", value: 1234.0, formatted: "1234"},
+		{kind: validator.EntityNumber, text: "nenhum", err: validator.ErrEntityNotFound},
+
+		// Dinheiro
+		{kind: validator.EntityMoney, text: "R$ 120,50", value: 120.5},
+		{kind: validator.EntityMoney, text: "1.500 reais", value: 1500.0},
+		{kind: validator.EntityMoney, text: "cem reais e cinquenta centavos", value: 100.5},
+		{kind: validator.EntityMoney, text: "R$ -5", err: validator.ErrEntityInvalid},
+		{kind: validator.EntityMoney, text: "depois eu vejo", err: validator.ErrEntityNotFound},
+
+		// Documentos
+		{kind: validator.EntityCPF, text: "meu cpf é 529.982.247-25", value: "52998224725", formatted: "529.982.247-25"},
+		{kind: validator.EntityCPF, text: "52998224725", value: "52998224725", formatted: "529.982.247-25"},
+		{kind: validator.EntityCPF, text: "111.111.111-11", err: validator.ErrEntityInvalid},
+		{kind: validator.EntityCPF, text: "529.982.247-26", err: validator.ErrEntityInvalid},
+		{kind: validator.EntityCPF, text: "não tenho", err: validator.ErrEntityNotFound},
+		{kind: validator.EntityCNPJ, text: "11.222.333/0001-81", value: "11222333000181", formatted: "11.222.333/0001-81"},
+		{kind: validator.EntityCNPJ, text: "cnpj 12.abc.345/01de-35", value: "12ABC34501DE35", formatted: "12.ABC.345/01DE-35"},
+		{kind: validator.EntityCNPJ, text: "11.222.333/0001-82", err: validator.ErrEntityInvalid},
+
+		// Telefone
+		{kind: validator.EntityPhone, text: "meu número é (11) 98765-4321", value: "11987654321", formatted: "+5511987654321"},
+		{kind: validator.EntityPhone, text: "+55 11 98765-4321", value: "11987654321", formatted: "+5511987654321"},
+		{kind: validator.EntityPhone, text: "+1 (415) 555-0123", value: "4155550123", formatted: "+14155550123"},
+		{kind: validator.EntityPhone, text: "sem telefone", err: validator.ErrEntityNotFound},
+	}
+
+	for _, c := range cases {
+		e, err := validator.ExtractEntity(c.kind, c.text, now)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("ExtractEntity(%s, %q) err = %v, want %v (entity %+v)", c.kind, c.text, err, c.err, e)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ExtractEntity(%s, %q) err = %v", c.kind, c.text, err)
+			continue
+		}
+		if e.Type != c.kind || e.Value != c.value {
+			t.Errorf("ExtractEntity(%s, %q) = %+v, want value %v", c.kind, c.text, e, c.value)
+		}
+		if c.formatted != "" && e.Formatted != c.formatted {
+			t.Errorf("ExtractEntity(%s, %q) formatted = %q, want %q", c.kind, c.text, e.Formatted, c.formatted)
+		}
+	}
+}
+
+func TestEntityPhoneRegion(t *testing.T) {
+	route := func(mode validator.EntityMode) *validator.Config {
+		mode.Field, mode.Type = "context.user_text", validator.EntityPhone
+		return &validator.Config{Enabled: true, Routes: []validator.Route{
+			{Go: "phone", Modes: validator.Modes{Entity: []validator.EntityMode{mode}}},
+		}}
+	}
+	vars := map[string]interface{}{"context": map[string]interface{}{"user_text": "(415) 555-0123", "region": "US"}}
+
+	cases := []struct {
+		name string
+		mode validator.EntityMode
+		want string
+	}{
+		{name: "session region", mode: validator.EntityMode{RegionField: "context.region", Region: "BR"}, want: "+14155550123"},
+		{name: "mode region", mode: validator.EntityMode{Region: "US"}, want: "+14155550123"},
+		{name: "default BR", mode: validator.EntityMode{}, want: "+554155550123"},
+	}
+	for _, c := range cases {
+		d, err := validator.NewValidator(route(c.mode), vars).Decide(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if d.Output != "phone" || len(d.Entities) != 1 || d.Entities[0].Formatted != c.want {
+			t.Errorf("%s: decision = %+v", c.name, d)
+		}
+	}
+}
+
+// fakeTransport HookTransport em processo: responde na ordem de replies e guarda as requisições
+type fakeTransport struct {
+	mu       sync.Mutex
+	replies  []validator.HookReply
+	requests []validator.HookRequest
+}
+
+func (f *fakeTransport) Send(_ context.Context, req validator.HookRequest) (validator.HookReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	req.Headers = maps.Clone(req.Headers)
+	f.requests = append(f.requests, req)
+	if len(f.replies) == 0 {
+		return validator.HookReply{}, errors.New("no reply configured")
+	}
+	reply := f.replies[0]
+	if len(f.replies) > 1 {
+		f.replies = f.replies[1:]
+	}
+	return reply, nil
+}
+
+func (f *fakeTransport) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func hookConfig(mode validator.HookMode) *validator.Config {
+	return &validator.Config{
+		Enabled:       true,
+		DefaultOutput: "invalid",
+		Routes:        []validator.Route{{Go: "valid", Modes: validator.Modes{Hook: &mode}}},
+	}
+}
+
+func hookVariables() map[string]interface{} {
+	return map[string]interface{}{
+		"context": map[string]interface{}{"user_text": "12345", "phone": "5511999990000"},
+		"profile": map[string]interface{}{"name": "Ana", "document": "52998224725"},
+	}
+}
+
+func TestHookSigning(t *testing.T) {
+	at := time.Unix(1_790_000_000, 0)
+	transport := &fakeTransport{replies: []validator.HookReply{{Status: 200, Body: []byte(`{"valid":true}`)}}}
+	v := validator.NewValidator(hookConfig(validator.HookMode{
+		URL:     "https://hooks.example/check",
+		Headers: map[string]string{"X-Tenant": "acme"},
+		Signing: &validator.HookSigning{Secret: "hook_secret", Header: "X-Hub-Signature"},
+	}), hookVariables()).
+		WithHookTransport(transport).
+		WithClock(func() time.Time { return at }).
+		WithSecretResolver(func(name string) (string, bool) { return "s3cr3t", name == "hook_secret" })
+
+	out, err := v.Validate(context.Background())
+	if err != nil || out != "valid" {
+		t.Fatalf("Validate = %q, %v", out, err)
+	}
+
+	req := transport.requests[0]
+	if req.Method != "POST" || req.Headers["X-Tenant"] != "acme" || req.Headers["Content-Type"] != "application/json" {
+		t.Errorf("request = %+v", req)
+	}
+	if ts := req.Headers[validator.DefaultTimestampHeader]; ts != "1790000000" {
+		t.Errorf("timestamp header = %q", ts)
+	}
+	if sig := req.Headers["X-Hub-Signature"]; sig != validator.SignHookPayload("s3cr3t", at.Unix(), req.Body) {
+		t.Errorf("signature = %q", sig)
+	}
+
+	// Segredo desconhecido não envia a requisição
+	_, err = v.WithSecretResolver(func(string) (string, bool) { return "", false }).Validate(context.Background())
+	if err == nil || transport.calls() != 1 {
+		t.Errorf("unknown secret err = %v, calls = %d", err, transport.calls())
+	}
+}
+
+func TestHookRetry(t *testing.T) {
+	retry := &validator.HookRetry{MaxAttempts: 3, BackoffMs: 1, MaxBackoffMs: 2}
+	cases := []struct {
+		name    string
+		replies []validator.HookReply
+		calls   int
+		output  string
+		wantErr bool
+	}{
+		{"5xx then success", []validator.HookReply{{Status: 503}, {Status: 500}, {Status: 200, Body: []byte(`{"valid":true}`)}}, 3, "valid", false},
+		{"5xx exhausts attempts", []validator.HookReply{{Status: 502}}, 3, "", true},
+		{"4xx is not retried", []validator.HookReply{{Status: 404}, {Status: 200, Body: []byte(`{"valid":true}`)}}, 1, "", true},
+		{"invalid body", []validator.HookReply{{Status: 200, Body: []byte(`nope`)}}, 1, "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			transport := &fakeTransport{replies: c.replies}
+			v := validator.NewValidator(hookConfig(validator.HookMode{URL: "https://hooks.example/check", Retry: retry}), hookVariables()).
+				WithHookTransport(transport)
+
+			out, err := v.Validate(context.Background())
+			if (err != nil) != c.wantErr || out != c.output || transport.calls() != c.calls {
+				t.Errorf("Validate = %q, %v after %d call(s)", out, err, transport.calls())
+			}
+		})
+	}
+}
+
+func TestHookVariablesAllowList(t *testing.T) {
+	transport := &fakeTransport{replies: []validator.HookReply{{Status: 200, Body: []byte(`{"valid":true}`)}}}
+	v := validator.NewValidator(hookConfig(validator.HookMode{
+		URL:       "https://hooks.example/check",
+		Variables: []string{"context.user_text", "profile.name", "profile.missing"},
+	}), hookVariables()).
+		WithHookTransport(transport)
+
+	if _, err := v.Validate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var sent map[string]map[string]any
+	if err := json.Unmarshal(transport.requests[0].Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]any{
+		"context": {"user_text": "12345"},
+		"profile": {"name": "Ana"},
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("payload = %s", transport.requests[0].Body)
+	}
+}
+
+func TestHookResponseOverrides(t *testing.T) {
+	cases := []struct {
+		body    string
+		output  string
+		message string
+		def     bool
+	}{
+		{`{"valid":false,"route":"blocked","message":"Documento bloqueado"}`, "blocked", "Documento bloqueado", false},
+		{`{"valid":true,"route":"vip"}`, "vip", "", false},
+		{`{"valid":false,"message":"Tente de novo"}`, "invalid", "Tente de novo", true},
+		{`{"valid":true}`, "valid", "", false},
+	}
+	for _, c := range cases {
+		transport := &fakeTransport{replies: []validator.HookReply{{Status: 200, Body: []byte(c.body)}}}
+		v := validator.NewValidator(hookConfig(validator.HookMode{URL: "https://hooks.example/check"}), hookVariables()).
+			WithHookTransport(transport)
+
+		d, err := v.Decide(context.Background())
+		if err != nil || d.Output != c.output || d.Default != c.def || d.Message != c.message || v.Message() != c.message {
+			t.Errorf("%s: Decide = %+v, %v (Message %q)", c.body, d, err, v.Message())
+		}
+		if !c.def && d.Mode != "hook" {
+			t.Errorf("%s: mode = %q", c.body, d.Mode)
+		}
+	}
+}
+
+// fakeClock relógio manual para o cache
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestHookCacheTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_790_000_000, 0)}
+	cache := validator.NewHookCache(10, clock.Now)
+
+	cache.Set("a", validator.HookResponse{Valid: true}, time.Minute)
+	cache.Set("ignored", validator.HookResponse{Valid: true}, 0)
+	if resp, ok := cache.Get("a"); !ok || !resp.Valid {
+		t.Fatalf("Get(a) = %+v, %v", resp, ok)
+	}
+
+	clock.Advance(59 * time.Second)
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("entry expired before its ttl")
+	}
+	clock.Advance(time.Second)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("entry served after its ttl")
+	}
+
+	want := validator.HookCacheStats{Hits: 2, Misses: 1, Expirations: 1, Size: 0, Capacity: 10}
+	if got := cache.Stats(); got != want {
+		t.Errorf("Stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestHookCacheLRU(t *testing.T) {
+	cache := validator.NewHookCache(2, nil)
+	cache.Set("a", validator.HookResponse{Route: "a"}, time.Hour)
+	cache.Set("b", validator.HookResponse{Route: "b"}, time.Hour)
+	cache.Get("a")                                                // a passa a ser o mais recente
+	cache.Set("c", validator.HookResponse{Route: "c"}, time.Hour) // remove b
+	cache.Set("a", validator.HookResponse{Route: "a2"}, time.Hour)
+	cache.Set("d", validator.HookResponse{Route: "d"}, time.Hour) // remove c
+
+	for key, want := range map[string]string{"a": "a2", "b": "", "c": "", "d": "d"} {
+		resp, ok := cache.Get(key)
+		if ok != (want != "") || resp.Route != want {
+			t.Errorf("Get(%s) = %+v, %v", key, resp, ok)
+		}
+	}
+
+	want := validator.HookCacheStats{Hits: 3, Misses: 2, Evictions: 2, Size: 2, Capacity: 2}
+	if got := cache.Stats(); got != want {
+		t.Errorf("Stats = %+v, want %+v", got, want)
+	}
+
+	cache.Purge()
+	if got := cache.Stats(); got.Size != 0 || got.Hits != 3 {
+		t.Errorf("Stats after Purge = %+v", got)
+	}
+}
+
+func TestHookCacheConcurrent(t *testing.T) {
+	cache := validator.NewHookCache(64, nil)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := validator.HookCacheKey("POST", "https://hooks.example", []byte{byte(g), byte(i % 100)})
+				cache.Set(key, validator.HookResponse{Valid: i%2 == 0}, time.Minute)
+				cache.Get(key)
+				cache.Stats()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	s := cache.Stats()
+	if s.Size > 64 || s.Hits+s.Misses != 8*500 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestHookCachePayloadIsolation(t *testing.T) {
+	transport := &fakeTransport{replies: []validator.HookReply{
+		{Status: 200, Body: []byte(`{"valid":true}`)},
+		{Status: 200, Body: []byte(`{"valid":false,"route":"blocked"}`)},
+	}}
+	cache := validator.NewHookCache(10, nil)
+	mode := validator.HookMode{URL: "https://hooks.example/check", CacheTTLs: 60, Variables: []string{"context.user_text"}}
+	decide := func(text string) string {
+		t.Helper()
+		vars := map[string]interface{}{"context": map[string]interface{}{"user_text": text}}
+		out, err := validator.NewValidator(hookConfig(mode), vars).
+			WithHookTransport(transport).
+			WithHookCache(cache).
+			Validate(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	if got := decide("52998224725"); got != "valid" {
+		t.Errorf("first payload = %q", got)
+	}
+	if got := decide("11111111111"); got != "blocked" {
+		t.Errorf("second payload reused the first verdict: %q", got)
+	}
+	if got := decide("52998224725"); got != "valid" || transport.calls() != 2 {
+		t.Errorf("cached payload = %q after %d call(s)", got, transport.calls())
+	}
+	if s := cache.Stats(); s.Hits != 1 || s.Size != 2 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestDecideTrace(t *testing.T) {
+	cfg := &validator.Config{
+		Enabled:       true,
+		Normalize:     &validator.Normalize{Trim: true, Lower: true},
+		DefaultOutput: "unknown",
+		Routes: []validator.Route{
+			{Go: "number", Modes: validator.Modes{Regex: []validator.RegexMode{{Field: "context.user_text", Pattern: `^\d+$`}}}},
+			{Go: "yes", Modes: validator.Modes{Tags: []validator.TagsMode{{Field: "context.user_text", Match: []string{"sim", "s"}, Strategy: "exact"}}}},
+			{Go: "cancel", Modes: validator.Modes{Tags: []validator.TagsMode{{Field: "context.user_text", Match: []string{"cancelar"}, Strategy: "fuzzy", Threshold: 0.8}}}},
+		},
+	}
+	decide := func(text string) validator.Decision {
+		t.Helper()
+		d, err := validator.NewValidator(cfg, map[string]interface{}{"context": map[string]interface{}{"user_text": text}}).Decide(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	// Estratégia exata: rastro das rotas avaliadas, sem score fuzzy
+	d := decide("  Sim ")
+	if d.Output != "yes" || d.RouteIndex != 1 || d.Mode != "tags" || d.Input != "sim" || d.Default || d.FuzzyScore != 0 {
+		t.Errorf("exact = %+v", d)
+	}
+	if len(d.Routes) != 2 || d.Routes[0].Matched || d.Routes[0].Modes[0].Pattern != `^\d+$` ||
+		!d.Routes[1].Matched || d.Routes[1].Modes[0].Term != "sim" || d.Routes[1].Modes[0].Score != 0 {
+		t.Errorf("exact routes = %+v", d.Routes)
+	}
+
+	// Fuzzy: score da similaridade
+	d = decide("cancelr")
+	if d.Output != "cancel" || d.RouteIndex != 2 || d.FuzzyScore != 0.875 || d.Routes[2].Modes[0].Term != "cancelar" {
+		t.Errorf("fuzzy = %+v", d)
+	}
+
+	// Nenhuma rota: default com o termo fuzzy mais próximo no rastro
+	d = decide("talvez")
+	closest := d.Routes[2].Modes[0]
+	if d.Output != "unknown" || !d.Default || d.RouteIndex != -1 || len(d.Routes) != 3 ||
+		closest.Matched || closest.Term != "cancelar" || closest.Score <= 0 || d.FuzzyScore != closest.Score {
+		t.Errorf("default = %+v", d)
+	}
+
+	// Desabilitado: default direto, sem rastro
+	off := *cfg
+	off.Enabled = false
+	if d, _ := validator.NewValidator(&off, nil).Decide(context.Background()); !d.Default || d.Output != "unknown" || d.Routes != nil {
+		t.Errorf("disabled = %+v", d)
+	}
+}
+
+func intentClassifier() *validator.TFIDFClassifier {
+	return validator.NewTFIDFClassifier(map[string][]string{
+		"agendar":  {"quero marcar um horário", "agendar consulta", "tem vaga amanhã"},
+		"cancelar": {"cancelar meu horário", "não vou poder ir", "desmarcar a consulta"},
+		"preco":    {"quanto custa", "qual o valor da consulta"},
+	})
+}
+
+func TestTFIDFClassifier(t *testing.T) {
+	c := intentClassifier()
+	cases := []struct {
+		text string
+		top  string
+	}{
+		{"Agendar consulta!", "agendar"},
+		{"queria desmarcar o horário", "cancelar"},
+		{"Quanto custa a consulta?", "preco"},
+		{"tem vaga pra amanhã?", "agendar"},
+		{"bom dia", ""},
+	}
+	for _, tc := range cases {
+		scores, err := c.Classify(context.Background(), tc.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.top == "" {
+			if len(scores) != 0 {
+				t.Errorf("Classify(%q) = %+v, want none", tc.text, scores)
+			}
+			continue
+		}
+		if len(scores) == 0 || scores[0].Intent != tc.top {
+			t.Errorf("Classify(%q) = %+v, want %s", tc.text, scores, tc.top)
+			continue
+		}
+		for i := 1; i < len(scores); i++ {
+			if scores[i].Confidence > scores[i-1].Confidence || scores[i].Confidence <= 0 {
+				t.Errorf("Classify(%q) not sorted: %+v", tc.text, scores)
+			}
+		}
+	}
+
+	// Frase idêntica a um exemplo (após normalização) tem confiança 1
+	if scores, _ := c.Classify(context.Background(), "AGENDAR   consulta"); scores[0].Confidence != 1 {
+		t.Errorf("exact example confidence = %+v", scores)
+	}
+}
+
+func TestDecideIntent(t *testing.T) {
+	cfg := &validator.Config{
+		Enabled:       true,
+		DefaultOutput: "unknown",
+		Routes: []validator.Route{
+			{Go: "book", Modes: validator.Modes{Intent: &validator.IntentMode{Field: "context.user_text", Intents: []string{"agendar"}, MinConfidence: 0.3, TopN: 2}}},
+			{Go: "cancel", Modes: validator.Modes{Intent: &validator.IntentMode{Field: "context.user_text", Intents: []string{"cancelar"}, MinConfidence: 0.3}}},
+		},
+	}
+	decide := func(text string) validator.Decision {
+		t.Helper()
+		d, err := validator.NewValidator(cfg, map[string]interface{}{"context": map[string]interface{}{"user_text": text}}).
+			WithClassifier(intentClassifier()).
+			Decide(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	d := decide("quero agendar uma consulta")
+	if d.Output != "book" || d.Mode != "intent" || d.Context[validator.ContextIntent] != "agendar" || len(d.Intents) > 2 {
+		t.Errorf("book = %+v", d)
+	}
+
+	// O contexto vem da primeira classificação, mesmo quando outra rota decide
+	d = decide("desmarcar")
+	if d.Output != "cancel" || d.RouteIndex != 1 || d.Context[validator.ContextIntent] != "cancelar" || d.Routes[0].Matched {
+		t.Errorf("cancel = %+v", d)
+	}
+
+	d = decide("bom dia")
+	if d.Output != "unknown" || !d.Default || d.Context[validator.ContextIntent] != "" || d.Context[validator.ContextIntentConfidence] != 0.0 {
+		t.Errorf("no intent = %+v", d)
+	}
+
+	if _, err := validator.NewValidator(cfg, map[string]interface{}{"context": map[string]interface{}{"user_text": "oi"}}).Decide(context.Background()); !errors.Is(err, validator.ErrNoClassifier) {
+		t.Errorf("no classifier err = %v", err)
+	}
+}